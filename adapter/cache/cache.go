@@ -0,0 +1,412 @@
+// Package cache wraps the task, project, label and user services with an
+// in-memory cache, so that repeated reads, such as the project list needed
+// to resolve every --project flag, are served without a request.
+//
+// Every resource has its own time to live, and mutations drop what they may
+// have changed: creating a project drops the cached project list, any task
+// change drops all cached tasks. Errors are never cached. Reads with a
+// context from Fresh skip the cache.
+//
+// Buckets, comments, attachments and relations are not cached, but their
+// services are wrapped too, as moving a task or relating it changes the
+// task.
+package cache
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"kunja/api"
+	"kunja/internal/service"
+)
+
+// TTLs are the times to live per resource; zero disables caching of that
+// resource.
+type TTLs struct {
+	Projects time.Duration `mapstructure:"projects"`
+	Users    time.Duration `mapstructure:"users"`
+	Labels   time.Duration `mapstructure:"labels"`
+	Tasks    time.Duration `mapstructure:"tasks"`
+}
+
+// DefaultTTLs keeps the slowly changing lists for minutes and tasks, which
+// other clients change all the time, only briefly.
+func DefaultTTLs() TTLs {
+	return TTLs{
+		Projects: 5 * time.Minute,
+		Users:    10 * time.Minute,
+		Labels:   5 * time.Minute,
+		Tasks:    30 * time.Second,
+	}
+}
+
+type entry struct {
+	value    interface{}
+	total    int // recorded by a task listing, see api.WithTotal
+	hasTotal bool
+	expires  time.Time
+}
+
+// Cache holds the cached values. It outlives the services it wraps, so a
+// long-running process can rebuild its services per request and still share
+// one cache. It is safe for concurrent use.
+type Cache struct {
+	ttl TTLs
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+// New returns an empty cache with the given TTLs.
+func New(ttl TTLs) *Cache {
+	return &Cache{ttl: ttl, now: time.Now, entries: map[string]entry{}}
+}
+
+// Flush drops all cached values.
+func (c *Cache) Flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = map[string]entry{}
+}
+
+// Invalidate drops the cached values whose key starts with one of the
+// prefixes.
+func (c *Cache) Invalidate(prefixes ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		for _, p := range prefixes {
+			if strings.HasPrefix(key, p) {
+				delete(c.entries, key)
+				break
+			}
+		}
+	}
+}
+
+func (c *Cache) get(key string) (entry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if ok && !c.now().Before(e.expires) {
+		delete(c.entries, key)
+		return entry{}, false
+	}
+	return e, ok
+}
+
+func (c *Cache) put(key string, ttl time.Duration, e entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e.expires = c.now().Add(ttl)
+	c.entries[key] = e
+}
+
+type freshKey struct{}
+
+// Fresh returns a context whose reads skip the cached values and go to the
+// server, e.g. to read a task that is about to be changed and saved back.
+// What they fetch is cached as usual.
+func Fresh(ctx context.Context) context.Context {
+	return context.WithValue(ctx, freshKey{}, true)
+}
+
+func isFresh(ctx context.Context) bool {
+	fresh, _ := ctx.Value(freshKey{}).(bool)
+	return fresh
+}
+
+// cached returns the value under key, calling fetch on a miss or for a
+// Fresh ctx. Slices are copied on the way out since callers sort them in
+// place. The total a task listing records with api.RecordTotal is cached
+// with it and recorded again on every hit.
+func cached[T any](ctx context.Context, c *Cache, key string, ttl time.Duration, fetch func(context.Context) (T, error), clone func(T) T) (T, error) {
+	if ttl <= 0 {
+		return fetch(ctx)
+	}
+	if e, ok := c.get(key); ok && !isFresh(ctx) {
+		if e.hasTotal {
+			api.RecordTotal(ctx, e.total)
+		}
+		return clone(e.value.(T)), nil
+	}
+	fetchCtx, total := api.WithTotal(ctx)
+	v, err := fetch(fetchCtx)
+	if err != nil {
+		return v, err
+	}
+	e := entry{value: clone(v)}
+	if e.total, e.hasTotal = total(); e.hasTotal {
+		api.RecordTotal(ctx, e.total)
+	}
+	c.put(key, ttl, e)
+	return v, nil
+}
+
+func same[T any](v T) T { return v }
+
+func cloneSlice[T any](s []T) []T {
+	if s == nil {
+		return nil
+	}
+	return append([]T(nil), s...)
+}
+
+// Service implements service.TaskService, ProjectService, LabelService and
+// UserService on top of inner implementations and a Cache.
+type Service struct {
+	cache    *Cache
+	tasks    service.TaskService
+	projects service.ProjectService
+	labels   service.LabelService
+	users    service.UserService
+}
+
+// Wrap returns the services backed by c.
+func (c *Cache) Wrap(tasks service.TaskService, projects service.ProjectService, labels service.LabelService, users service.UserService) *Service {
+	return &Service{cache: c, tasks: tasks, projects: projects, labels: labels, users: users}
+}
+
+// Compile-time assertions.
+var (
+	_ service.TaskService    = (*Service)(nil)
+	_ service.ProjectService = (*Service)(nil)
+	_ service.LabelService   = (*Service)(nil)
+	_ service.UserService    = (*Service)(nil)
+)
+
+// Key prefixes; a task change invalidates everything starting with "task".
+const (
+	keyTasks   = "tasks:"
+	keyTask    = "task:"
+	keyTaskSub = "task-" // assignees and labels of a task
+	keyProject = "project"
+	keyLabel   = "label"
+	keyUsers   = "users"
+)
+
+/* ---- TaskService ---- */
+
+func (s *Service) GetAllTasks(ctx context.Context, params api.GetAllTasksParams) ([]api.Task, error) {
+	return cached(ctx, s.cache, fmt.Sprintf("%s%+v", keyTasks, params), s.cache.ttl.Tasks, func(ctx context.Context) ([]api.Task, error) {
+		return s.tasks.GetAllTasks(ctx, params)
+	}, cloneSlice[api.Task])
+}
+
+func (s *Service) GetTask(ctx context.Context, id int) (api.Task, error) {
+	return cached(ctx, s.cache, fmt.Sprint(keyTask, id), s.cache.ttl.Tasks, func(ctx context.Context) (api.Task, error) {
+		return s.tasks.GetTask(ctx, id)
+	}, same[api.Task])
+}
+
+func (s *Service) CreateTask(ctx context.Context, projectID int, task api.Task) (api.Task, error) {
+	defer s.cache.Invalidate("task")
+	return s.tasks.CreateTask(ctx, projectID, task)
+}
+
+func (s *Service) UpdateTask(ctx context.Context, id int, task api.Task) (api.Task, error) {
+	defer s.cache.Invalidate("task")
+	return s.tasks.UpdateTask(ctx, id, task)
+}
+
+func (s *Service) DeleteTask(ctx context.Context, id int) (string, error) {
+	defer s.cache.Invalidate("task")
+	return s.tasks.DeleteTask(ctx, id)
+}
+
+func (s *Service) AssignUserToTask(ctx context.Context, taskID, userID int) (string, error) {
+	defer s.cache.Invalidate("task")
+	return s.tasks.AssignUserToTask(ctx, taskID, userID)
+}
+
+func (s *Service) GetTaskAssignees(ctx context.Context, taskID int) ([]api.User, error) {
+	return cached(ctx, s.cache, fmt.Sprint(keyTaskSub, "assignees:", taskID), s.cache.ttl.Tasks, func(ctx context.Context) ([]api.User, error) {
+		return s.tasks.GetTaskAssignees(ctx, taskID)
+	}, cloneSlice[api.User])
+}
+
+/* ---- ProjectService ---- */
+
+func (s *Service) GetAllProjects(ctx context.Context) ([]api.Project, error) {
+	return cached(ctx, s.cache, keyProject+"s", s.cache.ttl.Projects, func(ctx context.Context) ([]api.Project, error) {
+		return s.projects.GetAllProjects(ctx)
+	}, cloneSlice[api.Project])
+}
+
+func (s *Service) GetProject(ctx context.Context, id int) (api.Project, error) {
+	return cached(ctx, s.cache, fmt.Sprint(keyProject, ":", id), s.cache.ttl.Projects, func(ctx context.Context) (api.Project, error) {
+		return s.projects.GetProject(ctx, id)
+	}, same[api.Project])
+}
+
+func (s *Service) GetProjectUsers(ctx context.Context, projectID int) ([]api.UserWithRight, error) {
+	return cached(ctx, s.cache, fmt.Sprint(keyProject, "-users:", projectID), s.cache.ttl.Projects, func(ctx context.Context) ([]api.UserWithRight, error) {
+		return s.projects.GetProjectUsers(ctx, projectID)
+	}, cloneSlice[api.UserWithRight])
+}
+
+func (s *Service) CreateProject(ctx context.Context, p api.Project) (api.Project, error) {
+	defer s.cache.Invalidate(keyProject)
+	return s.projects.CreateProject(ctx, p)
+}
+
+// DeleteProject also drops the tasks, which are deleted with the project.
+func (s *Service) DeleteProject(ctx context.Context, id int) (string, error) {
+	defer s.cache.Invalidate(keyProject, "task")
+	return s.projects.DeleteProject(ctx, id)
+}
+
+/* ---- LabelService ---- */
+
+func (s *Service) GetAllLabels(ctx context.Context) ([]api.Label, error) {
+	return cached(ctx, s.cache, keyLabel+"s", s.cache.ttl.Labels, func(ctx context.Context) ([]api.Label, error) {
+		return s.labels.GetAllLabels(ctx)
+	}, cloneSlice[api.Label])
+}
+
+func (s *Service) GetLabel(ctx context.Context, id int) (api.Label, error) {
+	return cached(ctx, s.cache, fmt.Sprint(keyLabel, ":", id), s.cache.ttl.Labels, func(ctx context.Context) (api.Label, error) {
+		return s.labels.GetLabel(ctx, id)
+	}, same[api.Label])
+}
+
+func (s *Service) CreateLabel(ctx context.Context, l api.Label) (api.Label, error) {
+	defer s.cache.Invalidate(keyLabel)
+	return s.labels.CreateLabel(ctx, l)
+}
+
+// UpdateLabel and DeleteLabel also drop the tasks, which embed their labels.
+
+func (s *Service) UpdateLabel(ctx context.Context, id int, l api.Label) (api.Label, error) {
+	defer s.cache.Invalidate(keyLabel, "task")
+	return s.labels.UpdateLabel(ctx, id, l)
+}
+
+func (s *Service) DeleteLabel(ctx context.Context, id int) (string, error) {
+	defer s.cache.Invalidate(keyLabel, "task")
+	return s.labels.DeleteLabel(ctx, id)
+}
+
+func (s *Service) GetTaskLabels(ctx context.Context, taskID int) ([]api.Label, error) {
+	return cached(ctx, s.cache, fmt.Sprint(keyTaskSub, "labels:", taskID), s.cache.ttl.Tasks, func(ctx context.Context) ([]api.Label, error) {
+		return s.labels.GetTaskLabels(ctx, taskID)
+	}, cloneSlice[api.Label])
+}
+
+func (s *Service) AddLabelToTask(ctx context.Context, taskID, labelID int) (string, error) {
+	defer s.cache.Invalidate("task")
+	return s.labels.AddLabelToTask(ctx, taskID, labelID)
+}
+
+func (s *Service) RemoveLabelFromTask(ctx context.Context, taskID, labelID int) (string, error) {
+	defer s.cache.Invalidate("task")
+	return s.labels.RemoveLabelFromTask(ctx, taskID, labelID)
+}
+
+/* ---- UserService ---- */
+
+func (s *Service) GetAllUsers(ctx context.Context) ([]api.User, error) {
+	return cached(ctx, s.cache, keyUsers, s.cache.ttl.Users, func(ctx context.Context) ([]api.User, error) {
+		return s.users.GetAllUsers(ctx)
+	}, cloneSlice[api.User])
+}
+
+/* ---- BucketService, CommentService, AttachmentService, RelationService ---- */
+
+// WrapBuckets returns buckets with its mutations dropping the cached tasks,
+// whose bucket they change.
+func (c *Cache) WrapBuckets(buckets service.BucketService) service.BucketService {
+	return bucketService{buckets, c}
+}
+
+// WrapComments returns comments with its mutations dropping the cached tasks.
+func (c *Cache) WrapComments(comments service.CommentService) service.CommentService {
+	return commentService{comments, c}
+}
+
+// WrapAttachments returns attachments with its mutations dropping the
+// cached tasks.
+func (c *Cache) WrapAttachments(attachments service.AttachmentService) service.AttachmentService {
+	return attachmentService{attachments, c}
+}
+
+// WrapRelations returns relations with its mutations dropping the cached
+// tasks, which embed their related tasks.
+func (c *Cache) WrapRelations(relations service.RelationService) service.RelationService {
+	return relationService{relations, c}
+}
+
+type bucketService struct {
+	service.BucketService
+	cache *Cache
+}
+
+func (s bucketService) UpdateBucket(ctx context.Context, projectID, viewID, bucketID int, b api.Bucket) (api.Bucket, error) {
+	defer s.cache.Invalidate("task")
+	return s.BucketService.UpdateBucket(ctx, projectID, viewID, bucketID, b)
+}
+
+func (s bucketService) DeleteBucket(ctx context.Context, projectID, viewID, bucketID int) (string, error) {
+	defer s.cache.Invalidate("task")
+	return s.BucketService.DeleteBucket(ctx, projectID, viewID, bucketID)
+}
+
+func (s bucketService) MoveTaskToBucket(ctx context.Context, projectID, viewID, bucketID, taskID int) (string, error) {
+	defer s.cache.Invalidate("task")
+	return s.BucketService.MoveTaskToBucket(ctx, projectID, viewID, bucketID, taskID)
+}
+
+type commentService struct {
+	service.CommentService
+	cache *Cache
+}
+
+func (s commentService) CreateTaskComment(ctx context.Context, taskID int, comment string) (api.TaskComment, error) {
+	defer s.cache.Invalidate("task")
+	return s.CommentService.CreateTaskComment(ctx, taskID, comment)
+}
+
+func (s commentService) UpdateTaskComment(ctx context.Context, taskID, commentID int, comment string) (api.TaskComment, error) {
+	defer s.cache.Invalidate("task")
+	return s.CommentService.UpdateTaskComment(ctx, taskID, commentID, comment)
+}
+
+func (s commentService) DeleteTaskComment(ctx context.Context, taskID, commentID int) (string, error) {
+	defer s.cache.Invalidate("task")
+	return s.CommentService.DeleteTaskComment(ctx, taskID, commentID)
+}
+
+type attachmentService struct {
+	service.AttachmentService
+	cache *Cache
+}
+
+func (s attachmentService) UploadTaskAttachment(ctx context.Context, taskID int, filename string, r io.Reader) (api.TaskAttachment, error) {
+	defer s.cache.Invalidate("task")
+	return s.AttachmentService.UploadTaskAttachment(ctx, taskID, filename, r)
+}
+
+func (s attachmentService) DeleteTaskAttachment(ctx context.Context, taskID, attachmentID int) (string, error) {
+	defer s.cache.Invalidate("task")
+	return s.AttachmentService.DeleteTaskAttachment(ctx, taskID, attachmentID)
+}
+
+type relationService struct {
+	service.RelationService
+	cache *Cache
+}
+
+func (s relationService) CreateTaskRelation(ctx context.Context, taskID, otherTaskID int, kind api.RelationKind) (api.TaskRelation, error) {
+	defer s.cache.Invalidate("task")
+	return s.RelationService.CreateTaskRelation(ctx, taskID, otherTaskID, kind)
+}
+
+func (s relationService) DeleteTaskRelation(ctx context.Context, taskID, otherTaskID int, kind api.RelationKind) (string, error) {
+	defer s.cache.Invalidate("task")
+	return s.RelationService.DeleteTaskRelation(ctx, taskID, otherTaskID, kind)
+}
@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"kunja/adapter/memory"
+	"kunja/api"
+	"kunja/internal/core"
+)
+
+func newTestService(t *testing.T) (*memory.Adapter, *Service, api.Task, api.Task) {
+	t.Helper()
+	ctx := context.Background()
+	mem := memory.New("demo", "demo")
+	project, err := mem.CreateProject(ctx, api.Project{Title: "Test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := mem.CreateTask(ctx, project.ID, api.Task{Title: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := mem.CreateTask(ctx, project.ID, api.Task{Title: "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return mem, New(DefaultTTLs()).Wrap(mem, mem, mem, mem), a, b
+}
+
+func TestFresh(t *testing.T) {
+	ctx := context.Background()
+	mem, svc, a, _ := newTestService(t)
+	if _, err := svc.GetTask(ctx, a.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// Changed by another client, so the cache does not know.
+	a.Title = "changed"
+	if _, err := mem.UpdateTask(ctx, a.ID, a); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := svc.GetTask(ctx, a.ID); got.Title != "a" {
+		t.Errorf("cached title = %q, want the stale %q", got.Title, "a")
+	}
+	if got, _ := svc.GetTask(Fresh(ctx), a.ID); got.Title != "changed" {
+		t.Errorf("fresh title = %q, want %q", got.Title, "changed")
+	}
+	if got, _ := svc.GetTask(ctx, a.ID); got.Title != "changed" {
+		t.Errorf("title after a fresh read = %q, want %q", got.Title, "changed")
+	}
+}
+
+func TestRelationInvalidatesTasks(t *testing.T) {
+	ctx := context.Background()
+	mem, svc, a, b := newTestService(t)
+	if got, _ := svc.GetTask(ctx, a.ID); len(got.RelatedTasks) != 0 {
+		t.Fatalf("new task has relations: %v", got.RelatedTasks)
+	}
+	relations := svc.cache.WrapRelations(mem)
+	if _, err := relations.CreateTaskRelation(ctx, a.ID, b.ID, core.RelationSubtask); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := svc.GetTask(ctx, a.ID); len(got.RelatedTasks["subtask"]) != 1 {
+		t.Errorf("related tasks after relating = %v, want one subtask", got.RelatedTasks)
+	}
+}
+
+func TestTotal(t *testing.T) {
+	_, svc, _, _ := newTestService(t)
+	params := api.GetAllTasksParams{PerPage: 1}
+	for _, hit := range []string{"miss", "hit"} {
+		ctx, total := api.WithTotal(context.Background())
+		tasks, err := svc.GetAllTasks(ctx, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n, ok := total(); len(tasks) != 1 || n != 2 || !ok {
+			t.Errorf("%s: got %d tasks of %d (recorded: %v), want 1 of 2", hit, len(tasks), n, ok)
+		}
+	}
+}
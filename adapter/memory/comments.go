@@ -0,0 +1,164 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"kunja/api"
+)
+
+var (
+	errCommentNotFound    = apiError(404, "The task comment does not exist.")
+	errAttachmentNotFound = apiError(404, "The task attachment does not exist.")
+)
+
+/* ---- CommentService ---- */
+
+func (a *Adapter) GetTaskComments(ctx context.Context, taskID int) ([]api.TaskComment, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.tasks[taskID]; !ok {
+		return nil, errTaskNotFound
+	}
+	comments := []api.TaskComment{}
+	for _, id := range sortedIDs(a.comments) {
+		if c := a.comments[id]; c.taskID == taskID {
+			comments = append(comments, c.TaskComment)
+		}
+	}
+	return comments, nil
+}
+
+func (a *Adapter) GetTaskComment(ctx context.Context, taskID, commentID int) (api.TaskComment, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	c, ok := a.comments[commentID]
+	if !ok || c.taskID != taskID {
+		return api.TaskComment{}, errCommentNotFound
+	}
+	return c.TaskComment, nil
+}
+
+func (a *Adapter) CreateTaskComment(ctx context.Context, taskID int, text string) (api.TaskComment, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.tasks[taskID]; !ok {
+		return api.TaskComment{}, errTaskNotFound
+	}
+	if strings.TrimSpace(text) == "" {
+		return api.TaskComment{}, apiError(400, "The comment cannot be empty.")
+	}
+	now := a.now()
+	c := comment{taskID: taskID, TaskComment: api.TaskComment{
+		ID:      a.nextID("comment"),
+		Comment: text,
+		Author:  a.user(),
+		Created: now,
+		Updated: now,
+	}}
+	a.comments[c.ID] = c
+	return c.TaskComment, nil
+}
+
+func (a *Adapter) UpdateTaskComment(ctx context.Context, taskID, commentID int, text string) (api.TaskComment, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	c, ok := a.comments[commentID]
+	if !ok || c.taskID != taskID {
+		return api.TaskComment{}, errCommentNotFound
+	}
+	if strings.TrimSpace(text) == "" {
+		return api.TaskComment{}, apiError(400, "The comment cannot be empty.")
+	}
+	c.Comment, c.Updated = text, a.now()
+	a.comments[commentID] = c
+	return c.TaskComment, nil
+}
+
+func (a *Adapter) DeleteTaskComment(ctx context.Context, taskID, commentID int) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	c, ok := a.comments[commentID]
+	if !ok || c.taskID != taskID {
+		return "", errCommentNotFound
+	}
+	delete(a.comments, commentID)
+	return message("Successfully deleted."), nil
+}
+
+/* ---- AttachmentService ---- */
+
+func (a *Adapter) GetTaskAttachments(ctx context.Context, taskID int) ([]api.TaskAttachment, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.tasks[taskID]; !ok {
+		return nil, errTaskNotFound
+	}
+	attachments := []api.TaskAttachment{}
+	for _, id := range sortedIDs(a.attachments) {
+		if at := a.attachments[id]; at.TaskID == taskID {
+			attachments = append(attachments, at.TaskAttachment)
+		}
+	}
+	return attachments, nil
+}
+
+// UploadTaskAttachment keeps the content in memory, so unlike the
+// Vikunja adapter it reads r completely.
+func (a *Adapter) UploadTaskAttachment(ctx context.Context, taskID int, filename string, r io.Reader) (api.TaskAttachment, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return api.TaskAttachment{}, err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.tasks[taskID]; !ok {
+		return api.TaskAttachment{}, errTaskNotFound
+	}
+	mimeType := mime.TypeByExtension(filepath.Ext(filename))
+	if mimeType == "" {
+		mimeType = http.DetectContentType(data)
+	}
+	now := a.now()
+	at := attachment{data: data, TaskAttachment: api.TaskAttachment{
+		ID:        a.nextID("attachment"),
+		TaskID:    taskID,
+		CreatedBy: a.user(),
+		File: api.File{
+			ID:      a.nextID("file"),
+			Name:    filename,
+			Mime:    mimeType,
+			Size:    int64(len(data)),
+			Created: now,
+		},
+		Created: now,
+	}}
+	a.attachments[at.ID] = at
+	return at.TaskAttachment, nil
+}
+
+func (a *Adapter) DownloadTaskAttachment(ctx context.Context, taskID, attachmentID int, w io.Writer) (int64, error) {
+	a.mu.Lock()
+	at, ok := a.attachments[attachmentID]
+	a.mu.Unlock()
+	if !ok || at.TaskID != taskID {
+		return 0, errAttachmentNotFound
+	}
+	return io.Copy(w, bytes.NewReader(at.data))
+}
+
+func (a *Adapter) DeleteTaskAttachment(ctx context.Context, taskID, attachmentID int) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	at, ok := a.attachments[attachmentID]
+	if !ok || at.TaskID != taskID {
+		return "", errAttachmentNotFound
+	}
+	delete(a.attachments, attachmentID)
+	return message("Successfully deleted."), nil
+}
@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"kunja/api"
+	"kunja/internal/core"
+)
+
+// Demo returns an adapter logged in as "demo" (password "demo") with a
+// few projects, labels, users and tasks, with due dates relative to today,
+// to try kunja without a server.
+func Demo() *Adapter {
+	a := New("demo", "demo")
+	ctx := context.Background()
+	now := a.now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 17, 0, 0, 0, now.Location())
+	day := func(n int) time.Time { return today.AddDate(0, 0, n) }
+
+	alice := a.AddUser("alice", "Alice Example", "alice")
+	bob := a.AddUser("bob", "Bob Example", "bob")
+	a.mu.Lock()
+	me := a.user()
+	a.mu.Unlock()
+
+	work, _ := a.CreateProject(ctx, api.Project{Title: "Work", Description: "Day job"})
+	website, _ := a.CreateProject(ctx, api.Project{Title: "Website", ParentProjectID: work.ID})
+	home, _ := a.CreateProject(ctx, api.Project{Title: "Home"})
+	a.mu.Lock()
+	for id, ident := range map[int]string{work.ID: "WORK", website.ID: "WEB", home.ID: "HOME"} {
+		p := a.projects[id]
+		p.Identifier = ident
+		a.projects[id] = p
+	}
+	a.mu.Unlock()
+	a.Share(work.ID, alice.ID, 1)
+	a.Share(website.ID, bob.ID, 0)
+
+	urgent, _ := a.CreateLabel(ctx, api.Label{Title: "urgent", HexColor: "e8445a"})
+	backend, _ := a.CreateLabel(ctx, api.Label{Title: "backend", HexColor: "1973ff"})
+	errand, _ := a.CreateLabel(ctx, api.Label{Title: "errand", HexColor: "4caf50"})
+
+	task := func(projectID int, t api.Task, labels ...api.Label) api.Task {
+		created, _ := a.CreateTask(ctx, projectID, t)
+		for _, l := range labels {
+			a.AddLabelToTask(ctx, created.ID, l.ID)
+		}
+		return created
+	}
+	report := task(work.ID, api.Task{Title: "Send quarterly report", Priority: 4, DueDate: day(-1)}, urgent)
+	task(work.ID, api.Task{Title: "Review pull requests", Priority: 2, DueDate: day(0), Assignees: []api.User{me}})
+	api1 := task(work.ID, api.Task{Title: "Design the sync API", Description: "Endpoints for the mobile client.", Priority: 3, DueDate: day(3), PercentDone: 0.4, Assignees: []api.User{alice}}, backend)
+	api2 := task(work.ID, api.Task{Title: "Implement the sync API", Priority: 3, DueDate: day(10)}, backend)
+	task(work.ID, api.Task{Title: "Book conference travel", DueDate: day(14), IsFavorite: true})
+	task(website.ID, api.Task{Title: "Fix broken links on the blog", Priority: 1, DueDate: day(2), Assignees: []api.User{bob}})
+	task(website.ID, api.Task{Title: "Renew TLS certificate", Priority: 5, DueDate: day(5), RepeatAfter: 90 * 24 * 3600}, urgent)
+	task(home.ID, api.Task{Title: "Buy groceries", DueDate: day(0)}, errand)
+	task(home.ID, api.Task{Title: "Water the plants", DueDate: day(1), RepeatAfter: 3 * 24 * 3600})
+	task(home.ID, api.Task{Title: "Call the plumber", Done: true}, errand)
+	task(me.DefaultProjectID, api.Task{Title: "Try kunja", Description: "Run `kunja list`, `kunja agenda` and `kunja board`."})
+
+	a.CreateTaskRelation(ctx, api2.ID, api1.ID, core.RelationBlocked)
+	a.CreateTaskComment(ctx, report.ID, "Numbers from finance are in the shared drive.")
+	a.CreateSavedFilter(ctx, api.SavedFilter{
+		Title:   "Important",
+		Filters: api.SavedFilterQuery{Filter: "done = false && priority >= 3"},
+	})
+	return a
+}
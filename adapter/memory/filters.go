@@ -0,0 +1,89 @@
+package memory
+
+import (
+	"context"
+	"strings"
+
+	"kunja/api"
+	"kunja/internal/core/filter"
+)
+
+var errFilterNotFound = apiError(404, "The saved filter does not exist.")
+
+// checkFilter validates a saved filter the way the server does before
+// storing it.
+func checkFilter(f api.SavedFilter) error {
+	if strings.TrimSpace(f.Title) == "" {
+		return apiError(400, "The saved filter title cannot be empty.")
+	}
+	if strings.TrimSpace(f.Filters.Filter) == "" {
+		return nil
+	}
+	if _, err := filter.Parse(f.Filters.Filter); err != nil {
+		return apiError(400, "Invalid filter: "+err.Error())
+	}
+	return nil
+}
+
+/* ---- FilterService ---- */
+
+func (a *Adapter) GetSavedFilters(ctx context.Context) ([]api.SavedFilter, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	filters := []api.SavedFilter{}
+	for _, id := range sortedIDs(a.filters) {
+		filters = append(filters, a.filters[id])
+	}
+	return filters, nil
+}
+
+func (a *Adapter) GetSavedFilter(ctx context.Context, id int) (api.SavedFilter, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	f, ok := a.filters[id]
+	if !ok {
+		return api.SavedFilter{}, errFilterNotFound
+	}
+	return f, nil
+}
+
+func (a *Adapter) CreateSavedFilter(ctx context.Context, f api.SavedFilter) (api.SavedFilter, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := checkFilter(f); err != nil {
+		return api.SavedFilter{}, err
+	}
+	now := a.now()
+	f.ID = a.nextID("filter")
+	f.Owner = a.user()
+	f.Created, f.Updated = now, now
+	a.filters[f.ID] = f
+	return f, nil
+}
+
+// UpdateSavedFilter takes title, description, query and favorite flag of f.
+func (a *Adapter) UpdateSavedFilter(ctx context.Context, id int, f api.SavedFilter) (api.SavedFilter, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	old, ok := a.filters[id]
+	if !ok {
+		return api.SavedFilter{}, errFilterNotFound
+	}
+	if err := checkFilter(f); err != nil {
+		return api.SavedFilter{}, err
+	}
+	old.Title, old.Description, old.Filters, old.IsFavorite = f.Title, f.Description, f.Filters, f.IsFavorite
+	old.Updated = a.now()
+	a.filters[id] = old
+	return old, nil
+}
+
+func (a *Adapter) DeleteSavedFilter(ctx context.Context, id int) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.filters[id]; !ok {
+		return "", errFilterNotFound
+	}
+	delete(a.filters, id)
+	return message("Successfully deleted."), nil
+}
@@ -0,0 +1,225 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"kunja/api"
+)
+
+var errBucketNotFound = apiError(404, "The bucket does not exist.")
+
+// view returns a kanban view of the project.
+func (a *Adapter) view(projectID, viewID int) (api.ProjectView, error) {
+	if _, ok := a.projects[projectID]; !ok {
+		return api.ProjectView{}, errProjectNotFound
+	}
+	v, ok := a.views[viewID]
+	if !ok || v.ProjectID != projectID {
+		return api.ProjectView{}, apiError(404, "The project view does not exist.")
+	}
+	if v.ViewKind != "kanban" {
+		return api.ProjectView{}, apiError(400, "The project view is not a kanban view.")
+	}
+	return v, nil
+}
+
+// bucketsOf returns the buckets of a view by position, with their task
+// counts.
+func (a *Adapter) bucketsOf(viewID int) []api.Bucket {
+	doneID := a.views[viewID].DoneBucketID
+	buckets := []api.Bucket{}
+	for _, id := range sortedIDs(a.buckets) {
+		b := a.buckets[id]
+		if b.ProjectViewID != viewID {
+			continue
+		}
+		b.IsDoneBucket = b.ID == doneID
+		b.CountTasks = 0
+		for _, t := range a.tasks {
+			if t.BucketID == b.ID {
+				b.CountTasks++
+			}
+		}
+		buckets = append(buckets, b)
+	}
+	sort.SliceStable(buckets, func(i, j int) bool { return buckets[i].Position < buckets[j].Position })
+	return buckets
+}
+
+/* ---- BucketService ---- */
+
+func (a *Adapter) GetProjectViews(ctx context.Context, projectID int) ([]api.ProjectView, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.projects[projectID]; !ok {
+		return nil, errProjectNotFound
+	}
+	views := []api.ProjectView{}
+	for _, id := range sortedIDs(a.views) {
+		if a.views[id].ProjectID == projectID {
+			views = append(views, a.views[id])
+		}
+	}
+	sort.SliceStable(views, func(i, j int) bool { return views[i].Position < views[j].Position })
+	return views, nil
+}
+
+func (a *Adapter) GetBuckets(ctx context.Context, projectID, viewID int) ([]api.Bucket, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, err := a.view(projectID, viewID); err != nil {
+		return nil, err
+	}
+	return a.bucketsOf(viewID), nil
+}
+
+func (a *Adapter) GetBucketTasks(ctx context.Context, projectID, viewID int) ([]api.Bucket, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, err := a.view(projectID, viewID); err != nil {
+		return nil, err
+	}
+	buckets := a.bucketsOf(viewID)
+	for i := range buckets {
+		for _, id := range sortedIDs(a.tasks) {
+			if a.tasks[id].BucketID == buckets[i].ID {
+				buckets[i].Tasks = append(buckets[i].Tasks, a.expand(a.tasks[id]))
+			}
+		}
+		tasks := buckets[i].Tasks
+		sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].KanbanPosition < tasks[j].KanbanPosition })
+	}
+	return buckets, nil
+}
+
+func (a *Adapter) CreateBucket(ctx context.Context, projectID, viewID int, b api.Bucket) (api.Bucket, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, err := a.view(projectID, viewID); err != nil {
+		return api.Bucket{}, err
+	}
+	if strings.TrimSpace(b.Title) == "" {
+		return api.Bucket{}, apiError(400, "The bucket title cannot be empty.")
+	}
+	if b.Position == 0 {
+		for _, other := range a.bucketsOf(viewID) {
+			if other.Position >= b.Position {
+				b.Position = other.Position + 100
+			}
+		}
+	}
+	created := api.Bucket{ID: a.nextID("bucket"), Title: b.Title, ProjectViewID: viewID, Limit: b.Limit, Position: b.Position}
+	a.buckets[created.ID] = created
+	return created, nil
+}
+
+// UpdateBucket takes title and limit of b, and its position unless zero.
+func (a *Adapter) UpdateBucket(ctx context.Context, projectID, viewID, bucketID int, b api.Bucket) (api.Bucket, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, err := a.view(projectID, viewID); err != nil {
+		return api.Bucket{}, err
+	}
+	old, ok := a.buckets[bucketID]
+	if !ok || old.ProjectViewID != viewID {
+		return api.Bucket{}, errBucketNotFound
+	}
+	if strings.TrimSpace(b.Title) == "" {
+		return api.Bucket{}, apiError(400, "The bucket title cannot be empty.")
+	}
+	old.Title, old.Limit = b.Title, b.Limit
+	if b.Position != 0 {
+		old.Position = b.Position
+	}
+	a.buckets[bucketID] = old
+	old.IsDoneBucket = bucketID == a.views[viewID].DoneBucketID
+	return old, nil
+}
+
+// DeleteBucket moves the bucket's tasks to the default bucket, or to the
+// first remaining one when the default bucket itself is deleted.
+func (a *Adapter) DeleteBucket(ctx context.Context, projectID, viewID, bucketID int) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	v, err := a.view(projectID, viewID)
+	if err != nil {
+		return "", err
+	}
+	if b, ok := a.buckets[bucketID]; !ok || b.ProjectViewID != viewID {
+		return "", errBucketNotFound
+	}
+	buckets := a.bucketsOf(viewID)
+	if len(buckets) == 1 {
+		return "", apiError(412, "You cannot remove the last bucket of this project view.")
+	}
+	delete(a.buckets, bucketID)
+	if v.DefaultBucketID == bucketID {
+		for _, b := range buckets {
+			if b.ID != bucketID {
+				v.DefaultBucketID = b.ID
+				break
+			}
+		}
+	}
+	if v.DoneBucketID == bucketID {
+		v.DoneBucketID = 0
+	}
+	a.views[viewID] = v
+	for id, t := range a.tasks {
+		if t.BucketID == bucketID {
+			t.BucketID = v.DefaultBucketID
+			a.tasks[id] = t
+		}
+	}
+	return message("Successfully deleted."), nil
+}
+
+// MoveTaskToBucket marks the task done when it is moved into the done
+// bucket and open again when it is moved out of it.
+func (a *Adapter) MoveTaskToBucket(ctx context.Context, projectID, viewID, bucketID, taskID int) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	v, err := a.view(projectID, viewID)
+	if err != nil {
+		return "", err
+	}
+	b, ok := a.buckets[bucketID]
+	if !ok || b.ProjectViewID != viewID {
+		return "", errBucketNotFound
+	}
+	t, ok := a.tasks[taskID]
+	if !ok {
+		return "", errTaskNotFound
+	}
+	if t.ProjectID != projectID {
+		return "", apiError(400, "The task does not belong to this project.")
+	}
+	if t.BucketID != bucketID && b.Limit > 0 {
+		count := 0
+		for _, other := range a.tasks {
+			if other.BucketID == bucketID {
+				count++
+			}
+		}
+		if count >= b.Limit {
+			return "", apiError(412, "The bucket limit has been exceeded.")
+		}
+	}
+
+	now := a.now()
+	switch {
+	case bucketID == v.DoneBucketID && !t.Done:
+		t.Done, t.DoneAt = true, now
+	case bucketID != v.DoneBucketID && t.Done:
+		t.Done, t.DoneAt = false, time.Time{}
+	}
+	t.BucketID = bucketID
+	t.Updated = now
+	a.tasks[taskID] = t
+	body, _ := json.Marshal(map[string]int{"task_id": taskID, "bucket_id": bucketID, "project_view_id": viewID})
+	return string(body), nil
+}
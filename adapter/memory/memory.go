@@ -0,0 +1,419 @@
+// Package memory implements all service interfaces on data held in memory,
+// with the semantics of a Vikunja server: IDs are allocated per resource,
+// every project gets list, gantt, table and kanban views, completing a task
+// moves it to the done bucket, deleting a project deletes its subprojects
+// and tasks, and task listings are filtered, sorted and paged like
+// /tasks/all, including the total they record with api.RecordTotal. Errors
+// read like the API client's, e.g. "status code: 404, message: The task does
+// not exist.".
+//
+// It backs `backend: memory` for demos, and lets commands and MCP tools be
+// exercised without a server.
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"kunja/api"
+	"kunja/internal/core"
+	"kunja/internal/service"
+)
+
+// maxPerPage is the page size cap of Vikunja's task listings.
+const maxPerPage = 50
+
+// Adapter implements the service.* interfaces in memory. It is safe for
+// concurrent use.
+type Adapter struct {
+	// Urgency scores returned tasks; nil means the default model.
+	Urgency *api.UrgencyModel
+	// Now returns the current time; nil means time.Now.
+	Now func() time.Time
+
+	mu        sync.Mutex
+	lastID    map[string]int
+	me        int // the logged in user
+	users     map[int]api.User
+	passwords map[int]string
+	projects  map[int]api.Project
+	shares    map[int][]api.UserWithRight
+	views     map[int]api.ProjectView
+	buckets   map[int]api.Bucket
+	// tasks are stored without labels, assignees and related tasks, which
+	// are kept below and filled in on every read.
+	tasks       map[int]api.Task
+	taskLabels  map[int][]int
+	assignees   map[int][]int
+	relations   []api.TaskRelation
+	labels      map[int]api.Label
+	comments    map[int]comment
+	attachments map[int]attachment
+	filters     map[int]api.SavedFilter
+}
+
+type comment struct {
+	taskID int
+	api.TaskComment
+}
+
+type attachment struct {
+	api.TaskAttachment
+	data []byte
+}
+
+// Compile-time assertions.
+var (
+	_ service.AuthService       = (*Adapter)(nil)
+	_ service.TaskService       = (*Adapter)(nil)
+	_ service.ProjectService    = (*Adapter)(nil)
+	_ service.UserService       = (*Adapter)(nil)
+	_ service.LabelService      = (*Adapter)(nil)
+	_ service.BucketService     = (*Adapter)(nil)
+	_ service.CommentService    = (*Adapter)(nil)
+	_ service.AttachmentService = (*Adapter)(nil)
+	_ service.RelationService   = (*Adapter)(nil)
+	_ service.FilterService     = (*Adapter)(nil)
+)
+
+// New returns an adapter with a single user, logged in, who owns an
+// "Inbox" project as their default project, as a freshly registered
+// Vikunja account does.
+func New(username, password string) *Adapter {
+	a := &Adapter{
+		lastID:      map[string]int{},
+		users:       map[int]api.User{},
+		passwords:   map[int]string{},
+		projects:    map[int]api.Project{},
+		shares:      map[int][]api.UserWithRight{},
+		views:       map[int]api.ProjectView{},
+		buckets:     map[int]api.Bucket{},
+		tasks:       map[int]api.Task{},
+		taskLabels:  map[int][]int{},
+		assignees:   map[int][]int{},
+		labels:      map[int]api.Label{},
+		comments:    map[int]comment{},
+		attachments: map[int]attachment{},
+		filters:     map[int]api.SavedFilter{},
+	}
+	me := a.AddUser(username, "", password)
+	a.me = me.ID
+	inbox, _ := a.createProject(api.Project{Title: "Inbox"})
+	me.DefaultProjectID = inbox.ID
+	a.users[me.ID] = me
+	return a
+}
+
+// AddUser registers another user, who can then be assigned to tasks,
+// shared projects with and logged in as.
+func (a *Adapter) AddUser(username, name, password string) api.User {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	u := api.User{ID: a.nextID("user"), Username: username, Name: name}
+	a.users[u.ID] = u
+	a.passwords[u.ID] = password
+	return u
+}
+
+// Share gives a user access to a project with the given right (0 read,
+// 1 write, 2 admin), as listed by GetProjectUsers.
+func (a *Adapter) Share(projectID, userID, right int) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.projects[projectID]; !ok {
+		return errProjectNotFound
+	}
+	u, ok := a.users[userID]
+	if !ok {
+		return errUserNotFound
+	}
+	a.shares[projectID] = append(a.shares[projectID], api.UserWithRight{ID: u.ID, Username: u.Username, Right: right})
+	return nil
+}
+
+/* ---- helpers ---- */
+
+// Error is a failed request, worded like the API client's errors. Status
+// is the HTTP status code Vikunja answers with.
+type Error struct {
+	Status  int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("status code: %d, message: %s", e.Status, e.Message)
+}
+
+func apiError(status int, msg string) error {
+	return &Error{Status: status, Message: msg}
+}
+
+var (
+	errTaskNotFound    = apiError(404, "The task does not exist.")
+	errProjectNotFound = apiError(404, "The project does not exist.")
+	errUserNotFound    = apiError(404, "The user does not exist.")
+	errLabelNotFound   = apiError(404, "The label does not exist.")
+)
+
+// message returns the JSON body Vikunja answers deletions and the like
+// with.
+func message(msg string) string {
+	b, _ := json.Marshal(map[string]string{"message": msg})
+	return string(b)
+}
+
+func (a *Adapter) now() time.Time {
+	if a.Now != nil {
+		return a.Now()
+	}
+	return time.Now()
+}
+
+// nextID allocates the next ID of a kind of resource.
+func (a *Adapter) nextID(kind string) int {
+	a.lastID[kind]++
+	return a.lastID[kind]
+}
+
+// sortedIDs returns the keys of m in ascending order.
+func sortedIDs[T any](m map[int]T) []int {
+	ids := make([]int, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func (a *Adapter) user() api.User {
+	return a.users[a.me]
+}
+
+func (a *Adapter) score(t *api.Task) {
+	if a.Urgency == nil {
+		t.CalculateUrgency()
+		return
+	}
+	t.CalculateUrgencyWith(*a.Urgency)
+}
+
+/* ---- AuthService ---- */
+
+// Login logs in as the user with the given name and password; the
+// returned token has no further use.
+func (a *Adapter) Login(ctx context.Context, username, password, totpPasscode string) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, id := range sortedIDs(a.users) {
+		if strings.EqualFold(a.users[id].Username, username) && a.passwords[id] == password {
+			a.me = id
+			return "memory-" + a.users[id].Username, nil
+		}
+	}
+	return "", apiError(412, "Wrong username or password.")
+}
+
+/* ---- UserService ---- */
+
+func (a *Adapter) GetAllUsers(ctx context.Context) ([]api.User, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	users := []api.User{}
+	for _, id := range sortedIDs(a.users) {
+		users = append(users, a.users[id])
+	}
+	return users, nil
+}
+
+/* ---- ProjectService ---- */
+
+// GetAllProjects lists the projects by position, preceded by the
+// Favorites pseudo project when a task is marked favorite and followed by
+// the saved filters, as pseudo projects with negative IDs.
+func (a *Adapter) GetAllProjects(ctx context.Context) ([]api.Project, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	projects := []api.Project{}
+	for _, t := range a.tasks {
+		if t.IsFavorite {
+			projects = append(projects, a.favorites())
+			break
+		}
+	}
+	var regular []api.Project
+	for _, id := range sortedIDs(a.projects) {
+		regular = append(regular, a.projects[id])
+	}
+	sort.SliceStable(regular, func(i, j int) bool { return regular[i].Position < regular[j].Position })
+	projects = append(projects, regular...)
+	for _, id := range sortedIDs(a.filters) {
+		projects = append(projects, filterProject(a.filters[id]))
+	}
+	return projects, nil
+}
+
+func (a *Adapter) GetProject(ctx context.Context, id int) (api.Project, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if p, ok := a.projects[id]; ok {
+		p.AncestorProjects = a.ancestors(p)
+		return p, nil
+	}
+	if id == -1 {
+		return a.favorites(), nil
+	}
+	if filterID, ok := core.SavedFilterID(id); ok {
+		if f, ok := a.filters[filterID]; ok {
+			return filterProject(f), nil
+		}
+	}
+	return api.Project{}, errProjectNotFound
+}
+
+func (a *Adapter) GetProjectUsers(ctx context.Context, projectID int) ([]api.UserWithRight, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.projects[projectID]; !ok {
+		return nil, errProjectNotFound
+	}
+	return append([]api.UserWithRight{}, a.shares[projectID]...), nil
+}
+
+// CreateProject takes title, description and parent of p, like the API
+// client sends them.
+func (a *Adapter) CreateProject(ctx context.Context, p api.Project) (api.Project, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.createProject(api.Project{Title: p.Title, Description: p.Description, ParentProjectID: p.ParentProjectID})
+}
+
+// DeleteProject deletes the project with its subprojects and all their
+// tasks.
+func (a *Adapter) DeleteProject(ctx context.Context, id int) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.projects[id]; !ok {
+		return "", errProjectNotFound
+	}
+	doomed := []int{id}
+	for i := 0; i < len(doomed); i++ {
+		if doomed[i] == a.user().DefaultProjectID {
+			return "", apiError(412, "You cannot delete your default project.")
+		}
+		for _, child := range sortedIDs(a.projects) {
+			if a.projects[child].ParentProjectID == doomed[i] {
+				doomed = append(doomed, child)
+			}
+		}
+	}
+	for _, pid := range doomed {
+		for _, tid := range sortedIDs(a.tasks) {
+			if a.tasks[tid].ProjectID == pid {
+				a.deleteTask(tid)
+			}
+		}
+		for _, vid := range sortedIDs(a.views) {
+			if a.views[vid].ProjectID != pid {
+				continue
+			}
+			for _, bid := range sortedIDs(a.buckets) {
+				if a.buckets[bid].ProjectViewID == vid {
+					delete(a.buckets, bid)
+				}
+			}
+			delete(a.views, vid)
+		}
+		delete(a.shares, pid)
+		delete(a.projects, pid)
+	}
+	return message("Successfully deleted."), nil
+}
+
+// createProject adds p, owned by the logged in user, with the four views
+// and the kanban buckets Vikunja sets up for a new project.
+func (a *Adapter) createProject(p api.Project) (api.Project, error) {
+	if strings.TrimSpace(p.Title) == "" {
+		return api.Project{}, apiError(400, "The project title cannot be empty.")
+	}
+	if p.ParentProjectID != 0 {
+		if _, ok := a.projects[p.ParentProjectID]; !ok {
+			return api.Project{}, errProjectNotFound
+		}
+	}
+	now := a.now().UTC().Format(time.RFC3339)
+	p.ID = a.nextID("project")
+	p.Owner = a.user()
+	p.Position = float64(p.ID) * 65536
+	p.Created, p.Updated = now, now
+	a.projects[p.ID] = p
+
+	for i, kind := range []string{"list", "gantt", "table", "kanban"} {
+		v := api.ProjectView{
+			ID:        a.nextID("view"),
+			Title:     strings.ToUpper(kind[:1]) + kind[1:],
+			ProjectID: p.ID,
+			ViewKind:  kind,
+			Position:  float64(i+1) * 100,
+		}
+		if kind == "kanban" {
+			for j, title := range []string{"To-Do", "Doing", "Done"} {
+				b := api.Bucket{ID: a.nextID("bucket"), Title: title, ProjectViewID: v.ID, Position: float64(j+1) * 100}
+				a.buckets[b.ID] = b
+				switch title {
+				case "To-Do":
+					v.DefaultBucketID = b.ID
+				case "Done":
+					v.DoneBucketID = b.ID
+				}
+			}
+		}
+		a.views[v.ID] = v
+	}
+	return p, nil
+}
+
+// ancestors returns the parents of p, the closest first.
+func (a *Adapter) ancestors(p api.Project) []api.Project {
+	var out []api.Project
+	for id := p.ParentProjectID; id != 0; {
+		parent, ok := a.projects[id]
+		if !ok {
+			break
+		}
+		out = append(out, parent)
+		id = parent.ParentProjectID
+	}
+	return out
+}
+
+func (a *Adapter) favorites() api.Project {
+	return api.Project{ID: -1, Title: "Favorites", Description: "This project has all tasks marked as favorites.", Owner: a.user()}
+}
+
+// filterProject returns the pseudo project a saved filter is listed as.
+func filterProject(f api.SavedFilter) api.Project {
+	return api.Project{
+		ID:          -f.ID - 1,
+		Title:       f.Title,
+		Description: f.Description,
+		IsFavorite:  f.IsFavorite,
+		Owner:       f.Owner,
+		Created:     f.Created.UTC().Format(time.RFC3339),
+		Updated:     f.Updated.UTC().Format(time.RFC3339),
+	}
+}
+
+// kanbanView returns the kanban view of a project, if it has one.
+func (a *Adapter) kanbanView(projectID int) (api.ProjectView, bool) {
+	for _, id := range sortedIDs(a.views) {
+		if v := a.views[id]; v.ProjectID == projectID && v.ViewKind == "kanban" {
+			return v, true
+		}
+	}
+	return api.ProjectView{}, false
+}
@@ -0,0 +1,523 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"kunja/api"
+	"kunja/internal/core"
+	"kunja/internal/core/filter"
+)
+
+// expand returns the stored task t as the server returns it: with its
+// labels, assignees and related tasks, and scored.
+func (a *Adapter) expand(t api.Task) api.Task {
+	for _, id := range a.taskLabels[t.ID] {
+		t.Labels = append(t.Labels, a.labels[id])
+	}
+	for _, id := range a.assignees[t.ID] {
+		t.Assignees = append(t.Assignees, a.users[id])
+	}
+	for _, r := range a.relations {
+		if r.TaskID != t.ID {
+			continue
+		}
+		if t.RelatedTasks == nil {
+			t.RelatedTasks = core.RelatedTaskMap{}
+		}
+		t.RelatedTasks[r.RelationKind] = append(t.RelatedTasks[r.RelationKind], a.tasks[r.OtherTaskID])
+	}
+	a.score(&t)
+	return t
+}
+
+// filterEnv resolves project titles and identifiers in filter queries.
+func (a *Adapter) filterEnv() filter.Env {
+	return filter.Env{Now: a.Now, ProjectID: func(ref string) (int, error) {
+		for _, id := range sortedIDs(a.projects) {
+			p := a.projects[id]
+			if strings.EqualFold(p.Title, ref) || strings.EqualFold(p.Identifier, ref) {
+				return p.ID, nil
+			}
+		}
+		return 0, fmt.Errorf("project not found: %q", ref)
+	}}
+}
+
+// writableProject returns the project tasks can be created in or moved to.
+func (a *Adapter) writableProject(id int) (api.Project, error) {
+	p, ok := a.projects[id]
+	if !ok {
+		return api.Project{}, errProjectNotFound
+	}
+	if p.IsArchived {
+		return api.Project{}, apiError(412, "This project is archived. Editing or creating new tasks is not possible.")
+	}
+	return p, nil
+}
+
+// userIDs checks that the users exist and returns their IDs.
+func (a *Adapter) userIDs(users []api.User) ([]int, error) {
+	var ids []int
+	for _, u := range users {
+		if _, ok := a.users[u.ID]; !ok {
+			return nil, errUserNotFound
+		}
+		ids = append(ids, u.ID)
+	}
+	return ids, nil
+}
+
+/* ---- TaskService ---- */
+
+// GetAllTasks pages like Vikunja: at most 50 tasks per page, also when
+// params ask for more.
+func (a *Adapter) GetAllTasks(ctx context.Context, params api.GetAllTasksParams) ([]api.Task, error) {
+	tasks, total, err := a.QueryTasks(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	api.RecordTotal(ctx, total)
+	return tasks, nil
+}
+
+// QueryTasks returns the page of tasks GetAllTasks returns together with
+// the number of matching tasks on all pages, without recording it with
+// api.RecordTotal.
+func (a *Adapter) QueryTasks(ctx context.Context, params api.GetAllTasksParams) ([]api.Task, int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	all := make([]api.Task, 0, len(a.tasks))
+	for _, id := range sortedIDs(a.tasks) {
+		all = append(all, a.expand(a.tasks[id]))
+	}
+	tasks, err := filter.Query(all, params, a.filterEnv())
+	if err != nil {
+		return nil, 0, apiError(400, err.Error())
+	}
+	perPage := params.PerPage
+	if perPage <= 0 || perPage > maxPerPage {
+		perPage = maxPerPage
+	}
+	return append([]api.Task{}, filter.Page(tasks, params.Page, perPage)...), len(tasks), nil
+}
+
+func (a *Adapter) GetTask(ctx context.Context, id int) (api.Task, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	t, ok := a.tasks[id]
+	if !ok {
+		return api.Task{}, errTaskNotFound
+	}
+	return a.expand(t), nil
+}
+
+// CreateTask stores task in the project. Assignees are taken over; labels
+// and relations are not, they have their own calls.
+func (a *Adapter) CreateTask(ctx context.Context, projectID int, task api.Task) (api.Task, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, err := a.writableProject(projectID); err != nil {
+		return api.Task{}, err
+	}
+	if strings.TrimSpace(task.Title) == "" {
+		return api.Task{}, apiError(400, "The task title cannot be empty.")
+	}
+	assignees, err := a.userIDs(task.Assignees)
+	if err != nil {
+		return api.Task{}, err
+	}
+
+	now := a.now()
+	task.ID = a.nextID("task")
+	task.ProjectID = projectID
+	task.Created, task.Updated = now, now
+	task.DoneAt = time.Time{}
+	if task.Done {
+		task.DoneAt = now
+	}
+	task.Labels, task.Assignees, task.RelatedTasks = nil, nil, nil
+	task.Urgency = 0
+	task.BucketID = 0
+	if v, ok := a.kanbanView(projectID); ok {
+		task.BucketID = v.DefaultBucketID
+		if task.Done && v.DoneBucketID != 0 {
+			task.BucketID = v.DoneBucketID
+		}
+	}
+	if task.Position == 0 {
+		task.Position = float64(task.ID) * 65536
+	}
+	a.tasks[task.ID] = task
+	if len(assignees) > 0 {
+		a.assignees[task.ID] = assignees
+	}
+	return a.expand(task), nil
+}
+
+// UpdateTask replaces the task's fields and assignees with those of task;
+// labels and relations stay. Marking a task done moves it to the done
+// bucket; a repeating task instead stays open and moves on to its next
+// dates.
+func (a *Adapter) UpdateTask(ctx context.Context, id int, task api.Task) (api.Task, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	old, ok := a.tasks[id]
+	if !ok {
+		return api.Task{}, errTaskNotFound
+	}
+	if task.ProjectID == 0 {
+		task.ProjectID = old.ProjectID
+	}
+	if _, err := a.writableProject(task.ProjectID); err != nil {
+		return api.Task{}, err
+	}
+	if strings.TrimSpace(task.Title) == "" {
+		return api.Task{}, apiError(400, "The task title cannot be empty.")
+	}
+	assignees, err := a.userIDs(task.Assignees)
+	if err != nil {
+		return api.Task{}, err
+	}
+
+	now := a.now()
+	task.ID = id
+	task.Created, task.Updated = old.Created, now
+	task.Labels, task.Assignees, task.RelatedTasks = nil, nil, nil
+	task.Urgency = 0
+	task.BucketID = old.BucketID
+	view, hasKanban := a.kanbanView(task.ProjectID)
+	if task.ProjectID != old.ProjectID {
+		task.BucketID = view.DefaultBucketID
+	}
+
+	switch {
+	case task.Done && !old.Done && (task.RepeatAfter > 0 || task.RepeatMode == repeatMonthly):
+		repeat(&task, now)
+	case task.Done && !old.Done:
+		task.DoneAt = now
+		if hasKanban && view.DoneBucketID != 0 {
+			task.BucketID = view.DoneBucketID
+		}
+	case task.Done:
+		task.DoneAt = old.DoneAt
+	default:
+		task.DoneAt = time.Time{}
+		if hasKanban && task.BucketID == view.DoneBucketID {
+			task.BucketID = view.DefaultBucketID
+		}
+	}
+	a.tasks[id] = task
+	if len(assignees) > 0 {
+		a.assignees[id] = assignees
+	} else {
+		delete(a.assignees, id)
+	}
+	return a.expand(task), nil
+}
+
+// Vikunja's repeat modes.
+const (
+	repeatDefault     = 0 // repeat_after seconds after the old dates
+	repeatMonthly     = 1 // one month after the old dates
+	repeatFromCurrent = 2 // repeat_after seconds after completion
+)
+
+// repeat moves the dates of a repeating task that was marked done to its
+// next occurrence and reopens it.
+func repeat(t *api.Task, now time.Time) {
+	step := time.Duration(t.RepeatAfter) * time.Second
+	var shift func(d time.Time) time.Time
+	switch t.RepeatMode {
+	case repeatMonthly:
+		shift = func(d time.Time) time.Time { return d.AddDate(0, 1, 0) }
+	case repeatFromCurrent:
+		base := t.DueDate
+		if base.IsZero() {
+			base = now
+		}
+		offset := now.Add(step).Sub(base)
+		shift = func(d time.Time) time.Time { return d.Add(offset) }
+	default:
+		// Add the interval at least once and until the date is in the
+		// future, so an overdue task does not come back overdue.
+		shift = func(d time.Time) time.Time {
+			for d = d.Add(step); !d.After(now); d = d.Add(step) {
+			}
+			return d
+		}
+	}
+	for _, d := range []*time.Time{&t.DueDate, &t.StartDate, &t.EndDate} {
+		if !d.IsZero() {
+			*d = shift(*d)
+		}
+	}
+	reminders := make([]api.TaskReminder, len(t.Reminders))
+	for i, r := range t.Reminders {
+		if r.RelativeTo == "" && !r.Reminder.IsZero() {
+			r.Reminder = shift(r.Reminder)
+		}
+		reminders[i] = r
+	}
+	if t.Reminders != nil {
+		t.Reminders = reminders
+	}
+	t.Done = false
+	t.DoneAt = time.Time{}
+}
+
+func (a *Adapter) DeleteTask(ctx context.Context, id int) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.tasks[id]; !ok {
+		return "", errTaskNotFound
+	}
+	a.deleteTask(id)
+	return message("Successfully deleted."), nil
+}
+
+// deleteTask removes a task with everything attached to it.
+func (a *Adapter) deleteTask(id int) {
+	delete(a.tasks, id)
+	delete(a.taskLabels, id)
+	delete(a.assignees, id)
+	kept := a.relations[:0]
+	for _, r := range a.relations {
+		if r.TaskID != id && r.OtherTaskID != id {
+			kept = append(kept, r)
+		}
+	}
+	a.relations = kept
+	for cid, c := range a.comments {
+		if c.taskID == id {
+			delete(a.comments, cid)
+		}
+	}
+	for aid, at := range a.attachments {
+		if at.TaskID == id {
+			delete(a.attachments, aid)
+		}
+	}
+}
+
+func (a *Adapter) AssignUserToTask(ctx context.Context, taskID, userID int) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.tasks[taskID]; !ok {
+		return "", errTaskNotFound
+	}
+	if _, ok := a.users[userID]; !ok {
+		return "", errUserNotFound
+	}
+	for _, id := range a.assignees[taskID] {
+		if id == userID {
+			return "", apiError(400, "This user is already assigned to that task.")
+		}
+	}
+	a.assignees[taskID] = append(a.assignees[taskID], userID)
+	b, _ := json.Marshal(map[string]interface{}{"user_id": userID, "created": a.now()})
+	return string(b), nil
+}
+
+func (a *Adapter) GetTaskAssignees(ctx context.Context, taskID int) ([]api.User, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.tasks[taskID]; !ok {
+		return nil, errTaskNotFound
+	}
+	users := []api.User{}
+	for _, id := range a.assignees[taskID] {
+		users = append(users, a.users[id])
+	}
+	return users, nil
+}
+
+/* ---- LabelService ---- */
+
+func (a *Adapter) GetAllLabels(ctx context.Context) ([]api.Label, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	labels := []api.Label{}
+	for _, id := range sortedIDs(a.labels) {
+		labels = append(labels, a.labels[id])
+	}
+	return labels, nil
+}
+
+func (a *Adapter) GetLabel(ctx context.Context, id int) (api.Label, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	l, ok := a.labels[id]
+	if !ok {
+		return api.Label{}, errLabelNotFound
+	}
+	return l, nil
+}
+
+func (a *Adapter) CreateLabel(ctx context.Context, l api.Label) (api.Label, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if strings.TrimSpace(l.Title) == "" {
+		return api.Label{}, apiError(400, "The label title cannot be empty.")
+	}
+	l.ID = a.nextID("label")
+	a.labels[l.ID] = l
+	return l, nil
+}
+
+func (a *Adapter) UpdateLabel(ctx context.Context, id int, l api.Label) (api.Label, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.labels[id]; !ok {
+		return api.Label{}, errLabelNotFound
+	}
+	if strings.TrimSpace(l.Title) == "" {
+		return api.Label{}, apiError(400, "The label title cannot be empty.")
+	}
+	l.ID = id
+	a.labels[id] = l
+	return l, nil
+}
+
+// DeleteLabel also removes the label from the tasks carrying it.
+func (a *Adapter) DeleteLabel(ctx context.Context, id int) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.labels[id]; !ok {
+		return "", errLabelNotFound
+	}
+	delete(a.labels, id)
+	for taskID, ids := range a.taskLabels {
+		a.taskLabels[taskID] = without(ids, id)
+	}
+	return message("Successfully deleted."), nil
+}
+
+func (a *Adapter) GetTaskLabels(ctx context.Context, taskID int) ([]api.Label, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.tasks[taskID]; !ok {
+		return nil, errTaskNotFound
+	}
+	labels := []api.Label{}
+	for _, id := range a.taskLabels[taskID] {
+		labels = append(labels, a.labels[id])
+	}
+	return labels, nil
+}
+
+func (a *Adapter) AddLabelToTask(ctx context.Context, taskID, labelID int) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.tasks[taskID]; !ok {
+		return "", errTaskNotFound
+	}
+	if _, ok := a.labels[labelID]; !ok {
+		return "", errLabelNotFound
+	}
+	for _, id := range a.taskLabels[taskID] {
+		if id == labelID {
+			return "", apiError(400, "The label already exists on the task.")
+		}
+	}
+	a.taskLabels[taskID] = append(a.taskLabels[taskID], labelID)
+	b, _ := json.Marshal(map[string]interface{}{"label_id": labelID, "created": a.now()})
+	return string(b), nil
+}
+
+func (a *Adapter) RemoveLabelFromTask(ctx context.Context, taskID, labelID int) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.tasks[taskID]; !ok {
+		return "", errTaskNotFound
+	}
+	ids := a.taskLabels[taskID]
+	if len(without(ids, labelID)) == len(ids) {
+		return "", errLabelNotFound
+	}
+	a.taskLabels[taskID] = without(ids, labelID)
+	return message("The label was successfully removed from the task."), nil
+}
+
+// without returns ids without id.
+func without(ids []int, id int) []int {
+	var out []int
+	for _, x := range ids {
+		if x != id {
+			out = append(out, x)
+		}
+	}
+	return out
+}
+
+/* ---- RelationService ---- */
+
+// inverseRelation maps each kind to the one Vikunja records on the other
+// task.
+var inverseRelation = map[core.RelationKind]core.RelationKind{
+	core.RelationSubtask:     core.RelationParentTask,
+	core.RelationParentTask:  core.RelationSubtask,
+	core.RelationRelated:     core.RelationRelated,
+	core.RelationDuplicateOf: core.RelationDuplicates,
+	core.RelationDuplicates:  core.RelationDuplicateOf,
+	core.RelationBlocking:    core.RelationBlocked,
+	core.RelationBlocked:     core.RelationBlocking,
+	core.RelationPrecedes:    core.RelationFollows,
+	core.RelationFollows:     core.RelationPrecedes,
+	core.RelationCopiedFrom:  core.RelationCopiedTo,
+	core.RelationCopiedTo:    core.RelationCopiedFrom,
+}
+
+// CreateTaskRelation also records the inverse relation on the other task.
+func (a *Adapter) CreateTaskRelation(ctx context.Context, taskID, otherTaskID int, kind api.RelationKind) (api.TaskRelation, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	inverse, ok := inverseRelation[kind]
+	if !ok {
+		return api.TaskRelation{}, apiError(400, "The task relation kind is invalid.")
+	}
+	if _, ok := a.tasks[taskID]; !ok {
+		return api.TaskRelation{}, errTaskNotFound
+	}
+	if _, ok := a.tasks[otherTaskID]; !ok {
+		return api.TaskRelation{}, errTaskNotFound
+	}
+	if taskID == otherTaskID {
+		return api.TaskRelation{}, apiError(400, "You cannot relate a task with itself.")
+	}
+	for _, r := range a.relations {
+		if r.TaskID == taskID && r.OtherTaskID == otherTaskID && r.RelationKind == kind {
+			return api.TaskRelation{}, apiError(409, "The task relation already exists.")
+		}
+	}
+	now := a.now()
+	r := api.TaskRelation{TaskID: taskID, OtherTaskID: otherTaskID, RelationKind: kind, Created: now}
+	a.relations = append(a.relations, r, api.TaskRelation{TaskID: otherTaskID, OtherTaskID: taskID, RelationKind: inverse, Created: now})
+	return r, nil
+}
+
+// DeleteTaskRelation also removes the inverse relation.
+func (a *Adapter) DeleteTaskRelation(ctx context.Context, taskID, otherTaskID int, kind api.RelationKind) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	inverse := inverseRelation[kind]
+	found := false
+	var kept []api.TaskRelation
+	for _, r := range a.relations {
+		switch {
+		case r.TaskID == taskID && r.OtherTaskID == otherTaskID && r.RelationKind == kind:
+			found = true
+		case r.TaskID == otherTaskID && r.OtherTaskID == taskID && r.RelationKind == inverse:
+		default:
+			kept = append(kept, r)
+		}
+	}
+	if !found {
+		return "", apiError(404, "The task relation does not exist.")
+	}
+	a.relations = kept
+	return message("The task relation was successfully deleted."), nil
+}
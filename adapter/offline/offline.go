@@ -0,0 +1,556 @@
+// Package offline implements the task, project, label and user services on
+// top of another implementation, normally the Vikunja adapter, so that kunja
+// keeps working without a network.
+//
+// Everything read from the server is mirrored into a store.Store. When the
+// server cannot be reached, reads are answered from the store and task
+// creates, updates and deletes are applied to it and queued; Sync replays the
+// queue once the server is back. Other changes (labels, projects,
+// assignments) need the server and fail with ErrOffline.
+package offline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"kunja/api"
+	"kunja/internal/core/filter"
+	"kunja/internal/service"
+	"kunja/internal/store"
+)
+
+// ErrOffline is returned for operations that need the server while it
+// cannot be reached.
+var ErrOffline = errors.New("server unreachable")
+
+// Remote is the implementation the adapter mirrors.
+type Remote interface {
+	service.TaskService
+	service.ProjectService
+	service.LabelService
+	service.UserService
+}
+
+// Adapter implements service.TaskService, ProjectService, LabelService and
+// UserService with an offline fallback.
+type Adapter struct {
+	remote Remote
+	store  *store.Store
+
+	// Urgency scores tasks changed offline; nil means the default model.
+	Urgency *api.UrgencyModel
+	// Notify, if set, receives a one-line message when the adapter switches
+	// to the cache, and once when changes are waiting to be synced.
+	Notify func(msg string)
+
+	mu       sync.Mutex
+	offline  bool
+	reminded bool
+}
+
+// New returns an adapter mirroring remote into st. With offline set it
+// answers from st without trying the server.
+func New(remote Remote, st *store.Store, offline bool) *Adapter {
+	return &Adapter{remote: remote, store: st, offline: offline}
+}
+
+// Compile-time assertions.
+var (
+	_ service.TaskService    = (*Adapter)(nil)
+	_ service.ProjectService = (*Adapter)(nil)
+	_ service.LabelService   = (*Adapter)(nil)
+	_ service.UserService    = (*Adapter)(nil)
+)
+
+// Store returns the store the adapter mirrors into.
+func (a *Adapter) Store() *store.Store {
+	return a.store
+}
+
+// Remote returns the implementation the adapter mirrors.
+func (a *Adapter) Remote() Remote {
+	return a.remote
+}
+
+// Offline reports whether the adapter answers from the cache.
+func (a *Adapter) Offline() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.offline
+}
+
+// unreachable reports whether err means the request never got an answer
+// from the server. For writes only failures to connect count: a request
+// that timed out may have been applied, and queueing it would apply it
+// twice.
+func unreachable(err error, write bool) bool {
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		return false
+	}
+	if !write {
+		return true
+	}
+	var dnsErr *net.DNSError
+	var opErr *net.OpError
+	return errors.As(err, &dnsErr) || errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// fallback reports whether the call that returned err should be answered
+// from the cache instead, switching the adapter offline if so.
+func (a *Adapter) fallback(ctx context.Context, err error, write bool) bool {
+	if err == nil || ctx.Err() != nil || !unreachable(err, write) {
+		return false
+	}
+	a.mu.Lock()
+	first := !a.offline
+	a.offline = true
+	a.mu.Unlock()
+	if first {
+		a.notify(fmt.Sprintf("server unreachable (%v); working from the offline cache", err))
+	}
+	return true
+}
+
+func (a *Adapter) notify(msg string) {
+	if a.Notify != nil {
+		a.Notify(msg)
+	}
+}
+
+// mirror applies fn to the cached snapshot. The cache is best effort:
+// failures to write it never fail the call that fetched the data.
+// Tasks with queued changes keep their local state until synced.
+func (a *Adapter) mirror(fn func(s *store.Snapshot, pending map[int]bool)) {
+	q, err := a.store.Queue()
+	if err != nil {
+		return
+	}
+	pending := map[int]bool{}
+	for _, m := range q {
+		pending[m.TaskID] = true
+	}
+	a.mu.Lock()
+	remind := len(q) > 0 && !a.reminded
+	a.reminded = true
+	a.mu.Unlock()
+	if remind {
+		a.notify(fmt.Sprintf("%d offline change(s) not synced yet; run `kunja sync`", len(q)))
+	}
+	a.store.Update(func(s *store.Snapshot) error {
+		fn(s, pending)
+		return nil
+	})
+}
+
+func (a *Adapter) mirrorTasks(tasks ...api.Task) {
+	a.mirror(func(s *store.Snapshot, pending map[int]bool) {
+		for _, t := range tasks {
+			if !pending[t.ID] {
+				s.PutTask(t)
+			}
+		}
+	})
+}
+
+// needsServer is the error of operations that are not available offline.
+func needsServer(what string) error {
+	return fmt.Errorf("%w: %s needs the server", ErrOffline, what)
+}
+
+func (a *Adapter) score(t *api.Task) {
+	if a.Urgency == nil {
+		t.CalculateUrgency()
+		return
+	}
+	t.CalculateUrgencyWith(*a.Urgency)
+}
+
+/* ---- TaskService ---- */
+
+func (a *Adapter) GetAllTasks(ctx context.Context, params api.GetAllTasksParams) ([]api.Task, error) {
+	if !a.Offline() {
+		tasks, err := a.remote.GetAllTasks(ctx, params)
+		if !a.fallback(ctx, err, false) {
+			if err == nil {
+				a.mirrorTasks(tasks...)
+			}
+			return tasks, err
+		}
+	}
+	snap, err := a.store.Snapshot()
+	if err != nil {
+		return nil, err
+	}
+	return queryTasks(ctx, snap, params)
+}
+
+func (a *Adapter) GetTask(ctx context.Context, id int) (api.Task, error) {
+	if !a.Offline() && id > 0 {
+		task, err := a.remote.GetTask(ctx, id)
+		if !a.fallback(ctx, err, false) {
+			if err == nil {
+				a.mirrorTasks(task)
+			}
+			return task, err
+		}
+	}
+	snap, err := a.store.Snapshot()
+	if err != nil {
+		return api.Task{}, err
+	}
+	task, ok := snap.Task(id)
+	if !ok {
+		return api.Task{}, fmt.Errorf("%w: task %d is not in the offline cache", ErrOffline, id)
+	}
+	return task, nil
+}
+
+func (a *Adapter) CreateTask(ctx context.Context, projectID int, task api.Task) (api.Task, error) {
+	if !a.Offline() {
+		created, err := a.remote.CreateTask(ctx, projectID, task)
+		if !a.fallback(ctx, err, true) {
+			if err == nil {
+				a.mirrorTasks(created)
+			}
+			return created, err
+		}
+	}
+
+	now := time.Now()
+	err := a.store.Update(func(s *store.Snapshot) error {
+		task.ID = s.LocalID()
+		task.ProjectID = projectID
+		task.Created, task.Updated = now, now
+		a.score(&task)
+		s.PutTask(task)
+		return nil
+	})
+	if err != nil {
+		return api.Task{}, err
+	}
+	err = a.store.UpdateQueue(func(q []store.Mutation) ([]store.Mutation, error) {
+		return append(q, store.Mutation{Op: store.OpCreate, TaskID: task.ID, ProjectID: projectID, Task: task, Queued: now}), nil
+	})
+	return task, err
+}
+
+func (a *Adapter) UpdateTask(ctx context.Context, id int, task api.Task) (api.Task, error) {
+	if !a.Offline() && id > 0 {
+		updated, err := a.remote.UpdateTask(ctx, id, task)
+		if !a.fallback(ctx, err, true) {
+			if err == nil {
+				a.mirrorTasks(updated)
+			}
+			return updated, err
+		}
+	}
+
+	now := time.Now()
+	var base time.Time
+	err := a.store.Update(func(s *store.Snapshot) error {
+		old, ok := s.Task(id)
+		if !ok {
+			return fmt.Errorf("%w: task %d is not in the offline cache", ErrOffline, id)
+		}
+		base = old.Updated
+		task.ID = id
+		if task.ProjectID == 0 {
+			task.ProjectID = old.ProjectID
+		}
+		if task.Done && !old.Done {
+			task.DoneAt = now
+		}
+		task.Created, task.Updated = old.Created, now
+		a.score(&task)
+		s.PutTask(task)
+		return nil
+	})
+	if err != nil {
+		return api.Task{}, err
+	}
+	err = a.store.UpdateQueue(func(q []store.Mutation) ([]store.Mutation, error) {
+		// Fold into a queued create or update of the same task; an update
+		// keeps the Base of the first change, the server's last version.
+		for i := range q {
+			if q[i].TaskID == id && q[i].Op != store.OpDelete {
+				q[i].Task, q[i].Queued = task, now
+				return q, nil
+			}
+		}
+		return append(q, store.Mutation{Op: store.OpUpdate, TaskID: id, Task: task, Base: base, Queued: now}), nil
+	})
+	return task, err
+}
+
+func (a *Adapter) DeleteTask(ctx context.Context, id int) (string, error) {
+	if !a.Offline() && id > 0 {
+		msg, err := a.remote.DeleteTask(ctx, id)
+		if !a.fallback(ctx, err, true) {
+			if err == nil {
+				a.mirror(func(s *store.Snapshot, _ map[int]bool) { s.RemoveTask(id) })
+			}
+			return msg, err
+		}
+	}
+
+	var base time.Time
+	err := a.store.Update(func(s *store.Snapshot) error {
+		old, ok := s.Task(id)
+		if !ok {
+			return fmt.Errorf("%w: task %d is not in the offline cache", ErrOffline, id)
+		}
+		base = old.Updated
+		s.RemoveTask(id)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	err = a.store.UpdateQueue(func(q []store.Mutation) ([]store.Mutation, error) {
+		// Deleting a task created offline cancels its creation; a queued
+		// update is superseded but passes on its Base.
+		created := false
+		kept := q[:0]
+		for _, m := range q {
+			if m.TaskID != id {
+				kept = append(kept, m)
+				continue
+			}
+			created = created || m.Op == store.OpCreate
+			if m.Op == store.OpUpdate {
+				base = m.Base
+			}
+		}
+		if !created {
+			kept = append(kept, store.Mutation{Op: store.OpDelete, TaskID: id, Base: base, Queued: time.Now()})
+		}
+		return kept, nil
+	})
+	return "queued for sync", err
+}
+
+func (a *Adapter) AssignUserToTask(ctx context.Context, taskID, userID int) (string, error) {
+	msg, err := a.remote.AssignUserToTask(ctx, taskID, userID)
+	if a.fallback(ctx, err, true) {
+		return "", needsServer("assigning users")
+	}
+	return msg, err
+}
+
+func (a *Adapter) GetTaskAssignees(ctx context.Context, taskID int) ([]api.User, error) {
+	if !a.Offline() {
+		users, err := a.remote.GetTaskAssignees(ctx, taskID)
+		if !a.fallback(ctx, err, false) {
+			return users, err
+		}
+	}
+	task, err := a.GetTask(ctx, taskID)
+	return task.Assignees, err
+}
+
+/* ---- ProjectService ---- */
+
+func (a *Adapter) GetAllProjects(ctx context.Context) ([]api.Project, error) {
+	if !a.Offline() {
+		projects, err := a.remote.GetAllProjects(ctx)
+		if !a.fallback(ctx, err, false) {
+			if err == nil {
+				a.mirror(func(s *store.Snapshot, _ map[int]bool) { s.Projects = projects })
+			}
+			return projects, err
+		}
+	}
+	snap, err := a.store.Snapshot()
+	return snap.Projects, err
+}
+
+func (a *Adapter) GetProject(ctx context.Context, id int) (api.Project, error) {
+	if !a.Offline() {
+		p, err := a.remote.GetProject(ctx, id)
+		if !a.fallback(ctx, err, false) {
+			if err == nil {
+				a.mirror(func(s *store.Snapshot, _ map[int]bool) { s.PutProject(p) })
+			}
+			return p, err
+		}
+	}
+	snap, err := a.store.Snapshot()
+	if err != nil {
+		return api.Project{}, err
+	}
+	for _, p := range snap.Projects {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return api.Project{}, fmt.Errorf("%w: project %d is not in the offline cache", ErrOffline, id)
+}
+
+func (a *Adapter) GetProjectUsers(ctx context.Context, projectID int) ([]api.UserWithRight, error) {
+	users, err := a.remote.GetProjectUsers(ctx, projectID)
+	if a.fallback(ctx, err, false) {
+		return nil, needsServer("listing project users")
+	}
+	return users, err
+}
+
+func (a *Adapter) CreateProject(ctx context.Context, p api.Project) (api.Project, error) {
+	created, err := a.remote.CreateProject(ctx, p)
+	if a.fallback(ctx, err, true) {
+		return api.Project{}, needsServer("creating projects")
+	}
+	if err == nil {
+		a.mirror(func(s *store.Snapshot, _ map[int]bool) { s.PutProject(created) })
+	}
+	return created, err
+}
+
+func (a *Adapter) DeleteProject(ctx context.Context, id int) (string, error) {
+	msg, err := a.remote.DeleteProject(ctx, id)
+	if a.fallback(ctx, err, true) {
+		return "", needsServer("deleting projects")
+	}
+	if err == nil {
+		a.mirror(func(s *store.Snapshot, _ map[int]bool) {
+			for i, p := range s.Projects {
+				if p.ID == id {
+					s.Projects = append(s.Projects[:i], s.Projects[i+1:]...)
+					break
+				}
+			}
+		})
+	}
+	return msg, err
+}
+
+/* ---- LabelService ---- */
+
+func (a *Adapter) GetAllLabels(ctx context.Context) ([]api.Label, error) {
+	if !a.Offline() {
+		labels, err := a.remote.GetAllLabels(ctx)
+		if !a.fallback(ctx, err, false) {
+			if err == nil {
+				a.mirror(func(s *store.Snapshot, _ map[int]bool) { s.Labels = labels })
+			}
+			return labels, err
+		}
+	}
+	snap, err := a.store.Snapshot()
+	return snap.Labels, err
+}
+
+func (a *Adapter) GetLabel(ctx context.Context, id int) (api.Label, error) {
+	if !a.Offline() {
+		l, err := a.remote.GetLabel(ctx, id)
+		if !a.fallback(ctx, err, false) {
+			return l, err
+		}
+	}
+	snap, err := a.store.Snapshot()
+	if err != nil {
+		return api.Label{}, err
+	}
+	for _, l := range snap.Labels {
+		if l.ID == id {
+			return l, nil
+		}
+	}
+	return api.Label{}, fmt.Errorf("%w: label %d is not in the offline cache", ErrOffline, id)
+}
+
+// Label changes are not mirrored; the next GetAllLabels picks them up.
+
+func (a *Adapter) CreateLabel(ctx context.Context, l api.Label) (api.Label, error) {
+	created, err := a.remote.CreateLabel(ctx, l)
+	if a.fallback(ctx, err, true) {
+		return api.Label{}, needsServer("creating labels")
+	}
+	return created, err
+}
+
+func (a *Adapter) UpdateLabel(ctx context.Context, id int, l api.Label) (api.Label, error) {
+	updated, err := a.remote.UpdateLabel(ctx, id, l)
+	if a.fallback(ctx, err, true) {
+		return api.Label{}, needsServer("editing labels")
+	}
+	return updated, err
+}
+
+func (a *Adapter) DeleteLabel(ctx context.Context, id int) (string, error) {
+	msg, err := a.remote.DeleteLabel(ctx, id)
+	if a.fallback(ctx, err, true) {
+		return "", needsServer("deleting labels")
+	}
+	return msg, err
+}
+
+func (a *Adapter) GetTaskLabels(ctx context.Context, taskID int) ([]api.Label, error) {
+	if !a.Offline() {
+		labels, err := a.remote.GetTaskLabels(ctx, taskID)
+		if !a.fallback(ctx, err, false) {
+			return labels, err
+		}
+	}
+	task, err := a.GetTask(ctx, taskID)
+	return task.Labels, err
+}
+
+func (a *Adapter) AddLabelToTask(ctx context.Context, taskID, labelID int) (string, error) {
+	msg, err := a.remote.AddLabelToTask(ctx, taskID, labelID)
+	if a.fallback(ctx, err, true) {
+		return "", needsServer("labelling tasks")
+	}
+	return msg, err
+}
+
+func (a *Adapter) RemoveLabelFromTask(ctx context.Context, taskID, labelID int) (string, error) {
+	msg, err := a.remote.RemoveLabelFromTask(ctx, taskID, labelID)
+	if a.fallback(ctx, err, true) {
+		return "", needsServer("removing labels")
+	}
+	return msg, err
+}
+
+/* ---- UserService ---- */
+
+func (a *Adapter) GetAllUsers(ctx context.Context) ([]api.User, error) {
+	if !a.Offline() {
+		users, err := a.remote.GetAllUsers(ctx)
+		if !a.fallback(ctx, err, false) {
+			if err == nil {
+				a.mirror(func(s *store.Snapshot, _ map[int]bool) { s.Users = users })
+			}
+			return users, err
+		}
+	}
+	snap, err := a.store.Snapshot()
+	return snap.Users, err
+}
+
+/* ---- local queries ---- */
+
+// queryTasks answers GetAllTasks from the snapshot. The legacy filter_by
+// parameters are ignored.
+func queryTasks(ctx context.Context, snap store.Snapshot, params api.GetAllTasksParams) ([]api.Task, error) {
+	env := filter.Env{ProjectID: func(ref string) (int, error) {
+		for _, p := range snap.Projects {
+			if strings.EqualFold(p.Title, ref) || strings.EqualFold(p.Identifier, ref) {
+				return p.ID, nil
+			}
+		}
+		return 0, fmt.Errorf("project not found: %q", ref)
+	}}
+	tasks, err := filter.Query(snap.Tasks, params, env)
+	if err != nil {
+		return nil, fmt.Errorf("offline %w", err)
+	}
+	api.RecordTotal(ctx, len(tasks))
+	return filter.Page(tasks, params.Page, params.PerPage), nil
+}
@@ -0,0 +1,230 @@
+package offline
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"kunja/api"
+	"kunja/internal/store"
+)
+
+// SyncOptions control how Sync treats conflicts: queued changes to tasks
+// that were updated on the server after the change was made.
+type SyncOptions struct {
+	Force   bool // replay them anyway, overwriting the server's changes
+	Discard bool // drop them, keeping the server's version
+}
+
+// Change is one queued mutation as handled by Sync.
+type Change struct {
+	Op      store.Op `json:"op"`
+	TaskID  int      `json:"task_id"`            // the server's ID once created
+	LocalID int      `json:"local_id,omitempty"` // the offline ID of a created task
+	Title   string   `json:"title,omitempty"`
+	Reason  string   `json:"reason,omitempty"`
+}
+
+// SyncResult reports what Sync did. Conflicting and failed changes stay
+// queued.
+type SyncResult struct {
+	Applied   []Change `json:"applied"`
+	Conflicts []Change `json:"conflicts"`
+	Discarded []Change `json:"discarded"`
+	Failed    []Change `json:"failed"`
+	Projects  int      `json:"projects"`
+	Tasks     int      `json:"tasks"`
+	Labels    int      `json:"labels"`
+	Users     int      `json:"users"`
+}
+
+// Pending returns the queued mutations.
+func (a *Adapter) Pending() ([]store.Mutation, error) {
+	return a.store.Queue()
+}
+
+// notFound reports whether err is the server's answer for a missing task.
+func notFound(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "status code: 404")
+}
+
+// Sync replays the queued mutations against the server in the order they
+// were made and then replaces the snapshot with the server's current state,
+// with the still queued changes applied on top. Updates and deletes are
+// checked for conflicts first: if the task's Updated time on the server is
+// later than when the change was queued, someone else has changed it since.
+//
+// Sync always talks to the server, even when the adapter was created
+// offline. If the server cannot be reached it stops and keeps the rest of
+// the queue.
+func (a *Adapter) Sync(ctx context.Context, opts SyncOptions) (SyncResult, error) {
+	res := SyncResult{Applied: []Change{}, Conflicts: []Change{}, Discarded: []Change{}, Failed: []Change{}}
+	queue, err := a.store.Queue()
+	if err != nil {
+		return res, err
+	}
+
+	ids := map[int]int{} // local ID -> server ID
+	var remaining []store.Mutation
+	var syncErr error
+	for i, m := range queue {
+		if id, ok := ids[m.TaskID]; ok {
+			m.TaskID = id
+			m.Task.ID = id
+		}
+		c := Change{Op: m.Op, TaskID: m.TaskID, Title: m.Task.Title}
+
+		keep, err := a.replay(ctx, &m, &c, opts, &res)
+		if err != nil && unreachable(err, false) {
+			syncErr = fmt.Errorf("%w: %v", ErrOffline, err)
+			remaining = append(remaining, queue[i:]...)
+			break
+		}
+		if m.Op == store.OpCreate && !keep {
+			ids[c.LocalID] = c.TaskID
+			a.store.Update(func(s *store.Snapshot) error {
+				s.RemoveTask(c.LocalID)
+				return nil
+			})
+		}
+		if keep {
+			remaining = append(remaining, m)
+		}
+	}
+	for i := range remaining {
+		if id, ok := ids[remaining[i].TaskID]; ok {
+			remaining[i].TaskID, remaining[i].Task.ID = id, id
+		}
+	}
+	if err := a.store.UpdateQueue(func([]store.Mutation) ([]store.Mutation, error) {
+		return remaining, nil
+	}); err != nil {
+		return res, err
+	}
+	if syncErr != nil {
+		return res, syncErr
+	}
+
+	if err := a.pull(ctx, remaining, &res); err != nil {
+		return res, err
+	}
+	a.mu.Lock()
+	a.offline = false
+	a.mu.Unlock()
+	return res, nil
+}
+
+// replay applies one mutation and records the outcome in res. It reports
+// whether the mutation has to stay queued; errors that mean the server is
+// unreachable are returned so Sync can stop.
+func (a *Adapter) replay(ctx context.Context, m *store.Mutation, c *Change, opts SyncOptions, res *SyncResult) (bool, error) {
+	fail := func(err error) (bool, error) {
+		c.Reason = err.Error()
+		res.Failed = append(res.Failed, *c)
+		return true, err
+	}
+
+	if m.Op == store.OpCreate {
+		task := m.Task
+		task.ID, task.Created, task.Updated = 0, time.Time{}, time.Time{}
+		created, err := a.remote.CreateTask(ctx, m.ProjectID, task)
+		if err != nil {
+			return fail(err)
+		}
+		c.LocalID, c.TaskID = m.TaskID, created.ID
+		res.Applied = append(res.Applied, *c)
+		return false, nil
+	}
+	if m.TaskID < 0 {
+		c.Reason = "waiting for the task to be created"
+		res.Failed = append(res.Failed, *c)
+		return true, nil
+	}
+
+	server, err := a.remote.GetTask(ctx, m.TaskID)
+	switch {
+	case notFound(err) && m.Op == store.OpDelete:
+		c.Reason = "already deleted on the server"
+		res.Applied = append(res.Applied, *c)
+		return false, nil
+	case notFound(err):
+		err = fmt.Errorf("deleted on the server")
+		if opts.Discard {
+			c.Reason = err.Error()
+			res.Discarded = append(res.Discarded, *c)
+			return false, nil
+		}
+		c.Reason = err.Error()
+		res.Conflicts = append(res.Conflicts, *c)
+		return true, nil
+	case err != nil:
+		return fail(err)
+	}
+	if c.Title == "" {
+		c.Title = server.Title
+	}
+	if server.Updated.After(m.Base) && !opts.Force {
+		c.Reason = fmt.Sprintf("changed on the server at %s", server.Updated.Local().Format("2006-01-02 15:04"))
+		if opts.Discard {
+			res.Discarded = append(res.Discarded, *c)
+			return false, nil
+		}
+		res.Conflicts = append(res.Conflicts, *c)
+		return true, nil
+	}
+
+	if m.Op == store.OpDelete {
+		_, err = a.remote.DeleteTask(ctx, m.TaskID)
+	} else {
+		_, err = a.remote.UpdateTask(ctx, m.TaskID, m.Task)
+	}
+	if err != nil {
+		return fail(err)
+	}
+	res.Applied = append(res.Applied, *c)
+	return false, nil
+}
+
+// pull replaces the snapshot with the server's projects, tasks, labels and
+// users, then applies the mutations that are still queued.
+func (a *Adapter) pull(ctx context.Context, queued []store.Mutation, res *SyncResult) error {
+	var snap store.Snapshot
+	var err error
+	if snap.Projects, err = a.remote.GetAllProjects(ctx); err != nil {
+		return err
+	}
+	const perPage = 50
+	for page := 1; ; page++ {
+		batch, err := a.remote.GetAllTasks(ctx, api.GetAllTasksParams{Page: page, PerPage: perPage})
+		if err != nil {
+			return err
+		}
+		snap.Tasks = append(snap.Tasks, batch...)
+		if len(batch) < perPage {
+			break
+		}
+	}
+	if snap.Labels, err = a.remote.GetAllLabels(ctx); err != nil {
+		return err
+	}
+	if snap.Users, err = a.remote.GetAllUsers(ctx); err != nil {
+		// Listing users may be restricted; keep the ones seen before.
+		old, _ := a.store.Snapshot()
+		snap.Users = old.Users
+	}
+	res.Projects, res.Tasks, res.Labels, res.Users = len(snap.Projects), len(snap.Tasks), len(snap.Labels), len(snap.Users)
+
+	for _, m := range queued {
+		if m.Op == store.OpDelete {
+			snap.RemoveTask(m.TaskID)
+		} else {
+			snap.PutTask(m.Task)
+		}
+	}
+	snap.Synced = time.Now()
+	return a.store.Update(func(s *store.Snapshot) error {
+		*s = snap
+		return nil
+	})
+}
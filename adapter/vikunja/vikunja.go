@@ -2,6 +2,7 @@ package vikunja
 
 import (
 	"context"
+	"io"
 
 	"kunja/api"
 	"kunja/internal/service"
@@ -83,10 +84,152 @@ func (a *Adapter) GetAllUsers(ctx context.Context) ([]api.User, error) {
 	return a.client.GetAllUsers(ctx)
 }
 
+/* ---- LabelService ---- */
+
+func (a *Adapter) GetAllLabels(ctx context.Context) ([]api.Label, error) {
+	return a.client.GetAllLabels(ctx)
+}
+
+func (a *Adapter) GetLabel(ctx context.Context, id int) (api.Label, error) {
+	return a.client.GetLabel(ctx, id)
+}
+
+func (a *Adapter) CreateLabel(ctx context.Context, l api.Label) (api.Label, error) {
+	return a.client.CreateLabel(ctx, l)
+}
+
+func (a *Adapter) UpdateLabel(ctx context.Context, id int, l api.Label) (api.Label, error) {
+	return a.client.UpdateLabel(ctx, id, l)
+}
+
+func (a *Adapter) DeleteLabel(ctx context.Context, id int) (string, error) {
+	return a.client.DeleteLabel(ctx, id)
+}
+
+func (a *Adapter) GetTaskLabels(ctx context.Context, taskID int) ([]api.Label, error) {
+	return a.client.GetTaskLabels(ctx, taskID)
+}
+
+func (a *Adapter) AddLabelToTask(ctx context.Context, taskID, labelID int) (string, error) {
+	return a.client.AddLabelToTask(ctx, taskID, labelID)
+}
+
+func (a *Adapter) RemoveLabelFromTask(ctx context.Context, taskID, labelID int) (string, error) {
+	return a.client.RemoveLabelFromTask(ctx, taskID, labelID)
+}
+
+/* ---- BucketService ---- */
+
+func (a *Adapter) GetProjectViews(ctx context.Context, projectID int) ([]api.ProjectView, error) {
+	return a.client.GetProjectViews(ctx, projectID)
+}
+
+func (a *Adapter) GetBuckets(ctx context.Context, projectID, viewID int) ([]api.Bucket, error) {
+	return a.client.GetBuckets(ctx, projectID, viewID)
+}
+
+func (a *Adapter) GetBucketTasks(ctx context.Context, projectID, viewID int) ([]api.Bucket, error) {
+	return a.client.GetBucketTasks(ctx, projectID, viewID)
+}
+
+func (a *Adapter) CreateBucket(ctx context.Context, projectID, viewID int, b api.Bucket) (api.Bucket, error) {
+	return a.client.CreateBucket(ctx, projectID, viewID, b)
+}
+
+func (a *Adapter) UpdateBucket(ctx context.Context, projectID, viewID, bucketID int, b api.Bucket) (api.Bucket, error) {
+	return a.client.UpdateBucket(ctx, projectID, viewID, bucketID, b)
+}
+
+func (a *Adapter) DeleteBucket(ctx context.Context, projectID, viewID, bucketID int) (string, error) {
+	return a.client.DeleteBucket(ctx, projectID, viewID, bucketID)
+}
+
+func (a *Adapter) MoveTaskToBucket(ctx context.Context, projectID, viewID, bucketID, taskID int) (string, error) {
+	return a.client.MoveTaskToBucket(ctx, projectID, viewID, bucketID, taskID)
+}
+
+/* ---- CommentService ---- */
+
+func (a *Adapter) GetTaskComments(ctx context.Context, taskID int) ([]api.TaskComment, error) {
+	return a.client.GetTaskComments(ctx, taskID)
+}
+
+func (a *Adapter) GetTaskComment(ctx context.Context, taskID, commentID int) (api.TaskComment, error) {
+	return a.client.GetTaskComment(ctx, taskID, commentID)
+}
+
+func (a *Adapter) CreateTaskComment(ctx context.Context, taskID int, comment string) (api.TaskComment, error) {
+	return a.client.CreateTaskComment(ctx, taskID, comment)
+}
+
+func (a *Adapter) UpdateTaskComment(ctx context.Context, taskID, commentID int, comment string) (api.TaskComment, error) {
+	return a.client.UpdateTaskComment(ctx, taskID, commentID, comment)
+}
+
+func (a *Adapter) DeleteTaskComment(ctx context.Context, taskID, commentID int) (string, error) {
+	return a.client.DeleteTaskComment(ctx, taskID, commentID)
+}
+
+/* ---- AttachmentService ---- */
+
+func (a *Adapter) GetTaskAttachments(ctx context.Context, taskID int) ([]api.TaskAttachment, error) {
+	return a.client.GetTaskAttachments(ctx, taskID)
+}
+
+func (a *Adapter) UploadTaskAttachment(ctx context.Context, taskID int, filename string, r io.Reader) (api.TaskAttachment, error) {
+	return a.client.UploadTaskAttachment(ctx, taskID, filename, r)
+}
+
+func (a *Adapter) DownloadTaskAttachment(ctx context.Context, taskID, attachmentID int, w io.Writer) (int64, error) {
+	return a.client.DownloadTaskAttachment(ctx, taskID, attachmentID, w)
+}
+
+func (a *Adapter) DeleteTaskAttachment(ctx context.Context, taskID, attachmentID int) (string, error) {
+	return a.client.DeleteTaskAttachment(ctx, taskID, attachmentID)
+}
+
+/* ---- RelationService ---- */
+
+func (a *Adapter) CreateTaskRelation(ctx context.Context, taskID, otherTaskID int, kind api.RelationKind) (api.TaskRelation, error) {
+	return a.client.CreateTaskRelation(ctx, taskID, otherTaskID, kind)
+}
+
+func (a *Adapter) DeleteTaskRelation(ctx context.Context, taskID, otherTaskID int, kind api.RelationKind) (string, error) {
+	return a.client.DeleteTaskRelation(ctx, taskID, otherTaskID, kind)
+}
+
+/* ---- FilterService ---- */
+
+func (a *Adapter) GetSavedFilters(ctx context.Context) ([]api.SavedFilter, error) {
+	return a.client.GetSavedFilters(ctx)
+}
+
+func (a *Adapter) GetSavedFilter(ctx context.Context, id int) (api.SavedFilter, error) {
+	return a.client.GetSavedFilter(ctx, id)
+}
+
+func (a *Adapter) CreateSavedFilter(ctx context.Context, f api.SavedFilter) (api.SavedFilter, error) {
+	return a.client.CreateSavedFilter(ctx, f)
+}
+
+func (a *Adapter) UpdateSavedFilter(ctx context.Context, id int, f api.SavedFilter) (api.SavedFilter, error) {
+	return a.client.UpdateSavedFilter(ctx, id, f)
+}
+
+func (a *Adapter) DeleteSavedFilter(ctx context.Context, id int) (string, error) {
+	return a.client.DeleteSavedFilter(ctx, id)
+}
+
 // Ensure compile-time interface satisfaction
 var (
-	_ service.AuthService    = (*Adapter)(nil)
-	_ service.TaskService    = (*Adapter)(nil)
-	_ service.ProjectService = (*Adapter)(nil)
-	_ service.UserService    = (*Adapter)(nil)
+	_ service.AuthService       = (*Adapter)(nil)
+	_ service.TaskService       = (*Adapter)(nil)
+	_ service.ProjectService    = (*Adapter)(nil)
+	_ service.UserService       = (*Adapter)(nil)
+	_ service.LabelService      = (*Adapter)(nil)
+	_ service.BucketService     = (*Adapter)(nil)
+	_ service.CommentService    = (*Adapter)(nil)
+	_ service.AttachmentService = (*Adapter)(nil)
+	_ service.RelationService   = (*Adapter)(nil)
+	_ service.FilterService     = (*Adapter)(nil)
 )
@@ -7,11 +7,21 @@ import "kunja/internal/core"
 // All behaviour now lives in core; api only re-exports the types.
 type (
     Bucket            = core.Bucket
+    ProjectView       = core.ProjectView
     Label             = core.Label
     TaskReminder      = core.TaskReminder
+    TaskComment       = core.TaskComment
+    TaskAttachment    = core.TaskAttachment
+    TaskRelation      = core.TaskRelation
+    RelationKind      = core.RelationKind
+    File              = core.File
     Task              = core.Task
+    UrgencyModel      = core.UrgencyModel
+    UrgencyTerm       = core.UrgencyTerm
     GetAllTasksParams = core.GetAllTasksParams
     Project           = core.Project
+    SavedFilter       = core.SavedFilter
+    SavedFilterQuery  = core.SavedFilterQuery
     UserWithRight     = core.UserWithRight
     User              = core.User
 )
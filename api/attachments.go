@@ -0,0 +1,142 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+)
+
+// transferClient returns an http.Client sharing the regular transport but
+// without the overall request timeout, so large attachment transfers are
+// bounded only by the caller's context.
+func (client *ApiClient) transferClient() *http.Client {
+	return &http.Client{Transport: client.HttpClient.Transport}
+}
+
+// GetTaskAttachments retrieves the attachments of a task.
+func (client *ApiClient) GetTaskAttachments(ctx context.Context, taskID int) ([]TaskAttachment, error) {
+	response, err := client.getCtx(ctx, fmt.Sprintf("/tasks/%d/attachments", taskID))
+	if err != nil {
+		return nil, err
+	}
+	var attachments []TaskAttachment
+	if err := json.Unmarshal([]byte(response), &attachments); err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+// UploadTaskAttachment uploads r as a new attachment named filename.
+// The multipart body is streamed through a pipe, so the file is never held
+// in memory as a whole. When r is an io.Seeker the upload is retried once
+// after a transparent token refresh.
+func (client *ApiClient) UploadTaskAttachment(ctx context.Context, taskID int, filename string, r io.Reader) (TaskAttachment, error) {
+	apiPath := fmt.Sprintf("/tasks/%d/attachments", taskID)
+
+	// send uploads the body once. It returns only after the goroutine
+	// writing the body has stopped reading r, so r may be rewound for a
+	// retry: closing pr makes a write still pending fail.
+	send := func() ([]byte, int, error) {
+		pr, pw := io.Pipe()
+		mw := multipart.NewWriter(pw)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			part, err := mw.CreateFormFile("files", filename)
+			if err == nil {
+				_, err = io.Copy(part, r)
+			}
+			if err == nil {
+				err = mw.Close()
+			}
+			pw.CloseWithError(err)
+		}()
+		defer func() {
+			pr.Close()
+			<-done
+		}()
+		resp, err := client.do(ctx, client.transferClient(), http.MethodPut, apiPath, pr, mw.FormDataContentType())
+		if err != nil {
+			return nil, 0, err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		return body, resp.StatusCode, err
+	}
+
+	respBody, status, err := send()
+	if err != nil {
+		return TaskAttachment{}, err
+	}
+	if seeker, ok := r.(io.Seeker); ok && status == http.StatusUnauthorized && ctx.Value(tokenRefreshAttemptedKey{}) == nil {
+		if err := client.refreshToken(ctx); err == nil {
+			ctx = context.WithValue(ctx, tokenRefreshAttemptedKey{}, true)
+			if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+				return TaskAttachment{}, err
+			}
+			respBody, status, err = send()
+			if err != nil {
+				return TaskAttachment{}, err
+			}
+		}
+	}
+	if status < 200 || status >= 300 {
+		return TaskAttachment{}, errorFromBody(status, respBody)
+	}
+
+	// Vikunja reports per-file results: {"success": [...], "errors": [...]}.
+	var result struct {
+		Success []TaskAttachment `json:"success"`
+		Errors  []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return TaskAttachment{}, err
+	}
+	if len(result.Errors) > 0 {
+		var msgs []string
+		for _, e := range result.Errors {
+			msgs = append(msgs, e.Message)
+		}
+		return TaskAttachment{}, errors.New(strings.Join(msgs, "; "))
+	}
+	if len(result.Success) == 0 {
+		return TaskAttachment{}, errors.New("upload returned no attachment")
+	}
+	return result.Success[0], nil
+}
+
+// DownloadTaskAttachment streams the content of an attachment into w and
+// returns the number of bytes written.
+func (client *ApiClient) DownloadTaskAttachment(ctx context.Context, taskID, attachmentID int, w io.Writer) (int64, error) {
+	apiPath := fmt.Sprintf("/tasks/%d/attachments/%d", taskID, attachmentID)
+	resp, err := client.do(ctx, client.transferClient(), http.MethodGet, apiPath, nil, "")
+	if err != nil {
+		return 0, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized && ctx.Value(tokenRefreshAttemptedKey{}) == nil {
+		resp.Body.Close()
+		if err := client.refreshToken(ctx); err != nil {
+			return 0, errorFromBody(http.StatusUnauthorized, nil)
+		}
+		ctx = context.WithValue(ctx, tokenRefreshAttemptedKey{}, true)
+		return client.DownloadTaskAttachment(ctx, taskID, attachmentID, w)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return 0, errorFromBody(resp.StatusCode, body)
+	}
+	return io.Copy(w, resp.Body)
+}
+
+// DeleteTaskAttachment removes an attachment from a task.
+func (client *ApiClient) DeleteTaskAttachment(ctx context.Context, taskID, attachmentID int) (string, error) {
+	return client.deleteCtx(ctx, fmt.Sprintf("/tasks/%d/attachments/%d", taskID, attachmentID))
+}
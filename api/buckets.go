@@ -0,0 +1,103 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// bucketPayload is the writable subset of a bucket sent on create/update.
+type bucketPayload struct {
+	Title    string  `json:"title"`
+	Limit    int     `json:"limit"`
+	Position float64 `json:"position,omitempty"`
+}
+
+func bucketsPath(projectID, viewID int) string {
+	return fmt.Sprintf("/projects/%d/views/%d/buckets", projectID, viewID)
+}
+
+// GetProjectViews retrieves all views (list, gantt, table, kanban) of a project.
+func (client *ApiClient) GetProjectViews(ctx context.Context, projectID int) ([]ProjectView, error) {
+	response, err := client.getCtx(ctx, fmt.Sprintf("/projects/%d/views", projectID))
+	if err != nil {
+		return nil, err
+	}
+	var views []ProjectView
+	if err := json.Unmarshal([]byte(response), &views); err != nil {
+		return nil, err
+	}
+	return views, nil
+}
+
+// GetBuckets retrieves the buckets of a kanban view without their tasks.
+func (client *ApiClient) GetBuckets(ctx context.Context, projectID, viewID int) ([]Bucket, error) {
+	response, err := client.getCtx(ctx, bucketsPath(projectID, viewID))
+	if err != nil {
+		return nil, err
+	}
+	var buckets []Bucket
+	if err := json.Unmarshal([]byte(response), &buckets); err != nil {
+		return nil, err
+	}
+	return buckets, nil
+}
+
+// GetBucketTasks retrieves the buckets of a kanban view including the tasks
+// in each bucket. For kanban views Vikunja returns buckets instead of a flat
+// task list from the view's task endpoint.
+func (client *ApiClient) GetBucketTasks(ctx context.Context, projectID, viewID int) ([]Bucket, error) {
+	response, err := client.getCtx(ctx, fmt.Sprintf("/projects/%d/views/%d/tasks", projectID, viewID))
+	if err != nil {
+		return nil, err
+	}
+	var buckets []Bucket
+	if err := json.Unmarshal([]byte(response), &buckets); err != nil {
+		return nil, err
+	}
+	for i := range buckets {
+		for j := range buckets[i].Tasks {
+			client.scoreUrgency(&buckets[i].Tasks[j])
+		}
+	}
+	return buckets, nil
+}
+
+// CreateBucket adds a new bucket to a kanban view.
+func (client *ApiClient) CreateBucket(ctx context.Context, projectID, viewID int, b Bucket) (Bucket, error) {
+	payload, _ := json.Marshal(bucketPayload{Title: b.Title, Limit: b.Limit, Position: b.Position})
+	response, err := client.putCtx(ctx, bucketsPath(projectID, viewID), string(payload))
+	if err != nil {
+		return Bucket{}, err
+	}
+	var created Bucket
+	if err := json.Unmarshal([]byte(response), &created); err != nil {
+		return Bucket{}, err
+	}
+	return created, nil
+}
+
+// UpdateBucket changes title, WIP limit or position of a bucket.
+func (client *ApiClient) UpdateBucket(ctx context.Context, projectID, viewID, bucketID int, b Bucket) (Bucket, error) {
+	payload, _ := json.Marshal(bucketPayload{Title: b.Title, Limit: b.Limit, Position: b.Position})
+	response, err := client.postCtx(ctx, fmt.Sprintf("%s/%d", bucketsPath(projectID, viewID), bucketID), string(payload))
+	if err != nil {
+		return Bucket{}, err
+	}
+	var updated Bucket
+	if err := json.Unmarshal([]byte(response), &updated); err != nil {
+		return Bucket{}, err
+	}
+	return updated, nil
+}
+
+// DeleteBucket removes a bucket. Vikunja moves its tasks to the default bucket.
+func (client *ApiClient) DeleteBucket(ctx context.Context, projectID, viewID, bucketID int) (string, error) {
+	return client.deleteCtx(ctx, fmt.Sprintf("%s/%d", bucketsPath(projectID, viewID), bucketID))
+}
+
+// MoveTaskToBucket moves a task into the given bucket of a kanban view.
+func (client *ApiClient) MoveTaskToBucket(ctx context.Context, projectID, viewID, bucketID, taskID int) (string, error) {
+	payload, _ := json.Marshal(map[string]int{"task_id": taskID})
+	return client.postCtx(ctx, fmt.Sprintf("%s/%d/tasks", bucketsPath(projectID, viewID), bucketID), string(payload))
+}
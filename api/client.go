@@ -19,6 +19,9 @@ type ApiClient struct {
 	Username   string
 	Password   string
 	Verbose    bool
+	// Urgency is the model used to score fetched tasks; nil means the
+	// default Vikunja formula.
+	Urgency *UrgencyModel
 }
 
 // tokenRefreshAttemptedKey marks a context that already retried a token refresh once.
@@ -41,23 +44,52 @@ func NewApiClient(baseURL string, token string) *ApiClient {
 	}
 }
 
+// scoreUrgency sets the urgency of task using the client's model.
+func (client *ApiClient) scoreUrgency(task *Task) {
+	if client.Urgency == nil {
+		task.CalculateUrgency()
+		return
+	}
+	task.CalculateUrgencyWith(*client.Urgency)
+}
+
 func (client *ApiClient) request(ctx context.Context, method, apiPath string, body io.Reader) ([]byte, int, error) {
+	contentType := ""
+	if method == http.MethodPost || method == http.MethodPut {
+		contentType = "application/json"
+	}
+	resp, err := client.do(ctx, client.HttpClient, method, apiPath, body, contentType)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp.StatusCode, err
+	}
+	return respBody, resp.StatusCode, nil
+}
+
+// do sends an authenticated request and returns the raw response; the caller
+// owns resp.Body. It is shared by the JSON helpers and the streaming
+// attachment transfers.
+func (client *ApiClient) do(ctx context.Context, httpClient *http.Client, method, apiPath string, body io.Reader, contentType string) (*http.Response, error) {
 	if client.Verbose {
 		fmt.Printf("%s %s\n", method, client.ApiBaseUrl+apiPath)
 	}
 	req, err := http.NewRequestWithContext(ctx, method, client.ApiBaseUrl+apiPath, body)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 	req.Header.Set("Authorization", "Bearer "+client.Token)
-	if method == http.MethodPost || method == http.MethodPut {
-		req.Header.Set("Content-Type", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
 	}
-	resp, err := client.HttpClient.Do(req)
+	resp, err := httpClient.Do(req)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
-	// Record X-Total header (if present) for later diagnostics.
+	// Record the X-Total header (if present) for the caller, see WithTotal.
 	// Try several header names used by Vikunja for the total-items count.
 	totalStr := resp.Header.Get("X-Total")
 	if totalStr == "" {
@@ -68,15 +100,10 @@ func (client *ApiClient) request(ctx context.Context, method, apiPath string, bo
 	}
 	if totalStr != "" {
 		if total, err := strconv.Atoi(totalStr); err == nil {
-			setLastTotal(total)
+			RecordTotal(ctx, total)
 		}
 	}
-	defer resp.Body.Close()
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, resp.StatusCode, err
-	}
-	return respBody, resp.StatusCode, nil
+	return resp, nil
 }
 
 func errorFromBody(status int, respBody []byte) error {
@@ -0,0 +1,262 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"kunja/adapter/memory"
+	"kunja/api"
+	"kunja/internal/vikunjatest"
+)
+
+// newServer starts a fake Vikunja server with the demo data and returns it
+// with a client logged in to it, whose credentials allow it to log in again.
+func newServer(t *testing.T) (*vikunjatest.Server, *api.ApiClient) {
+	t.Helper()
+	srv := vikunjatest.NewServer(memory.Demo())
+	t.Cleanup(srv.Close)
+	client := api.NewApiClient(srv.BaseURL(), srv.Token())
+	client.SetCredentials("demo", "demo")
+	return srv, client
+}
+
+// trace renders the requests the server received, for failure messages.
+func trace(srv *vikunjatest.Server) string {
+	var lines []string
+	for _, r := range srv.Requests() {
+		lines = append(lines, fmt.Sprintf("%s %s?%s -> %d", r.Method, r.Path, r.Query.Encode(), r.Status))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestClientTotal(t *testing.T) {
+	srv, client := newServer(t)
+	ctx, total := api.WithTotal(context.Background())
+	tasks, err := client.GetAllTasks(ctx, api.GetAllTasksParams{PerPage: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reqs := srv.Requests()
+	header := reqs[len(reqs)-1].Header.Get("X-Total")
+	n, ok := total()
+	if !ok || fmt.Sprint(n) != header {
+		t.Errorf("recorded total %d (%v), want the X-Total header %q", n, ok, header)
+	}
+	if len(tasks) != 3 || n <= 3 {
+		t.Errorf("got %d tasks of %d, want 3 of more", len(tasks), n)
+	}
+
+	// Listings made with other contexts keep their own totals.
+	other, otherTotal := api.WithTotal(context.Background())
+	if _, err := client.GetAllTasks(other, api.GetAllTasksParams{Filter: "done = true"}); err != nil {
+		t.Fatal(err)
+	}
+	if m, _ := otherTotal(); m == n {
+		t.Errorf("both listings recorded a total of %d", n)
+	}
+	if m, _ := total(); m != n {
+		t.Errorf("the first total changed from %d to %d", n, m)
+	}
+}
+
+func TestClientPagination(t *testing.T) {
+	srv, client := newServer(t)
+	ctx := context.Background()
+	projects, err := srv.Backend.GetAllProjects(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inbox := 0
+	for _, p := range projects {
+		if p.Title == "Inbox" {
+			inbox = p.ID
+		}
+	}
+	for i := 1; i <= 120; i++ {
+		if _, err := srv.Backend.CreateTask(ctx, inbox, api.Task{Title: fmt.Sprintf("Bulk task %d", i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, want, err := srv.Backend.QueryTasks(ctx, api.GetAllTasksParams{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seen := map[int]bool{}
+	for page := 1; ; page++ {
+		pageCtx, total := api.WithTotal(ctx)
+		tasks, err := client.GetAllTasks(pageCtx, api.GetAllTasksParams{Page: page, PerPage: 100})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(tasks) > 50 {
+			t.Errorf("page %d has %d tasks, more than the 50 Vikunja returns", page, len(tasks))
+		}
+		if n, _ := total(); n != want {
+			t.Errorf("page %d reports a total of %d, want %d", page, n, want)
+		}
+		for _, task := range tasks {
+			if seen[task.ID] {
+				t.Errorf("task %d is on two pages", task.ID)
+			}
+			seen[task.ID] = true
+		}
+		if len(tasks) < 50 {
+			break
+		}
+	}
+	if len(seen) != want {
+		t.Errorf("the pages hold %d tasks, want %d:\n%s", len(seen), want, trace(srv))
+	}
+}
+
+func TestClientRefreshOn401(t *testing.T) {
+	srv, client := newServer(t)
+	srv.ExpireTokens()
+	projects, err := client.GetAllProjects(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(projects) == 0 {
+		t.Error("no projects after the token refresh")
+	}
+	var got []string
+	for _, r := range srv.Requests() {
+		got = append(got, fmt.Sprintf("%s %s %d", r.Method, r.Path, r.Status))
+	}
+	want := "GET /projects 401, POST /login 200, GET /projects 200"
+	if strings.Join(got, ", ") != want {
+		t.Errorf("requests %q, want %q", got, want)
+	}
+}
+
+func TestClientRefreshWithoutCredentials(t *testing.T) {
+	srv, _ := newServer(t)
+	client := api.NewApiClient(srv.BaseURL(), "expired")
+	_, err := client.GetAllProjects(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "status code: 401, message: missing, malformed, expired or otherwise invalid token provided") {
+		t.Errorf("error %v, want the 401", err)
+	}
+	for _, r := range srv.Requests() {
+		if r.Path == "/login" {
+			t.Errorf("logged in without credentials:\n%s", trace(srv))
+		}
+	}
+}
+
+func TestClientRefreshLoginFails(t *testing.T) {
+	srv, client := newServer(t)
+	srv.ExpireTokens()
+	srv.Inject(vikunjatest.Fault{Method: "POST", Path: "/login", Status: 412, Message: "Wrong username or password."})
+	_, err := client.GetAllUsers(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "status code: 401") {
+		t.Errorf("error %v, want the original 401", err)
+	}
+	logins := 0
+	for _, r := range srv.Requests() {
+		if r.Path == "/login" {
+			logins++
+		}
+	}
+	if logins != 1 {
+		t.Errorf("logged in %d times, want once:\n%s", logins, trace(srv))
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	page := "<html><body><h1>502 Bad Gateway</h1>" + strings.Repeat("x", 300) + "</body></html>"
+	tests := []struct {
+		name  string
+		fault *vikunjatest.Fault
+		call  func(*api.ApiClient) error
+		want  string
+	}{
+		{
+			name:  "json-payload",
+			fault: &vikunjatest.Fault{Path: "/projects", Status: 500, Message: "database is locked"},
+			call: func(c *api.ApiClient) error {
+				_, err := c.GetAllProjects(context.Background())
+				return err
+			},
+			want: "status code: 500, message: database is locked",
+		},
+		{
+			name:  "raw-body",
+			fault: &vikunjatest.Fault{Path: "/users", Status: 502, Body: page},
+			call: func(c *api.ApiClient) error {
+				_, err := c.GetAllUsers(context.Background())
+				return err
+			},
+			want: "status code: 502, body: " + page[:256],
+		},
+		{
+			name: "not-found",
+			call: func(c *api.ApiClient) error {
+				_, err := c.GetTask(context.Background(), 999)
+				return err
+			},
+			want: "status code: 404, message: The task does not exist.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, client := newServer(t)
+			if tt.fault != nil {
+				srv.Inject(*tt.fault)
+			}
+			err := tt.call(client)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientSlowResponse(t *testing.T) {
+	const delay = 500 * time.Millisecond
+	srv, client := newServer(t)
+	srv.Inject(vikunjatest.Fault{Path: "/tasks/all", Delay: delay, Times: 1})
+	start := time.Now()
+	tasks, err := client.GetAllTasks(context.Background(), api.GetAllTasksParams{PerPage: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < delay {
+		t.Errorf("the listing took %v, less than the injected delay of %v", elapsed, delay)
+	}
+	if len(tasks) != 5 {
+		t.Errorf("got %d tasks, want 5", len(tasks))
+	}
+}
+
+// TestClientUploadAfter401 uploads a file large enough to still be sent
+// when the server answers the expired token with 401; the retry after the
+// login must send the whole file again. Run it with -race.
+func TestClientUploadAfter401(t *testing.T) {
+	srv, client := newServer(t)
+	ctx := context.Background()
+	tasks, _, err := srv.Backend.QueryTasks(ctx, api.GetAllTasksParams{PerPage: 1})
+	if err != nil || len(tasks) == 0 {
+		t.Fatalf("no task to attach to (%v)", err)
+	}
+	data := bytes.Repeat([]byte("0123456789abcdef"), 1<<18) // 4 MiB
+	srv.ExpireTokens()
+	a, err := client.UploadTaskAttachment(ctx, tasks[0].ID, "notes.txt", bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("upload: %v\n%s", err, trace(srv))
+	}
+	if a.File.Size != int64(len(data)) {
+		t.Errorf("uploaded %d bytes, want %d", a.File.Size, len(data))
+	}
+	var got bytes.Buffer
+	if _, err := srv.Backend.DownloadTaskAttachment(ctx, tasks[0].ID, a.ID, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got.Bytes(), data) {
+		t.Errorf("the stored attachment differs from the file (%d of %d bytes)", got.Len(), len(data))
+	}
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// GetTaskComments retrieves all comments of a task, oldest first.
+func (client *ApiClient) GetTaskComments(ctx context.Context, taskID int) ([]TaskComment, error) {
+	response, err := client.getCtx(ctx, fmt.Sprintf("/tasks/%d/comments", taskID))
+	if err != nil {
+		return nil, err
+	}
+	var comments []TaskComment
+	if err := json.Unmarshal([]byte(response), &comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// GetTaskComment retrieves a single comment of a task.
+func (client *ApiClient) GetTaskComment(ctx context.Context, taskID, commentID int) (TaskComment, error) {
+	response, err := client.getCtx(ctx, fmt.Sprintf("/tasks/%d/comments/%d", taskID, commentID))
+	if err != nil {
+		return TaskComment{}, err
+	}
+	var comment TaskComment
+	if err := json.Unmarshal([]byte(response), &comment); err != nil {
+		return TaskComment{}, err
+	}
+	return comment, nil
+}
+
+// CreateTaskComment adds a new comment to a task.
+func (client *ApiClient) CreateTaskComment(ctx context.Context, taskID int, comment string) (TaskComment, error) {
+	payload, _ := json.Marshal(map[string]string{"comment": comment})
+	response, err := client.putCtx(ctx, fmt.Sprintf("/tasks/%d/comments", taskID), string(payload))
+	if err != nil {
+		return TaskComment{}, err
+	}
+	var created TaskComment
+	if err := json.Unmarshal([]byte(response), &created); err != nil {
+		return TaskComment{}, err
+	}
+	return created, nil
+}
+
+// UpdateTaskComment replaces the text of an existing comment.
+func (client *ApiClient) UpdateTaskComment(ctx context.Context, taskID, commentID int, comment string) (TaskComment, error) {
+	payload, _ := json.Marshal(map[string]string{"comment": comment})
+	response, err := client.postCtx(ctx, fmt.Sprintf("/tasks/%d/comments/%d", taskID, commentID), string(payload))
+	if err != nil {
+		return TaskComment{}, err
+	}
+	var updated TaskComment
+	if err := json.Unmarshal([]byte(response), &updated); err != nil {
+		return TaskComment{}, err
+	}
+	return updated, nil
+}
+
+// DeleteTaskComment removes a comment from a task.
+func (client *ApiClient) DeleteTaskComment(ctx context.Context, taskID, commentID int) (string, error) {
+	return client.deleteCtx(ctx, fmt.Sprintf("/tasks/%d/comments/%d", taskID, commentID))
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"kunja/internal/core"
+)
+
+// savedFilterPayload is the writable subset of a saved filter sent on
+// create/update.
+type savedFilterPayload struct {
+	Title       string           `json:"title"`
+	Description string           `json:"description,omitempty"`
+	Filters     SavedFilterQuery `json:"filters"`
+	IsFavorite  bool             `json:"is_favorite"`
+}
+
+// GetSavedFilters retrieves all saved filters of the current user. Vikunja
+// has no endpoint listing them, so they are read off the pseudo projects in
+// /projects and then fetched one by one.
+func (client *ApiClient) GetSavedFilters(ctx context.Context) ([]SavedFilter, error) {
+	projects, err := client.GetAllProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var filters []SavedFilter
+	for _, p := range projects {
+		id, ok := core.SavedFilterID(p.ID)
+		if !ok {
+			continue
+		}
+		f, err := client.GetSavedFilter(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, f)
+	}
+	return filters, nil
+}
+
+// GetSavedFilter retrieves a single saved filter by its ID.
+func (client *ApiClient) GetSavedFilter(ctx context.Context, filterID int) (SavedFilter, error) {
+	response, err := client.getCtx(ctx, "/filters/"+strconv.Itoa(filterID))
+	if err != nil {
+		return SavedFilter{}, err
+	}
+	var f SavedFilter
+	if err := json.Unmarshal([]byte(response), &f); err != nil {
+		return SavedFilter{}, err
+	}
+	return f, nil
+}
+
+// CreateSavedFilter stores a new saved filter.
+func (client *ApiClient) CreateSavedFilter(ctx context.Context, f SavedFilter) (SavedFilter, error) {
+	payload, _ := json.Marshal(savedFilterPayload{
+		Title:       f.Title,
+		Description: f.Description,
+		Filters:     f.Filters,
+		IsFavorite:  f.IsFavorite,
+	})
+	response, err := client.putCtx(ctx, "/filters", string(payload))
+	if err != nil {
+		return SavedFilter{}, err
+	}
+	var created SavedFilter
+	if err := json.Unmarshal([]byte(response), &created); err != nil {
+		return SavedFilter{}, err
+	}
+	return created, nil
+}
+
+// UpdateSavedFilter replaces title, description, query and favorite flag of
+// an existing saved filter.
+func (client *ApiClient) UpdateSavedFilter(ctx context.Context, filterID int, f SavedFilter) (SavedFilter, error) {
+	payload, _ := json.Marshal(savedFilterPayload{
+		Title:       f.Title,
+		Description: f.Description,
+		Filters:     f.Filters,
+		IsFavorite:  f.IsFavorite,
+	})
+	response, err := client.postCtx(ctx, "/filters/"+strconv.Itoa(filterID), string(payload))
+	if err != nil {
+		return SavedFilter{}, err
+	}
+	var updated SavedFilter
+	if err := json.Unmarshal([]byte(response), &updated); err != nil {
+		return SavedFilter{}, err
+	}
+	return updated, nil
+}
+
+// DeleteSavedFilter deletes a saved filter.
+func (client *ApiClient) DeleteSavedFilter(ctx context.Context, filterID int) (string, error) {
+	return client.deleteCtx(ctx, "/filters/"+strconv.Itoa(filterID))
+}
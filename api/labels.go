@@ -0,0 +1,106 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// labelPayload is the writable subset of a label sent on create/update.
+type labelPayload struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	HexColor    string `json:"hex_color,omitempty"`
+}
+
+// GetAllLabels retrieves all labels the current user has access to.
+func (client *ApiClient) GetAllLabels(ctx context.Context) ([]Label, error) {
+	response, err := client.getCtx(ctx, "/labels")
+	if err != nil {
+		return nil, err
+	}
+	var labels []Label
+	if err := json.Unmarshal([]byte(response), &labels); err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+// GetLabel retrieves a single label by its ID.
+func (client *ApiClient) GetLabel(ctx context.Context, labelID int) (Label, error) {
+	response, err := client.getCtx(ctx, "/labels/"+strconv.Itoa(labelID))
+	if err != nil {
+		return Label{}, err
+	}
+	var label Label
+	if err := json.Unmarshal([]byte(response), &label); err != nil {
+		return Label{}, err
+	}
+	return label, nil
+}
+
+// CreateLabel creates a new label owned by the current user.
+func (client *ApiClient) CreateLabel(ctx context.Context, l Label) (Label, error) {
+	payload, _ := json.Marshal(labelPayload{
+		Title:       l.Title,
+		Description: l.Description,
+		HexColor:    l.HexColor,
+	})
+	response, err := client.putCtx(ctx, "/labels", string(payload))
+	if err != nil {
+		return Label{}, err
+	}
+	var created Label
+	if err := json.Unmarshal([]byte(response), &created); err != nil {
+		return Label{}, err
+	}
+	return created, nil
+}
+
+// UpdateLabel replaces title, description and colour of an existing label.
+func (client *ApiClient) UpdateLabel(ctx context.Context, labelID int, l Label) (Label, error) {
+	payload, _ := json.Marshal(labelPayload{
+		Title:       l.Title,
+		Description: l.Description,
+		HexColor:    l.HexColor,
+	})
+	response, err := client.postCtx(ctx, "/labels/"+strconv.Itoa(labelID), string(payload))
+	if err != nil {
+		return Label{}, err
+	}
+	var updated Label
+	if err := json.Unmarshal([]byte(response), &updated); err != nil {
+		return Label{}, err
+	}
+	return updated, nil
+}
+
+// DeleteLabel deletes a label. Tasks carrying it lose the label.
+func (client *ApiClient) DeleteLabel(ctx context.Context, labelID int) (string, error) {
+	return client.deleteCtx(ctx, "/labels/"+strconv.Itoa(labelID))
+}
+
+// GetTaskLabels retrieves all labels attached to a task.
+func (client *ApiClient) GetTaskLabels(ctx context.Context, taskID int) ([]Label, error) {
+	response, err := client.getCtx(ctx, fmt.Sprintf("/tasks/%d/labels", taskID))
+	if err != nil {
+		return nil, err
+	}
+	var labels []Label
+	if err := json.Unmarshal([]byte(response), &labels); err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+// AddLabelToTask attaches an existing label to a task.
+func (client *ApiClient) AddLabelToTask(ctx context.Context, taskID, labelID int) (string, error) {
+	payload, _ := json.Marshal(map[string]int{"label_id": labelID})
+	return client.putCtx(ctx, fmt.Sprintf("/tasks/%d/labels", taskID), string(payload))
+}
+
+// RemoveLabelFromTask detaches a label from a task.
+func (client *ApiClient) RemoveLabelFromTask(ctx context.Context, taskID, labelID int) (string, error) {
+	return client.deleteCtx(ctx, fmt.Sprintf("/tasks/%d/labels/%d", taskID, labelID))
+}
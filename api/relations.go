@@ -0,0 +1,30 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// CreateTaskRelation relates taskID to otherTaskID with the given kind.
+// Vikunja creates the inverse relation (e.g. blocked ⇄ blocking) itself.
+func (client *ApiClient) CreateTaskRelation(ctx context.Context, taskID, otherTaskID int, kind RelationKind) (TaskRelation, error) {
+	payload, _ := json.Marshal(map[string]interface{}{
+		"other_task_id": otherTaskID,
+		"relation_kind": kind,
+	})
+	response, err := client.putCtx(ctx, fmt.Sprintf("/tasks/%d/relations", taskID), string(payload))
+	if err != nil {
+		return TaskRelation{}, err
+	}
+	var created TaskRelation
+	if err := json.Unmarshal([]byte(response), &created); err != nil {
+		return TaskRelation{}, err
+	}
+	return created, nil
+}
+
+// DeleteTaskRelation removes a relation of the given kind between two tasks.
+func (client *ApiClient) DeleteTaskRelation(ctx context.Context, taskID, otherTaskID int, kind RelationKind) (string, error) {
+	return client.deleteCtx(ctx, fmt.Sprintf("/tasks/%d/relations/%s/%d", taskID, kind, otherTaskID))
+}
@@ -20,7 +20,7 @@ func (client *ApiClient) GetAllTasks(ctx context.Context, params GetAllTasksPara
 		return nil, err
 	}
 	for i := range tasks {
-		tasks[i].CalculateUrgency()
+		client.scoreUrgency(&tasks[i])
 	}
 	return tasks, nil
 }
@@ -66,7 +66,7 @@ func (client *ApiClient) GetTask(ctx context.Context, ID int) (Task, error) {
 	if err != nil {
 		return Task{}, err
 	}
-	task.CalculateUrgency()
+	client.scoreUrgency(&task)
 	return task, nil
 }
 
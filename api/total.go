@@ -1,17 +1,42 @@
 package api
 
-import "sync/atomic"
+import (
+	"context"
+	"sync"
+)
 
-var lastTotal int64
+type totalKey struct{}
 
-// setLastTotal stores the total number of items returned by the last API
-// request that provided the X-Total header.
-func setLastTotal(n int) {
-	atomic.StoreInt64(&lastTotal, int64(n))
+// total holds the last total recorded with a context from WithTotal.
+type total struct {
+	mu sync.Mutex
+	n  int
+	ok bool
 }
 
-// GetLastTotal returns the last total value recorded from an API response.
-// It is safe for concurrent reads.
-func GetLastTotal() int {
-	return int(atomic.LoadInt64(&lastTotal))
+// WithTotal returns a context that collects the total number of matching
+// items reported for the listings made with it, such as Vikunja's X-Total
+// header, and a function returning the last one; ok is false until one was
+// reported. Each caller gets its own count, so concurrent listings, e.g. of
+// different MCP sessions, do not see each other's totals.
+func WithTotal(ctx context.Context) (context.Context, func() (n int, ok bool)) {
+	t := &total{}
+	return context.WithValue(ctx, totalKey{}, t), func() (int, bool) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		return t.n, t.ok
+	}
+}
+
+// RecordTotal records n as the total of a listing made with ctx, if ctx
+// comes from WithTotal. Services that answer without a request, such as
+// the offline cache, call it themselves.
+func RecordTotal(ctx context.Context, n int) {
+	t, ok := ctx.Value(totalKey{}).(*total)
+	if !ok {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.n, t.ok = n, true
 }
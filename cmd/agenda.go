@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"kunja/api"
+	"kunja/internal/render"
+
+	"github.com/spf13/cobra"
+)
+
+// agendaGroup is a section of the agenda, e.g. "Today", with its tasks in
+// due order.
+type agendaGroup struct {
+	Name  string     `json:"name"`
+	Tasks []api.Task `json:"tasks"`
+}
+
+// startOfDay returns midnight of t's day in t's location.
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// dueTime returns when a task is due in local time: its due date, or its end
+// date when it has none.
+func dueTime(t api.Task) (time.Time, bool) {
+	switch {
+	case !t.DueDate.IsZero():
+		return t.DueDate.Local(), true
+	case !t.EndDate.IsZero():
+		return t.EndDate.Local(), true
+	}
+	return time.Time{}, false
+}
+
+// formatDue prints a due time as "Fri 16 Oct", adding the clock time unless
+// it is midnight.
+func formatDue(t time.Time) string {
+	if t.Equal(startOfDay(t)) {
+		return t.Format("Mon 02 Jan")
+	}
+	return t.Format("Mon 02 Jan 15:04")
+}
+
+// agendaGroups sorts the open tasks due before the end of the given number
+// of days into Overdue, Today, Tomorrow, This week and Later.
+func agendaGroups(tasks []api.Task, now time.Time, days int) []agendaGroup {
+	today := startOfDay(now)
+	tomorrow := today.AddDate(0, 0, 1)
+	end := today.AddDate(0, 0, days)
+	// Weeks start on Monday.
+	nextWeek := today.AddDate(0, 0, 7-(int(today.Weekday())+6)%7)
+
+	groups := []agendaGroup{{Name: "Overdue"}, {Name: "Today"}, {Name: "Tomorrow"}, {Name: "This week"}, {Name: "Later"}}
+	sort.SliceStable(tasks, func(i, j int) bool {
+		a, _ := dueTime(tasks[i])
+		b, _ := dueTime(tasks[j])
+		if a.Equal(b) {
+			return tasks[i].Urgency > tasks[j].Urgency
+		}
+		return a.Before(b)
+	})
+	for _, t := range tasks {
+		due, ok := dueTime(t)
+		if !ok || !due.Before(end) {
+			continue
+		}
+		var g int
+		switch {
+		case due.Before(today):
+			g = 0
+		case due.Before(tomorrow):
+			g = 1
+		case due.Before(tomorrow.AddDate(0, 0, 1)):
+			g = 2
+		case due.Before(nextWeek):
+			g = 3
+		default:
+			g = 4
+		}
+		groups[g].Tasks = append(groups[g].Tasks, t)
+	}
+
+	nonEmpty := groups[:0]
+	for _, g := range groups {
+		if len(g.Tasks) > 0 {
+			nonEmpty = append(nonEmpty, g)
+		}
+	}
+	return nonEmpty
+}
+
+// buildAgenda returns the open tasks due within the next days days, and the
+// overdue ones, grouped by due date and rendered in the format selected by
+// out.
+func buildAgenda(ctx context.Context, svc Services, days int, out render.Options) (string, error) {
+	groups, err := queryAgenda(ctx, svc, days)
+	if err != nil {
+		return "", err
+	}
+	return renderAgenda(groups, days, out)
+}
+
+// queryAgenda returns the open tasks due within the next days days, and the
+// overdue ones, grouped by due date.
+func queryAgenda(ctx context.Context, svc Services, days int) ([]agendaGroup, error) {
+	if days < 1 {
+		return nil, fmt.Errorf("days must be at least 1")
+	}
+	now := time.Now()
+	end := startOfDay(now).AddDate(0, 0, days).UTC().Format(time.RFC3339)
+	params := api.GetAllTasksParams{
+		Filter: fmt.Sprintf("done = false && (due_date < %s || end_date < %s)", end, end),
+	}
+	tasks, err := fetchTasks(ctx, svc, params, 0)
+	if err != nil {
+		return nil, err
+	}
+	return agendaGroups(tasks, now, days), nil
+}
+
+// renderAgenda renders the agenda groups of the next days days in the
+// format selected by out.
+func renderAgenda(groups []agendaGroup, days int, out render.Options) (string, error) {
+	if !out.Tabular() {
+		table := render.Table{Header: []string{"Group", "ID", "Title", "Due", "Priority"}}
+		for _, g := range groups {
+			for _, t := range g.Tasks {
+				due, _ := dueTime(t)
+				table.AddRow(g.Name, t.ID, t.Title, due.Format(time.RFC3339), t.Priority)
+			}
+		}
+		return out.String(groups, table)
+	}
+
+	if len(groups) == 0 {
+		return fmt.Sprintf("Nothing due in the next %d days.\n", days), nil
+	}
+	var b strings.Builder
+	for i, g := range groups {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(g.Name + "\n")
+		table := render.Table{}
+		for _, t := range g.Tasks {
+			due, _ := dueTime(t)
+			table.AddRow("", fmt.Sprintf("#%d", t.ID), t.Title, formatDue(due))
+		}
+		if err := out.Render(&b, nil, table); err != nil {
+			return "", err
+		}
+	}
+	return b.String(), nil
+}
+
+var agendaCmd = &cobra.Command{
+	Use:   "agenda",
+	Short: "Show open tasks grouped by due date",
+	Long:  `Show the open tasks that are overdue or due within the next --days days, grouped into Overdue, Today, Tomorrow, This week and Later. Tasks without a due date are listed by their end date.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		days, _ := cmd.Flags().GetInt("days")
+		opts, err := outputOptions()
+		if err != nil {
+			return err
+		}
+		out, err := buildAgenda(cmd.Context(), getServices(cmd), days, opts)
+		if err != nil {
+			fmt.Println("Error building agenda:", err)
+			return err
+		}
+		fmt.Print(out)
+		return nil
+	},
+}
+
+func init() {
+	agendaCmd.Flags().Int("days", 7, "number of days ahead to include, starting today")
+	rootCmd.AddCommand(agendaCmd)
+}
@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"kunja/api"
+	"kunja/internal/render"
+
+	"github.com/spf13/cobra"
+)
+
+var attachCmd = &cobra.Command{
+	Use:   "attach [TASK_ID] [FILE...]",
+	Short: "Upload one or more files as task attachments",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		taskID, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid task ID: %q", args[0])
+		}
+		svc := getServices(cmd)
+
+		var uploaded []string
+		var failed []string
+		for _, path := range args[1:] {
+			a, err := uploadAttachment(cmd.Context(), svc, taskID, path)
+			if err != nil {
+				failed = append(failed, fmt.Sprintf("%s (%v)", path, err))
+			} else {
+				uploaded = append(uploaded, fmt.Sprintf("%s (%d)", a.File.Name, a.ID))
+			}
+		}
+
+		if len(uploaded) > 0 {
+			fmt.Printf("Uploaded: %s\n", strings.Join(uploaded, ", "))
+		}
+		if len(failed) > 0 {
+			fmt.Printf("Failed:  %s\n", strings.Join(failed, ", "))
+			return fmt.Errorf("%d file(s) could not be uploaded", len(failed))
+		}
+		return nil
+	},
+}
+
+var attachmentsCmd = &cobra.Command{
+	Use:   "attachments [TASK_ID]",
+	Short: "List the attachments of a task (arg TASK_ID)",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		taskID, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid task ID: %q", args[0])
+		}
+		opts, err := outputOptions()
+		if err != nil {
+			return err
+		}
+		out, err := buildAttachmentList(cmd.Context(), getServices(cmd), taskID, opts)
+		if err != nil {
+			fmt.Println("Error retrieving attachments:", err)
+			return err
+		}
+		fmt.Print(out)
+		return nil
+	},
+}
+
+var fetchAttachmentCmd = &cobra.Command{
+	Use:   "fetch-attachment [TASK_ID] [ATTACHMENT_ID]",
+	Short: "Download a task attachment to disk",
+	Long:  `Download a task attachment. The file is streamed to --dest (default: the attachment's file name in the current directory); use --dest - to write to stdout.`,
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		taskID, attachmentID, err := parseAttachmentIDs(args)
+		if err != nil {
+			return err
+		}
+		dest, _ := cmd.Flags().GetString("dest")
+		force, _ := cmd.Flags().GetBool("force")
+		svc := getServices(cmd)
+
+		if dest == "-" {
+			_, err := svc.Attachment.DownloadTaskAttachment(cmd.Context(), taskID, attachmentID, os.Stdout)
+			return err
+		}
+		msg, err := downloadAttachment(cmd.Context(), svc, taskID, attachmentID, dest, force)
+		if err != nil {
+			fmt.Println("Error downloading attachment:", err)
+			return err
+		}
+		fmt.Println(msg)
+		return nil
+	},
+}
+
+var attachmentDelCmd = &cobra.Command{
+	Use:         "attachment-del [TASK_ID] [ATTACHMENT_ID]",
+	Short:       "Delete a task attachment",
+	Annotations: map[string]string{"skip_mcp": "true"},
+	Args:        cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		taskID, attachmentID, err := parseAttachmentIDs(args)
+		if err != nil {
+			return err
+		}
+		if _, err := getServices(cmd).Attachment.DeleteTaskAttachment(cmd.Context(), taskID, attachmentID); err != nil {
+			return err
+		}
+		fmt.Println("Attachment deleted.")
+		return nil
+	},
+}
+
+func init() {
+	fetchAttachmentCmd.Flags().String("dest", "", "Destination path (default: attachment file name, - for stdout)")
+	fetchAttachmentCmd.Flags().Bool("force", false, "Overwrite an existing destination file")
+
+	rootCmd.AddCommand(attachCmd)
+	rootCmd.AddCommand(attachmentsCmd)
+	rootCmd.AddCommand(fetchAttachmentCmd)
+	rootCmd.AddCommand(attachmentDelCmd)
+}
+
+func parseAttachmentIDs(args []string) (int, int, error) {
+	taskID, err := strconv.Atoi(args[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid task ID: %q", args[0])
+	}
+	attachmentID, err := strconv.Atoi(args[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid attachment ID: %q", args[1])
+	}
+	return taskID, attachmentID, nil
+}
+
+// buildAttachmentList returns a task's attachments rendered in the format
+// selected by out.
+func buildAttachmentList(ctx context.Context, svc Services, taskID int, out render.Options) (string, error) {
+	attachments, err := svc.Attachment.GetTaskAttachments(ctx, taskID)
+	if err != nil {
+		return "", err
+	}
+
+	table := render.Table{Header: []string{"ID", "Name", "Size", "Created"}}
+	for _, a := range attachments {
+		table.AddRow(a.ID, a.File.Name, humanSize(a.File.Size), a.Created.Format("2006-01-02 15:04"))
+	}
+	return out.String(attachments, table)
+}
+
+// uploadAttachment streams a local file to the task as a new attachment.
+func uploadAttachment(ctx context.Context, svc Services, taskID int, path string) (api.TaskAttachment, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return api.TaskAttachment{}, err
+	}
+	defer f.Close()
+	if info, err := f.Stat(); err != nil {
+		return api.TaskAttachment{}, err
+	} else if info.IsDir() {
+		return api.TaskAttachment{}, fmt.Errorf("%s is a directory", path)
+	}
+	return svc.Attachment.UploadTaskAttachment(ctx, taskID, filepath.Base(path), f)
+}
+
+// downloadAttachment streams an attachment to dest. An empty dest uses the
+// attachment's own file name; an existing file is only replaced with force.
+func downloadAttachment(ctx context.Context, svc Services, taskID, attachmentID int, dest string, force bool) (string, error) {
+	if dest == "" {
+		attachments, err := svc.Attachment.GetTaskAttachments(ctx, taskID)
+		if err != nil {
+			return "", err
+		}
+		for _, a := range attachments {
+			if a.ID == attachmentID {
+				dest = filepath.Base(a.File.Name)
+				break
+			}
+		}
+		if dest == "" || dest == "." || dest == string(filepath.Separator) {
+			return "", fmt.Errorf("attachment %d not found on task %d", attachmentID, taskID)
+		}
+	}
+	if info, err := os.Stat(dest); err == nil && info.IsDir() {
+		return "", fmt.Errorf("%s is a directory", dest)
+	} else if err == nil && !force {
+		return "", fmt.Errorf("%s already exists (use --force to overwrite)", dest)
+	} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+
+	// Write to a temporary file next to dest so a failed download never
+	// leaves a truncated file behind.
+	tmp, err := os.CreateTemp(filepath.Dir(dest), "."+filepath.Base(dest)+".*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	n, err := svc.Attachment.DownloadTaskAttachment(ctx, taskID, attachmentID, tmp)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return "", err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), dest); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Saved %s (%s)", dest, humanSize(n)), nil
+}
+
+// humanSize formats a byte count using binary units.
+func humanSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
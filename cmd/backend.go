@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"sync"
+
+	"kunja/adapter/memory"
+
+	"github.com/spf13/viper"
+)
+
+// memoryBackend is shared by every Services built in this process, so the
+// REPL and the MCP server keep their changes between commands and tool
+// calls.
+var memoryBackend struct {
+	sync.Once
+	adapter *memory.Adapter
+}
+
+// useMemoryBackend reports whether the config selects the in-memory
+// backend instead of a Vikunja server.
+func useMemoryBackend() (bool, error) {
+	switch backend := viper.GetString("backend"); backend {
+	case "", "vikunja":
+		return false, nil
+	case "memory":
+		return true, nil
+	default:
+		return false, fmt.Errorf("unknown backend %q (use vikunja or memory)", backend)
+	}
+}
+
+// memoryServices returns Services backed by the demo data of the
+// in-memory backend.
+func memoryServices() Services {
+	memoryBackend.Do(func() {
+		memoryBackend.adapter = memory.Demo()
+		if model, err := urgencyModel(); err == nil {
+			memoryBackend.adapter.Urgency = &model
+		}
+	})
+	return adapterServices(memoryBackend.adapter)
+}
+
+// adapterServices returns Services that all use the memory adapter a.
+func adapterServices(a *memory.Adapter) Services {
+	return Services{
+		Auth:       a,
+		Task:       a,
+		Project:    a,
+		User:       a,
+		Label:      a,
+		Bucket:     a,
+		Comment:    a,
+		Attachment: a,
+		Relation:   a,
+		Filter:     a,
+	}
+}
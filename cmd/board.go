@@ -0,0 +1,232 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"kunja/api"
+
+	"github.com/spf13/cobra"
+)
+
+// boardColumnWidth is the width (in runes) of a single bucket column.
+const boardColumnWidth = 26
+
+var boardCmd = &cobra.Command{
+	Use:   "board [PROJECT_ID]",
+	Short: "Show the kanban board of a project (arg PROJECT_ID)",
+	Long:  `Render the buckets of a project's kanban view as columns, each listing the tasks it contains. Use --view to pick a specific kanban view when a project has several.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectID, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("project ID must be a number")
+		}
+		viewID, _ := cmd.Flags().GetInt("view")
+		out, err := buildBoard(cmd.Context(), getServices(cmd), projectID, viewID)
+		if err != nil {
+			fmt.Println("Error retrieving board:", err)
+			return err
+		}
+		fmt.Print(out)
+		return nil
+	},
+}
+
+var moveCmd = &cobra.Command{
+	Use:   "move [TASK_ID] [BUCKET]",
+	Short: "Move a task to another kanban bucket (by bucket ID or name)",
+	Long:  `Move a task into another bucket of its project's kanban view. BUCKET may be a bucket ID or its (case-insensitive) title. The move is refused when the target bucket has reached its WIP limit; --force leaves that check to the server, which may still refuse it.`,
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		taskID, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid task ID: %q", args[0])
+		}
+		viewID, _ := cmd.Flags().GetInt("view")
+		force, _ := cmd.Flags().GetBool("force")
+		msg, err := moveTaskToBucket(cmd.Context(), getServices(cmd), taskID, args[1], viewID, force)
+		if err != nil {
+			fmt.Println("Error moving task:", err)
+			return err
+		}
+		fmt.Println(msg)
+		return nil
+	},
+}
+
+func init() {
+	boardCmd.Flags().Int("view", 0, "Kanban view ID (defaults to the project's first kanban view)")
+	moveCmd.Flags().Int("view", 0, "Kanban view ID (defaults to the project's first kanban view)")
+	moveCmd.Flags().Bool("force", false, "Attempt the move even if the bucket is at its WIP limit")
+	rootCmd.AddCommand(boardCmd)
+	rootCmd.AddCommand(moveCmd)
+}
+
+// kanbanView returns the requested view of a project, or its first kanban
+// view when viewID is 0.
+func kanbanView(ctx context.Context, svc Services, projectID, viewID int) (api.ProjectView, error) {
+	views, err := svc.Bucket.GetProjectViews(ctx, projectID)
+	if err != nil {
+		return api.ProjectView{}, err
+	}
+	for _, v := range views {
+		if viewID != 0 && v.ID == viewID {
+			if v.ViewKind != "kanban" {
+				return api.ProjectView{}, fmt.Errorf("view %d is a %s view, not kanban", v.ID, v.ViewKind)
+			}
+			return v, nil
+		}
+		if viewID == 0 && v.ViewKind == "kanban" {
+			return v, nil
+		}
+	}
+	if viewID != 0 {
+		return api.ProjectView{}, fmt.Errorf("project %d has no view %d", projectID, viewID)
+	}
+	return api.ProjectView{}, fmt.Errorf("project %d has no kanban view", projectID)
+}
+
+// buildBoard renders the kanban view of a project as side-by-side columns.
+func buildBoard(ctx context.Context, svc Services, projectID, viewID int) (string, error) {
+	view, err := kanbanView(ctx, svc, projectID, viewID)
+	if err != nil {
+		return "", err
+	}
+	buckets, err := svc.Bucket.GetBucketTasks(ctx, projectID, view.ID)
+	if err != nil {
+		return "", err
+	}
+	sort.SliceStable(buckets, func(i, j int) bool { return buckets[i].Position < buckets[j].Position })
+	return renderBoard(buckets, view.DoneBucketID), nil
+}
+
+// renderBoard lays out buckets as columns. The done bucket is marked with ✓
+// and a bucket at or above its WIP limit with !.
+func renderBoard(buckets []api.Bucket, doneBucketID int) string {
+	if len(buckets) == 0 {
+		return "No buckets.\n"
+	}
+
+	var b strings.Builder
+	rows := 0
+	for i, bk := range buckets {
+		header := bk.Title
+		if bk.Limit > 0 {
+			header += fmt.Sprintf(" (%d/%d)", bucketSize(bk), bk.Limit)
+			if bucketSize(bk) >= bk.Limit {
+				header += " !"
+			}
+		} else {
+			header += fmt.Sprintf(" (%d)", bucketSize(bk))
+		}
+		if bk.ID == doneBucketID || bk.IsDoneBucket {
+			header += " ✓"
+		}
+		writeBoardCell(&b, header, i == len(buckets)-1)
+		if len(bk.Tasks) > rows {
+			rows = len(bk.Tasks)
+		}
+	}
+	for i := range buckets {
+		writeBoardCell(&b, strings.Repeat("─", boardColumnWidth-1), i == len(buckets)-1)
+	}
+	for r := 0; r < rows; r++ {
+		for i, bk := range buckets {
+			cell := ""
+			if r < len(bk.Tasks) {
+				t := bk.Tasks[r]
+				cell = fmt.Sprintf("#%d %s", t.ID, t.Title)
+				if t.Done {
+					cell = fmt.Sprintf("#%d ✓ %s", t.ID, t.Title)
+				}
+			}
+			writeBoardCell(&b, cell, i == len(buckets)-1)
+		}
+	}
+	return b.String()
+}
+
+// bucketSize returns the number of tasks in a bucket. The view endpoint
+// pages the tasks of each bucket, so Tasks may hold only the first of
+// them; CountTasks is the server's count of all.
+func bucketSize(b api.Bucket) int {
+	return max(b.CountTasks, len(b.Tasks))
+}
+
+// writeBoardCell writes one fixed-width column cell, truncating long text.
+func writeBoardCell(b *strings.Builder, text string, last bool) {
+	r := []rune(text)
+	if len(r) > boardColumnWidth-1 {
+		r = append(r[:boardColumnWidth-2], '…')
+	}
+	b.WriteString(string(r))
+	if last {
+		b.WriteString("\n")
+		return
+	}
+	b.WriteString(strings.Repeat(" ", boardColumnWidth-len(r)))
+}
+
+// moveTaskToBucket moves a task into the referenced bucket of its project's
+// kanban view. A move that would exceed the bucket's WIP limit is refused
+// unless force is set; if the server then accepts it, the returned message
+// warns about the exceeded limit.
+func moveTaskToBucket(ctx context.Context, svc Services, taskID int, bucketRef string, viewID int, force bool) (string, error) {
+	task, err := svc.Task.GetTask(ctx, taskID)
+	if err != nil {
+		return "", err
+	}
+	view, err := kanbanView(ctx, svc, task.ProjectID, viewID)
+	if err != nil {
+		return "", err
+	}
+	buckets, err := svc.Bucket.GetBucketTasks(ctx, task.ProjectID, view.ID)
+	if err != nil {
+		return "", err
+	}
+
+	var target *api.Bucket
+	if id, err := strconv.Atoi(bucketRef); err == nil {
+		for i := range buckets {
+			if buckets[i].ID == id {
+				target = &buckets[i]
+				break
+			}
+		}
+	} else {
+		for i := range buckets {
+			if strings.EqualFold(buckets[i].Title, strings.TrimSpace(bucketRef)) {
+				target = &buckets[i]
+				break
+			}
+		}
+	}
+	if target == nil {
+		return "", fmt.Errorf("bucket not found in project %d: %q", task.ProjectID, bucketRef)
+	}
+
+	if task.BucketID == target.ID {
+		return fmt.Sprintf("Task %d is already in bucket %q", taskID, target.Title), nil
+	}
+	for _, t := range target.Tasks {
+		if t.ID == taskID {
+			return fmt.Sprintf("Task %d is already in bucket %q", taskID, target.Title), nil
+		}
+	}
+	msg := fmt.Sprintf("Task %d moved to bucket %q", taskID, target.Title)
+	if size := bucketSize(*target); target.Limit > 0 && size+1 > target.Limit {
+		if !force {
+			return "", fmt.Errorf("bucket %q is at its WIP limit (%d/%d); use --force to move task %d anyway", target.Title, size, target.Limit, taskID)
+		}
+		msg += fmt.Sprintf("\nWarning: bucket %q is now over its WIP limit (%d/%d)", target.Title, size+1, target.Limit)
+	}
+
+	if _, err := svc.Bucket.MoveTaskToBucket(ctx, task.ProjectID, view.ID, target.ID, taskID); err != nil {
+		return "", err
+	}
+	return msg, nil
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"kunja/adapter/cache"
+
+	"github.com/spf13/viper"
+)
+
+// NoCache disables the in-memory cache of projects, users, labels and tasks.
+var NoCache bool
+
+// serviceCache holds a cache per server and token, shared by every
+// Services built in this process for them, so the MCP server, which builds
+// them per tool call, keeps its cache between calls.
+var serviceCache struct {
+	sync.Mutex
+	caches map[string]*cache.Cache
+}
+
+// cacheTTLs returns the default times to live overridden by the "cache"
+// section of the config file.
+func cacheTTLs() (cache.TTLs, error) {
+	ttl := cache.DefaultTTLs()
+	if err := viper.UnmarshalKey("cache", &ttl); err != nil {
+		return ttl, fmt.Errorf("invalid cache config: %w", err)
+	}
+	return ttl, nil
+}
+
+// cacheKey identifies the cache of an account on a server.
+func cacheKey(baseURL, token string) string {
+	return baseURL + " " + token
+}
+
+// withServiceCache routes the task, project, label and user services
+// through the in-memory cache of the configured account unless --no-cache
+// is given; the bucket, comment, attachment and relation services drop its
+// tasks when they change them. If the cache config is invalid svc is
+// returned unchanged.
+func withServiceCache(svc Services) Services {
+	return withServiceCacheFor(svc, cacheKey(viper.GetString("baseurl"), viper.GetString("token")))
+}
+
+// withServiceCacheFor is withServiceCache for the account identified by
+// key, see cacheKey.
+func withServiceCacheFor(svc Services, key string) Services {
+	if viper.GetBool("no-cache") {
+		return svc
+	}
+
+	serviceCache.Lock()
+	c, ok := serviceCache.caches[key]
+	if !ok {
+		ttl, err := cacheTTLs()
+		if err != nil {
+			serviceCache.Unlock()
+			fmt.Fprintln(os.Stderr, "Warning:", err)
+			return svc
+		}
+		if serviceCache.caches == nil {
+			serviceCache.caches = map[string]*cache.Cache{}
+		}
+		c = cache.New(ttl)
+		serviceCache.caches[key] = c
+	}
+	serviceCache.Unlock()
+
+	cached := c.Wrap(svc.Task, svc.Project, svc.Label, svc.User)
+	svc.Task = cached
+	svc.Project = cached
+	svc.Label = cached
+	svc.User = cached
+	svc.Bucket = c.WrapBuckets(svc.Bucket)
+	svc.Comment = c.WrapComments(svc.Comment)
+	svc.Attachment = c.WrapAttachments(svc.Attachment)
+	svc.Relation = c.WrapRelations(svc.Relation)
+	return svc
+}
+
+// flushServiceCache drops everything cached so far, so the next command
+// sees the server's current state.
+func flushServiceCache() {
+	serviceCache.Lock()
+	defer serviceCache.Unlock()
+	for _, c := range serviceCache.caches {
+		c.Flush()
+	}
+}
+
+// dropServiceCache forgets the cache of the account identified by key,
+// e.g. when an MCP session using it ends.
+func dropServiceCache(key string) {
+	serviceCache.Lock()
+	defer serviceCache.Unlock()
+	delete(serviceCache.caches, key)
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&NoCache, "no-cache", false, "always fetch from the server instead of the in-memory cache")
+	viper.BindPFlag("no-cache", rootCmd.PersistentFlags().Lookup("no-cache"))
+}
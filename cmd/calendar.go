@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"kunja/api"
+	"kunja/internal/render"
+
+	"github.com/spf13/cobra"
+)
+
+// calendarDay is a day of the calendar that has tasks.
+type calendarDay struct {
+	Date  string     `json:"date"`
+	Count int        `json:"count"`
+	Tasks []api.Task `json:"tasks"`
+}
+
+// calendarCellWidth is the width of a day in the month grid: the day number
+// right-aligned in three columns, then the task count such as "(3)".
+const calendarCellWidth = 7
+
+// taskDays returns the days of [first, next) a task falls on: its due day,
+// and every day between its start and end date.
+func taskDays(t api.Task, first, next time.Time) []time.Time {
+	seen := map[time.Time]bool{}
+	var days []time.Time
+	add := func(d time.Time) {
+		d = startOfDay(d)
+		if !d.Before(first) && d.Before(next) && !seen[d] {
+			seen[d] = true
+			days = append(days, d)
+		}
+	}
+	if !t.DueDate.IsZero() {
+		add(t.DueDate.Local())
+	}
+	if !t.StartDate.IsZero() && !t.EndDate.IsZero() {
+		d := startOfDay(t.StartDate.Local())
+		if d.Before(first) {
+			d = first
+		}
+		for ; !d.After(t.EndDate.Local()) && d.Before(next); d = d.AddDate(0, 0, 1) {
+			add(d)
+		}
+	}
+	return days
+}
+
+// calendarDays distributes tasks over the days of the month starting at
+// first. The result has one entry per day of the month.
+func calendarDays(tasks []api.Task, first time.Time) []calendarDay {
+	next := first.AddDate(0, 1, 0)
+	days := make([]calendarDay, next.AddDate(0, 0, -1).Day())
+	for i := range days {
+		days[i].Date = first.AddDate(0, 0, i).Format("2006-01-02")
+	}
+	for _, t := range tasks {
+		for _, d := range taskDays(t, first, next) {
+			day := &days[d.Day()-1]
+			day.Count++
+			day.Tasks = append(day.Tasks, t)
+		}
+	}
+	return days
+}
+
+// writeMonthGrid draws the month as a Monday-first grid with the number of
+// tasks per day; today is marked with an asterisk.
+func writeMonthGrid(b *strings.Builder, first time.Time, days []calendarDay, now time.Time) {
+	var line strings.Builder
+	endLine := func() {
+		b.WriteString(strings.TrimRight(line.String(), " ") + "\n")
+		line.Reset()
+	}
+
+	title := first.Format("January 2006")
+	fmt.Fprintf(&line, "%*s", (7*calendarCellWidth+len(title))/2, title)
+	endLine()
+	for _, name := range []string{"Mo", "Tu", "We", "Th", "Fr", "Sa", "Su"} {
+		fmt.Fprintf(&line, "%3s%-*s", name, calendarCellWidth-3, "")
+	}
+	endLine()
+
+	col := (int(first.Weekday()) + 6) % 7
+	line.WriteString(strings.Repeat(" ", col*calendarCellWidth))
+	today := startOfDay(now)
+	for i, day := range days {
+		num := fmt.Sprint(i + 1)
+		if first.AddDate(0, 0, i).Equal(today) {
+			num = "*" + num
+		}
+		count := ""
+		if day.Count > 0 {
+			count = fmt.Sprintf("(%d)", day.Count)
+		}
+		fmt.Fprintf(&line, "%3s%-*s", num, calendarCellWidth-3, count)
+		if col = (col + 1) % 7; col == 0 || i == len(days)-1 {
+			endLine()
+		}
+	}
+}
+
+// buildCalendar returns the month grid starting at first with the number of
+// tasks due or scheduled on each day, followed by the tasks per day, or the
+// days with tasks in the format selected by out. Done tasks are included
+// when all is set.
+func buildCalendar(ctx context.Context, svc Services, first time.Time, all bool, out render.Options) (string, error) {
+	days, err := queryCalendar(ctx, svc, first, all)
+	if err != nil {
+		return "", err
+	}
+	return renderCalendar(first, days, out)
+}
+
+// queryCalendar returns the days of the month starting at first with the
+// tasks due or scheduled on each. Done tasks are included when all is set.
+func queryCalendar(ctx context.Context, svc Services, first time.Time, all bool) ([]calendarDay, error) {
+	next := first.AddDate(0, 1, 0)
+	from, to := first.UTC().Format(time.RFC3339), next.UTC().Format(time.RFC3339)
+	query := fmt.Sprintf("((due_date >= %s && due_date < %s) || (start_date < %s && end_date >= %s))", from, to, to, from)
+	if !all {
+		query = "done = false && " + query
+	}
+	tasks, err := fetchTasks(ctx, svc, api.GetAllTasksParams{Filter: query}, 0)
+	if err != nil {
+		return nil, err
+	}
+	return calendarDays(tasks, first), nil
+}
+
+// busyDays returns the days that have tasks.
+func busyDays(days []calendarDay) []calendarDay {
+	busy := []calendarDay{}
+	for _, d := range days {
+		if d.Count > 0 {
+			busy = append(busy, d)
+		}
+	}
+	return busy
+}
+
+// renderCalendar renders the days of the month starting at first in the
+// format selected by out.
+func renderCalendar(first time.Time, days []calendarDay, out render.Options) (string, error) {
+	busy := busyDays(days)
+	if !out.Tabular() {
+		table := render.Table{Header: []string{"Date", "Count", "Tasks"}}
+		for _, d := range busy {
+			ids := make([]string, len(d.Tasks))
+			for i, t := range d.Tasks {
+				ids[i] = fmt.Sprint(t.ID)
+			}
+			table.AddRow(d.Date, d.Count, strings.Join(ids, ","))
+		}
+		return out.String(busy, table)
+	}
+
+	var b strings.Builder
+	writeMonthGrid(&b, first, days, time.Now())
+	if len(busy) > 0 {
+		b.WriteString("\n")
+	}
+	table := render.Table{}
+	for _, d := range busy {
+		date, _ := time.ParseInLocation("2006-01-02", d.Date, first.Location())
+		titles := make([]string, len(d.Tasks))
+		for i, t := range d.Tasks {
+			titles[i] = fmt.Sprintf("#%d %s", t.ID, t.Title)
+		}
+		table.AddRow(date.Format("Mon 02"), strings.Join(titles, ", "))
+	}
+	if err := out.Render(&b, nil, table); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+var calendarCmd = &cobra.Command{
+	Use:   "calendar",
+	Short: "Draw a month calendar with the number of tasks per day",
+	Long:  `Draw a month grid with the number of open tasks due on, or scheduled across (start to end date), each day, followed by the tasks of each day. Add --all to count done tasks as well.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		month, _ := cmd.Flags().GetString("month")
+		first, err := parseMonth(month, time.Now())
+		if err != nil {
+			return err
+		}
+		opts, err := outputOptions()
+		if err != nil {
+			return err
+		}
+		out, err := buildCalendar(cmd.Context(), getServices(cmd), first, ShowAll, opts)
+		if err != nil {
+			fmt.Println("Error building calendar:", err)
+			return err
+		}
+		fmt.Print(out)
+		return nil
+	},
+}
+
+// parseMonth returns the first day of the month given as YYYY-MM in local
+// time, or of the current month when s is empty.
+func parseMonth(s string, now time.Time) (time.Time, error) {
+	if strings.TrimSpace(s) == "" {
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local), nil
+	}
+	first, err := time.ParseInLocation("2006-01", strings.TrimSpace(s), time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid month %q (use YYYY-MM)", s)
+	}
+	return first, nil
+}
+
+func init() {
+	calendarCmd.Flags().String("month", "", "month to show as YYYY-MM (default: current month)")
+	rootCmd.AddCommand(calendarCmd)
+}
@@ -0,0 +1,266 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"kunja/api"
+	"kunja/internal/render"
+
+	"github.com/spf13/cobra"
+)
+
+// commentScissors separates the comment from the hint below it in the
+// editor buffer. Only the text above it is kept, so comments may contain
+// markdown headings and other lines starting with #.
+const commentScissors = "# ------------------------ >8 ------------------------"
+
+// commentEditorHint is appended to the editor buffer.
+const commentEditorHint = "\n" + commentScissors + "\n# Write the comment above this line. Everything below it is ignored.\n"
+
+// editComment opens text in $EDITOR and returns the comment written above
+// the scissors line.
+func editComment(text string) (string, error) {
+	content, err := editInEditor(text + "\n" + commentEditorHint)
+	if err != nil {
+		return "", err
+	}
+	if i := strings.Index(content, commentScissors); i >= 0 {
+		content = content[:i]
+	}
+	return content, nil
+}
+
+var commentCmd = &cobra.Command{
+	Use:   "comment [TASK_ID] [text]",
+	Short: "List or add task comments (arg TASK_ID [text])",
+	Long:  `Without text, list the comments of a task. With text, add it as a new comment. Use the list/add/edit/delete subcommands for full control; add and edit open $EDITOR when no text is given.`,
+	Args:  cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return cmd.Help()
+		}
+		taskID, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid task ID: %q", args[0])
+		}
+		svc := getServices(cmd)
+		if len(args) == 1 {
+			opts, err := outputOptions()
+			if err != nil {
+				return err
+			}
+			out, err := buildCommentList(cmd.Context(), svc, taskID, opts)
+			if err != nil {
+				fmt.Println("Error retrieving comments:", err)
+				return err
+			}
+			fmt.Print(out)
+			return nil
+		}
+		_, msg, err := addTaskComment(cmd.Context(), svc, taskID, strings.Join(args[1:], " "))
+		if err != nil {
+			fmt.Println("Error adding comment:", err)
+			return err
+		}
+		fmt.Println(msg)
+		return nil
+	},
+}
+
+var commentListCmd = &cobra.Command{
+	Use:   "list [TASK_ID]",
+	Short: "List the comments of a task",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		taskID, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid task ID: %q", args[0])
+		}
+		opts, err := outputOptions()
+		if err != nil {
+			return err
+		}
+		out, err := buildCommentList(cmd.Context(), getServices(cmd), taskID, opts)
+		if err != nil {
+			fmt.Println("Error retrieving comments:", err)
+			return err
+		}
+		fmt.Print(out)
+		return nil
+	},
+}
+
+var commentAddCmd = &cobra.Command{
+	Use:   "add [TASK_ID] [text...]",
+	Short: "Add a comment to a task (opens $EDITOR without text)",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		taskID, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid task ID: %q", args[0])
+		}
+		text := strings.Join(args[1:], " ")
+		if text == "" {
+			text, err = editComment("")
+			if err != nil {
+				fmt.Println("Error editing comment:", err)
+				return err
+			}
+		}
+		_, msg, err := addTaskComment(cmd.Context(), getServices(cmd), taskID, text)
+		if err != nil {
+			fmt.Println("Error adding comment:", err)
+			return err
+		}
+		fmt.Println(msg)
+		return nil
+	},
+}
+
+var commentEditCmd = &cobra.Command{
+	Use:   "edit [TASK_ID] [COMMENT_ID] [text...]",
+	Short: "Edit a comment (opens $EDITOR without text)",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		taskID, commentID, err := parseCommentIDs(args)
+		if err != nil {
+			return err
+		}
+		svc := getServices(cmd)
+		text := strings.Join(args[2:], " ")
+		if text == "" {
+			current, err := svc.Comment.GetTaskComment(cmd.Context(), taskID, commentID)
+			if err != nil {
+				fmt.Println("Error getting comment:", err)
+				return err
+			}
+			text, err = editComment(current.Comment)
+			if err != nil {
+				fmt.Println("Error editing comment:", err)
+				return err
+			}
+		}
+		_, msg, err := editTaskComment(cmd.Context(), svc, taskID, commentID, text)
+		if err != nil {
+			fmt.Println("Error updating comment:", err)
+			return err
+		}
+		fmt.Println(msg)
+		return nil
+	},
+}
+
+var commentDeleteCmd = &cobra.Command{
+	Use:         "delete [TASK_ID] [COMMENT_ID]",
+	Short:       "Delete a comment",
+	Annotations: map[string]string{"skip_mcp": "true"},
+	Args:        cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		taskID, commentID, err := parseCommentIDs(args)
+		if err != nil {
+			return err
+		}
+		if _, err := getServices(cmd).Comment.DeleteTaskComment(cmd.Context(), taskID, commentID); err != nil {
+			fmt.Println("Error deleting comment:", err)
+			return err
+		}
+		fmt.Println("Comment deleted.")
+		return nil
+	},
+}
+
+func init() {
+	commentCmd.AddCommand(commentListCmd)
+	commentCmd.AddCommand(commentAddCmd)
+	commentCmd.AddCommand(commentEditCmd)
+	commentCmd.AddCommand(commentDeleteCmd)
+	rootCmd.AddCommand(commentCmd)
+}
+
+func parseCommentIDs(args []string) (int, int, error) {
+	taskID, err := strconv.Atoi(args[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid task ID: %q", args[0])
+	}
+	commentID, err := strconv.Atoi(args[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid comment ID: %q", args[1])
+	}
+	return taskID, commentID, nil
+}
+
+// ---------------------------------------------------------------------
+// Shared helpers used by both CLI commands and native MCP tools
+// ---------------------------------------------------------------------
+
+// buildCommentList returns the comments of a task rendered in the format
+// selected by out. The table format shows each comment as a readable block
+// rather than a row.
+func buildCommentList(ctx context.Context, svc Services, taskID int, out render.Options) (string, error) {
+	comments, err := svc.Comment.GetTaskComments(ctx, taskID)
+	if err != nil {
+		return "", err
+	}
+	return renderCommentList(taskID, comments, out)
+}
+
+// renderCommentList renders the comments of a task in the format selected
+// by out.
+func renderCommentList(taskID int, comments []api.TaskComment, out render.Options) (string, error) {
+	if !out.Tabular() {
+		table := render.Table{Header: []string{"ID", "Author", "Created", "Updated", "Comment"}}
+		for _, c := range comments {
+			table.AddRow(c.ID, c.Author.Username, c.Created.Format(time.RFC3339), c.Updated.Format(time.RFC3339), strings.TrimSpace(c.Comment))
+		}
+		return out.String(comments, table)
+	}
+
+	if len(comments) == 0 {
+		return fmt.Sprintf("Task %d has no comments.\n", taskID), nil
+	}
+	var b strings.Builder
+	for _, c := range comments {
+		author := c.Author.Username
+		if c.Author.Name != "" {
+			author = c.Author.Name
+		}
+		fmt.Fprintf(&b, "#%d  %s  %s", c.ID, author, c.Created.Format("2006-01-02 15:04"))
+		if c.Updated.After(c.Created) {
+			b.WriteString(" (edited)")
+		}
+		fmt.Fprintf(&b, "\n%s\n\n", strings.TrimSpace(c.Comment))
+	}
+	return b.String(), nil
+}
+
+// addTaskComment creates a comment after trimming surrounding whitespace
+// and returns it with a message for the user.
+func addTaskComment(ctx context.Context, svc Services, taskID int, text string) (api.TaskComment, string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return api.TaskComment{}, "", fmt.Errorf("comment text must not be empty")
+	}
+	c, err := svc.Comment.CreateTaskComment(ctx, taskID, text)
+	if err != nil {
+		return api.TaskComment{}, "", err
+	}
+	return c, fmt.Sprintf("Comment added: %d", c.ID), nil
+}
+
+// editTaskComment replaces the text of an existing comment and returns the
+// updated comment with a message for the user.
+func editTaskComment(ctx context.Context, svc Services, taskID, commentID int, text string) (api.TaskComment, string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return api.TaskComment{}, "", fmt.Errorf("comment text must not be empty")
+	}
+	c, err := svc.Comment.UpdateTaskComment(ctx, taskID, commentID, text)
+	if err != nil {
+		return api.TaskComment{}, "", err
+	}
+	return c, "Comment updated successfully", nil
+}
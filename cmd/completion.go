@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"kunja/api"
+
+	"github.com/spf13/cobra"
+)
+
+// Kinds of values offered by completion.
+const (
+	completeTasks         = "tasks"          // open task IDs
+	completeProjects      = "projects"       // project IDs
+	completeProjectTitles = "project-titles" // project titles
+	completeLabels        = "labels"         // label titles
+	completeUsers         = "users"          // usernames
+	completeFilters       = "filters"        // saved filter titles
+)
+
+// completionTimeout bounds the API calls made while completing, so a slow
+// server does not freeze the prompt.
+const completionTimeout = 5 * time.Second
+
+// completionCacheTTL is how long shell completion reuses fetched values, so
+// that repeated TAB presses do not each hit the server.
+const completionCacheTTL = 30 * time.Second
+
+// completionCacheEntry is the cached result of one completionItems call.
+type completionCacheEntry struct {
+	Fetched time.Time `json:"fetched"`
+	Items   []string  `json:"items"`
+}
+
+func completionCachePath() string {
+	return filepath.Join(ConfigDir, "completion-cache.json")
+}
+
+// clearCompletionCache removes the on-disk completion cache, e.g. after a
+// command that may have changed tasks.
+func clearCompletionCache() {
+	if ConfigDir != "" {
+		os.Remove(completionCachePath())
+	}
+}
+
+// cachedCompletionItems returns completionItems(kind), served from the
+// on-disk cache while it is younger than completionCacheTTL. The cache is
+// keyed by account (see accountHash) so that switching server or user never
+// offers another account's values. Without a ConfigDir nothing is cached.
+func cachedCompletionItems(ctx context.Context, kind string) ([]string, error) {
+	key := accountHash() + " " + kind
+	path := completionCachePath()
+	cache := map[string]completionCacheEntry{}
+	if ConfigDir != "" {
+		if data, err := os.ReadFile(path); err == nil {
+			json.Unmarshal(data, &cache)
+		}
+	}
+	if e, ok := cache[key]; ok && time.Since(e.Fetched) < completionCacheTTL {
+		return e.Items, nil
+	}
+
+	if ctx == nil {
+		ctx = context.Background() // commands that have not run yet have no context
+	}
+	ctx, cancel := context.WithTimeout(ctx, completionTimeout)
+	defer cancel()
+	ctx, svc, err := prepareServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items, err := completionItems(ctx, svc, kind)
+	if err != nil {
+		return nil, err
+	}
+	if ConfigDir == "" {
+		return items, nil
+	}
+	cache[key] = completionCacheEntry{Fetched: time.Now(), Items: items}
+	for k, e := range cache {
+		if time.Since(e.Fetched) >= completionCacheTTL {
+			delete(cache, k)
+		}
+	}
+	if data, err := json.Marshal(cache); err == nil {
+		os.WriteFile(path, data, 0o600)
+	}
+	return items, nil
+}
+
+// completionItems fetches the values of kind as "value\tdescription", the
+// format shells show as a value with a hint.
+func completionItems(ctx context.Context, svc Services, kind string) ([]string, error) {
+	var items []string
+	switch kind {
+	case completeTasks:
+		tasks, err := fetchTasks(ctx, svc, api.GetAllTasksParams{Filter: "done = false"}, 200)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range tasks {
+			items = append(items, fmt.Sprintf("%d\t%s", t.ID, t.Title))
+		}
+	case completeProjects, completeProjectTitles:
+		projects, err := svc.Project.GetAllProjects(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range projects {
+			if p.ID <= 0 {
+				continue // pseudo projects of saved filters
+			}
+			if kind == completeProjects {
+				items = append(items, fmt.Sprintf("%d\t%s", p.ID, p.Title))
+			} else {
+				items = append(items, fmt.Sprintf("%s\t#%d", p.Title, p.ID))
+			}
+		}
+	case completeLabels:
+		labels, err := svc.Label.GetAllLabels(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range labels {
+			items = append(items, fmt.Sprintf("%s\t#%d", l.Title, l.ID))
+		}
+	case completeUsers:
+		users, err := svc.User.GetAllUsers(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range users {
+			if u.Name == "" {
+				items = append(items, u.Username)
+			} else {
+				items = append(items, u.Username+"\t"+u.Name)
+			}
+		}
+	case completeFilters:
+		filters, err := svc.Filter.GetSavedFilters(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range filters {
+			items = append(items, fmt.Sprintf("%s\t#%d", f.Title, f.ID))
+		}
+	default:
+		return nil, fmt.Errorf("unknown completion kind %q", kind)
+	}
+	return items, nil
+}
+
+// matchCompletions keeps the items whose value starts with toComplete and
+// is not in exclude.
+func matchCompletions(items []string, toComplete string, exclude []string) []string {
+	var out []string
+	for _, item := range items {
+		value, _, _ := strings.Cut(item, "\t")
+		if !strings.HasPrefix(strings.ToLower(value), strings.ToLower(toComplete)) {
+			continue
+		}
+		used := false
+		for _, e := range exclude {
+			used = used || e == value
+		}
+		if !used {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// completeArgs returns a ValidArgsFunction that completes the first
+// positional argument with values of kind, or every argument when repeat is
+// set. Values already given are not offered again.
+func completeArgs(kind string, repeat bool) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 && !repeat {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		items, err := cachedCompletionItems(cmd.Context(), kind)
+		if err != nil {
+			cobra.CompErrorln(err.Error())
+			return nil, cobra.ShellCompDirectiveError
+		}
+		return matchCompletions(items, toComplete, args), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeFlag returns a flag completion function offering values of kind.
+func completeFlag(kind string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		items, err := cachedCompletionItems(cmd.Context(), kind)
+		if err != nil {
+			cobra.CompErrorln(err.Error())
+			return nil, cobra.ShellCompDirectiveError
+		}
+		return matchCompletions(items, toComplete, nil), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// registerTaskFilterCompletions adds value completion to the flags added by
+// addTaskFilterFlags.
+func registerTaskFilterCompletions(cmd *cobra.Command) {
+	cmd.RegisterFlagCompletionFunc("project", completeFlag(completeProjectTitles))
+	cmd.RegisterFlagCompletionFunc("label", completeFlag(completeLabels))
+	cmd.RegisterFlagCompletionFunc("assignee", completeFlag(completeUsers))
+	cmd.RegisterFlagCompletionFunc("columns", cobra.FixedCompletions(taskColumnNames, cobra.ShellCompDirectiveNoFileComp))
+}
+
+// isCompletionCmd reports whether cmd generates or answers shell completion;
+// such runs need no login and must not start the REPL.
+func isCompletionCmd(cmd *cobra.Command) bool {
+	switch cmd.Name() {
+	case cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd, "completion":
+		return true
+	}
+	return false
+}
+
+var completionCmd = &cobra.Command{
+	Use:   "completion [bash|zsh|fish]",
+	Short: "Generate the shell completion script",
+	Long: `Print the completion script for bash, zsh or fish. Besides commands and
+flags it completes task IDs, project IDs, labels and users by asking the
+server, showing titles next to the IDs. Fetched values are cached for 30
+seconds in the config directory.
+
+  bash:  source <(kunja completion bash)
+  zsh:   kunja completion zsh > "${fpath[1]}/_kunja"
+  fish:  kunja completion fish > ~/.config/fish/completions/kunja.fish`,
+	Annotations:           map[string]string{"skip_mcp": "true"},
+	ValidArgs:             []string{"bash", "zsh", "fish"},
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	DisableFlagsInUseLine: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+		switch args[0] {
+		case "bash":
+			return cmd.Root().GenBashCompletionV2(out, true)
+		case "zsh":
+			return cmd.Root().GenZshCompletion(out)
+		default:
+			return cmd.Root().GenFishCompletion(out, true)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+}
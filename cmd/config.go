@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"os"
@@ -25,6 +27,18 @@ func defaultConfigDir() string {
 	return filepath.Join(".", "."+AppName)
 }
 
+// accountHash identifies the configured account in the files kept in
+// ConfigDir: a hash of the server URL and the username or, without one, the
+// token.
+func accountHash() string {
+	account := viper.GetString("username")
+	if account == "" {
+		account = viper.GetString("token")
+	}
+	sum := sha256.Sum256([]byte(viper.GetString("baseurl") + "\n" + account))
+	return hex.EncodeToString(sum[:8])
+}
+
 func legacyConfigDir() string {
 	if home, err := os.UserHomeDir(); err == nil && home != "" {
 		return filepath.Join(home, "."+AppName)
@@ -0,0 +1,308 @@
+package cmd_test
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"kunja/adapter/memory"
+	"kunja/api"
+	"kunja/internal/vikunjatest"
+)
+
+// The tests in this file build the kunja binary and run its commands
+// against the fake Vikunja server of package vikunjatest, checking their
+// output and the requests they send. -short skips them.
+
+// kunjaBin is the binary built by TestMain.
+var kunjaBin string
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	if testing.Short() {
+		os.Exit(m.Run())
+	}
+	dir, err := os.MkdirTemp("", "kunja-test-")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+	kunjaBin = filepath.Join(dir, "kunja")
+	if out, err := exec.Command("go", "build", "-o", kunjaBin, "kunja").CombinedOutput(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error building kunja: %v\n%s", err, out)
+		os.RemoveAll(dir)
+		os.Exit(1)
+	}
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// env is a fake server with the demo data and a config directory pointing
+// kunja at it.
+type env struct {
+	t   *testing.T
+	srv *vikunjatest.Server
+	dir string
+}
+
+func newEnv(t *testing.T) *env {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("runs the kunja binary")
+	}
+	e := &env{t: t, srv: vikunjatest.NewServer(memory.Demo()), dir: t.TempDir()}
+	t.Cleanup(e.srv.Close)
+	path := filepath.Join(e.dir, "kunja", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	config := fmt.Sprintf("baseurl: %q\ntoken: %q\nusername: demo\npassword: demo\n", e.srv.BaseURL(), e.srv.Token())
+	if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+// kunja runs the binary with args and returns its combined output.
+func (e *env) kunja(args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, kunjaBin, args...)
+	cmd.Env = []string{"HOME=" + e.dir, "XDG_CONFIG_HOME=" + e.dir, "PATH=" + os.Getenv("PATH"), "TERM=dumb"}
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+// mustKunja runs the binary and fails the test if it exits with an error.
+func (e *env) mustKunja(args ...string) string {
+	e.t.Helper()
+	out, err := e.kunja(args...)
+	if err != nil {
+		e.t.Fatalf("kunja %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return out
+}
+
+// requests returns the requests the server received for method and path.
+func (e *env) requests(method, path string) []vikunjatest.Request {
+	var matched []vikunjatest.Request
+	for _, r := range e.srv.Requests() {
+		if r.Method == method && r.Path == path {
+			matched = append(matched, r)
+		}
+	}
+	return matched
+}
+
+// trace renders the requests the server received, for failure messages.
+func (e *env) trace() string {
+	var lines []string
+	for _, r := range e.srv.Requests() {
+		lines = append(lines, fmt.Sprintf("%s %s?%s -> %d", r.Method, r.Path, r.Query.Encode(), r.Status))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (e *env) contains(out, want string) {
+	e.t.Helper()
+	if !strings.Contains(out, want) {
+		e.t.Errorf("output does not contain %q:\n%s", want, out)
+	}
+}
+
+// project returns the ID of the demo project with the given title.
+func (e *env) project(title string) int {
+	e.t.Helper()
+	projects, err := e.srv.Backend.GetAllProjects(context.Background())
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	for _, p := range projects {
+		if p.Title == title {
+			return p.ID
+		}
+	}
+	e.t.Fatalf("no project %q", title)
+	return 0
+}
+
+// user returns the ID of the demo user with the given username.
+func (e *env) user(username string) int {
+	e.t.Helper()
+	users, err := e.srv.Backend.GetAllUsers(context.Background())
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	for _, u := range users {
+		if u.Username == username {
+			return u.ID
+		}
+	}
+	e.t.Fatalf("no user %q", username)
+	return 0
+}
+
+// TestListTotal checks that list shows the X-Total count of the server
+// next to the number of tasks shown.
+func TestListTotal(t *testing.T) {
+	e := newEnv(t)
+	out := e.mustKunja("list", "--limit", "3")
+	reqs := e.requests("GET", "/tasks/all")
+	if len(reqs) == 0 {
+		t.Fatalf("list sent no /tasks/all request:\n%s", e.trace())
+	}
+	total := reqs[len(reqs)-1].Header.Get("X-Total")
+	if total == "" || total == "0" {
+		t.Errorf("unexpected X-Total %q", total)
+	}
+	if want := fmt.Sprintf("3/%s\n", total); !strings.HasPrefix(out, want) {
+		t.Errorf("output does not start with %q:\n%s", want, out)
+	}
+}
+
+// TestListPagination checks that list walks the 50-task pages until a
+// short page, and that the total is reported over all of them.
+func TestListPagination(t *testing.T) {
+	e := newEnv(t)
+	ctx := context.Background()
+	inbox := e.project("Inbox")
+	for i := 1; i <= 120; i++ {
+		if _, err := e.srv.Backend.CreateTask(ctx, inbox, api.Task{Title: fmt.Sprintf("Bulk task %d", i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, total, err := e.srv.Backend.QueryTasks(ctx, api.GetAllTasksParams{Filter: "done = false"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := e.mustKunja("list", "--limit", "1000")
+	var pages []string
+	for _, r := range e.requests("GET", "/tasks/all") {
+		if r.Query.Get("per_page") != "50" {
+			t.Errorf("request for page %s asked for %q tasks per page", r.Query.Get("page"), r.Query.Get("per_page"))
+		}
+		pages = append(pages, r.Query.Get("page"))
+	}
+	wantPages := (total + 49) / 50
+	if total%50 == 0 {
+		wantPages++ // a full last page needs an empty one to stop
+	}
+	if len(pages) != wantPages || pages[0] != "1" || pages[len(pages)-1] != fmt.Sprint(wantPages) {
+		t.Errorf("fetched pages %v of %d undone tasks, want 1..%d", pages, total, wantPages)
+	}
+	e.contains(out, fmt.Sprintf("%d/%d\n", total, total))
+	e.contains(out, "Bulk task 120")
+
+	// --page asks for at most the 50 tasks Vikunja returns per page.
+	e.srv.ResetRequests()
+	out = e.mustKunja("list", "--page", "2")
+	if reqs := e.requests("GET", "/tasks/all"); len(reqs) != 1 || reqs[0].Query.Get("per_page") != "50" || reqs[0].Query.Get("page") != "2" {
+		t.Errorf("list --page 2 sent:\n%s", e.trace())
+	}
+	e.contains(out, fmt.Sprintf("50/%d\n", total))
+}
+
+// TestListLocalFilter checks that tasks filtered locally are counted after
+// filtering, not out of the server's total.
+func TestListLocalFilter(t *testing.T) {
+	e := newEnv(t)
+	tasks, _, err := e.srv.Backend.QueryTasks(context.Background(), api.GetAllTasksParams{Filter: "done = false"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	favorites := 0
+	for _, task := range tasks {
+		if task.IsFavorite {
+			favorites++
+		}
+	}
+	out := e.mustKunja("list", "--favorite")
+	e.contains(out, fmt.Sprintf("%d/%d\n", favorites, favorites))
+}
+
+// TestTaskLifecycle creates a task with an assignee, lists its assignees,
+// completes and deletes it.
+func TestTaskLifecycle(t *testing.T) {
+	e := newEnv(t)
+	ctx := context.Background()
+	home := e.project("Home")
+	e.contains(e.mustKunja("new", "--project", fmt.Sprint(home), "--no-magic", "Fix the fence"), "Task created successfully")
+	tasks, _, err := e.srv.Backend.QueryTasks(ctx, api.GetAllTasksParams{S: "Fix the fence"})
+	if err != nil || len(tasks) != 1 {
+		t.Fatalf("the task was not created (%v):\n%s", err, e.trace())
+	}
+	task := tasks[0]
+	if task.ProjectID != home {
+		t.Errorf("the task was created in project %d, want %d", task.ProjectID, home)
+	}
+	id := fmt.Sprint(task.ID)
+
+	if _, err := e.srv.Backend.AssignUserToTask(ctx, task.ID, e.user("alice")); err != nil {
+		t.Fatal(err)
+	}
+	e.contains(e.mustKunja("assigned", id), "alice")
+	if len(e.requests("GET", "/tasks/"+id+"/assignees")) != 1 {
+		t.Errorf("assigned did not ask for the assignees:\n%s", e.trace())
+	}
+
+	e.mustKunja("done", id)
+	if done, err := e.srv.Backend.GetTask(ctx, task.ID); err != nil || !done.Done {
+		t.Errorf("the task is not done (%v):\n%s", err, e.trace())
+	}
+	e.contains(e.mustKunja("delete", id), "Task deleted successfully")
+	if _, err := e.srv.Backend.GetTask(ctx, task.ID); err == nil {
+		t.Errorf("the task still exists:\n%s", e.trace())
+	}
+}
+
+// TestProjects creates and deletes a project.
+func TestProjects(t *testing.T) {
+	e := newEnv(t)
+	e.mustKunja("project-new", "Garden")
+	id := e.project("Garden")
+	e.contains(e.mustKunja("projects"), "Garden")
+	e.mustKunja("project-del", fmt.Sprint(id))
+	if _, err := e.srv.Backend.GetProject(context.Background(), id); err == nil {
+		t.Errorf("the project still exists:\n%s", e.trace())
+	}
+}
+
+// TestUsers lists all users and the users a project is shared with.
+func TestUsers(t *testing.T) {
+	e := newEnv(t)
+	out := e.mustKunja("users")
+	for _, name := range []string{"demo", "alice", "bob"} {
+		e.contains(out, name)
+	}
+	out = e.mustKunja("project-users", fmt.Sprint(e.project("Work")))
+	e.contains(out, "alice")
+	if strings.Contains(out, "bob") {
+		t.Errorf("bob is listed for a project not shared with him:\n%s", out)
+	}
+}
+
+// TestErrors checks that errors of the server reach the user.
+func TestErrors(t *testing.T) {
+	e := newEnv(t)
+	out, err := e.kunja("show", "999")
+	if err == nil {
+		t.Errorf("show succeeded for a missing task:\n%s", out)
+	}
+	e.contains(out, "status code: 404, message: The task does not exist.")
+
+	e.srv.ExpireTokens()
+	e.srv.Inject(vikunjatest.Fault{Method: "POST", Path: "/login", Status: 412, Message: "Wrong username or password."})
+	out, err = e.kunja("users")
+	if err == nil {
+		t.Errorf("users succeeded although the login failed:\n%s", out)
+	}
+	e.contains(out, "status code: 401")
+}
@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"kunja/api"
+	"kunja/internal/render"
+
+	"github.com/spf13/cobra"
+)
+
+var labelCmd = &cobra.Command{
+	Use:   "label",
+	Short: "Manage labels and attach them to tasks",
+	Long:  `Create, edit and delete labels, and add or remove them on tasks. Labels can be referenced by ID or by (case-insensitive) name.`,
+}
+
+var labelListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all labels",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		svc := getServices(cmd)
+		opts, err := outputOptions()
+		if err != nil {
+			return err
+		}
+		out, err := buildLabelList(cmd.Context(), svc, opts)
+		if err != nil {
+			fmt.Println("Error retrieving labels:", err)
+			return err
+		}
+		fmt.Print(out)
+		return nil
+	},
+}
+
+var labelNewCmd = &cobra.Command{
+	Use:   "new [NAME]",
+	Short: "Create a new label (arg NAME)",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		desc, _ := cmd.Flags().GetString("description")
+		color, _ := cmd.Flags().GetString("color")
+		svc := getServices(cmd)
+		l, err := svc.Label.CreateLabel(cmd.Context(), api.Label{
+			Title:       strings.Join(args, " "),
+			Description: desc,
+			HexColor:    strings.TrimPrefix(color, "#"),
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Label created: %d – %s\n", l.ID, l.Title)
+		return nil
+	},
+}
+
+var labelEditCmd = &cobra.Command{
+	Use:               "edit [LABEL]",
+	Short:             "Edit a label's title, description or colour",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeArgs(completeLabels, false),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		title, _ := cmd.Flags().GetString("title")
+		desc, _ := cmd.Flags().GetString("description")
+		color, _ := cmd.Flags().GetString("color")
+		if title == "" && desc == "" && color == "" {
+			return fmt.Errorf("at least one of --title/--description/--color is required")
+		}
+
+		svc := getServices(cmd)
+		l, err := resolveLabel(cmd.Context(), svc, args[0])
+		if err != nil {
+			return err
+		}
+		if title != "" {
+			l.Title = title
+		}
+		if desc != "" {
+			l.Description = desc
+		}
+		if color != "" {
+			l.HexColor = strings.TrimPrefix(color, "#")
+		}
+		if _, err := svc.Label.UpdateLabel(cmd.Context(), l.ID, l); err != nil {
+			return err
+		}
+		fmt.Println("Label updated successfully")
+		return nil
+	},
+}
+
+var labelDeleteCmd = &cobra.Command{
+	Use:               "delete [LABEL]",
+	Short:             "Delete a label",
+	Annotations:       map[string]string{"skip_mcp": "true"},
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeArgs(completeLabels, false),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		svc := getServices(cmd)
+		l, err := resolveLabel(cmd.Context(), svc, args[0])
+		if err != nil {
+			return err
+		}
+		if _, err := svc.Label.DeleteLabel(cmd.Context(), l.ID); err != nil {
+			return err
+		}
+		fmt.Println("Label deleted.")
+		return nil
+	},
+}
+
+var labelAddCmd = &cobra.Command{
+	Use:   "add [TASK_ID] [LABEL...]",
+	Short: "Add one or more labels to a task",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		taskID, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid task ID: %q", args[0])
+		}
+		out, err := changeTaskLabels(cmd.Context(), getServices(cmd), taskID, args[1:], true)
+		fmt.Print(out)
+		return err
+	},
+}
+
+var labelRemoveCmd = &cobra.Command{
+	Use:   "remove [TASK_ID] [LABEL...]",
+	Short: "Remove one or more labels from a task",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		taskID, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid task ID: %q", args[0])
+		}
+		out, err := changeTaskLabels(cmd.Context(), getServices(cmd), taskID, args[1:], false)
+		fmt.Print(out)
+		return err
+	},
+}
+
+func init() {
+	labelNewCmd.Flags().String("description", "", "Description for the label")
+	labelNewCmd.Flags().String("color", "", "Hex colour for the label (e.g. e8e8e8)")
+	labelEditCmd.Flags().StringP("title", "t", "", "New title for the label")
+	labelEditCmd.Flags().String("description", "", "New description for the label")
+	labelEditCmd.Flags().String("color", "", "New hex colour for the label")
+
+	labelCmd.AddCommand(labelListCmd)
+	labelCmd.AddCommand(labelNewCmd)
+	labelCmd.AddCommand(labelEditCmd)
+	labelCmd.AddCommand(labelDeleteCmd)
+	labelCmd.AddCommand(labelAddCmd)
+	labelCmd.AddCommand(labelRemoveCmd)
+	rootCmd.AddCommand(labelCmd)
+}
+
+// buildLabelList returns all labels rendered in the format selected by out.
+func buildLabelList(ctx context.Context, svc Services, out render.Options) (string, error) {
+	labels, err := svc.Label.GetAllLabels(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	table := render.Table{Header: []string{"ID", "Title", "Color"}}
+	for _, l := range labels {
+		table.AddRow(l.ID, l.Title, l.HexColor)
+	}
+	return out.String(labels, table)
+}
+
+// resolveLabel looks up a label by numeric ID or, failing that, by its
+// title (case-insensitive).
+func resolveLabel(ctx context.Context, svc Services, ref string) (api.Label, error) {
+	ref = strings.TrimSpace(ref)
+	if id, err := strconv.Atoi(ref); err == nil {
+		return svc.Label.GetLabel(ctx, id)
+	}
+	labels, err := svc.Label.GetAllLabels(ctx)
+	if err != nil {
+		return api.Label{}, err
+	}
+	for _, l := range labels {
+		if strings.EqualFold(l.Title, ref) {
+			return l, nil
+		}
+	}
+	return api.Label{}, fmt.Errorf("label not found: %q", ref)
+}
+
+// changeTaskLabels adds (add=true) or removes the referenced labels on a task
+// and reports which ones succeeded.
+func changeTaskLabels(ctx context.Context, svc Services, taskID int, refs []string, add bool) (string, error) {
+	var changed []string
+	var failed []string
+	for _, ref := range refs {
+		l, err := resolveLabel(ctx, svc, ref)
+		if err == nil {
+			if add {
+				_, err = svc.Label.AddLabelToTask(ctx, taskID, l.ID)
+			} else {
+				_, err = svc.Label.RemoveLabelFromTask(ctx, taskID, l.ID)
+			}
+		}
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s (%v)", ref, err))
+		} else {
+			changed = append(changed, l.Title)
+		}
+	}
+
+	var b strings.Builder
+	verb := "Added"
+	if !add {
+		verb = "Removed"
+	}
+	if len(changed) > 0 {
+		fmt.Fprintf(&b, "%s: %s\n", verb, strings.Join(changed, ", "))
+	}
+	if len(failed) > 0 {
+		fmt.Fprintf(&b, "Failed:  %s\n", strings.Join(failed, ", "))
+		return b.String(), fmt.Errorf("%d label(s) could not be changed", len(failed))
+	}
+	return b.String(), nil
+}
@@ -9,9 +9,26 @@ package cmd
 import "github.com/spf13/cobra"
 
 var listCmd = &cobra.Command{
-	Use:   "list",
+	Use:   "list [FILTER]",
 	Short: "List open or all tasks sorted by urgency",
-	Long:  `List tasks from the API.  Shows open tasks by default; add --all to show completed ones as well. Output is sorted by urgency (desc).`,
+	Long: `List tasks from the API.  Shows open tasks by default; add --all to show completed ones as well. Output is sorted by urgency (desc) unless --sort names another field.
+
+Filters combine with AND, e.g.
+
+  kunja list --project Work --label backend --priority 3 --due-before "next friday"
+  kunja list --overdue --assignee alice --sort due
+
+FILTER is an expression over the task fields done, priority, percent_done,
+due, start, end, done_at, created, updated, label, project, assignee, id,
+urgency, title, description, favorite and blocked, combined with &&, || and !
+and grouped with parentheses:
+
+  kunja list 'priority >= 3 && (label in backend, api) && due < now+7d'
+  kunja list 'title like release || (favorite = true && !(blocked = true))'
+
+Whatever Vikunja can evaluate is sent to the server; the rest (e.g. title,
+urgency or blocked) is applied to the returned tasks.`,
+	Args: cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Delegate to the same RunE function that the root command uses.
 		return rootCmd.RunE(cmd, args)
@@ -19,5 +36,7 @@ var listCmd = &cobra.Command{
 }
 
 func init() {
+	addTaskFilterFlags(listCmd.Flags())
+	registerTaskFilterCompletions(listCmd)
 	rootCmd.AddCommand(listCmd)
 }
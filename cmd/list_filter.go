@@ -0,0 +1,355 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"kunja/api"
+	"kunja/internal/core"
+	"kunja/internal/core/dateparse"
+	"kunja/internal/core/filter"
+
+	"github.com/spf13/pflag"
+)
+
+// defaultListLimit is how many tasks list fetches when --limit is not given.
+const defaultListLimit = 100
+
+// taskFilter collects the filters of the list command and the MCP list tool.
+// Everything except Favorite and HideBlocked is translated into Vikunja's
+// filter query; those two, and the parts of Expr the server cannot evaluate,
+// are applied to the fetched tasks.
+type taskFilter struct {
+	Expr        string // filter expression, see package filter
+	ShowAll     bool
+	HideBlocked bool
+	Project     string   // project ID, title or identifier
+	Labels      []string // label IDs or titles; a task must have one of them
+	Assignees   []string // usernames; a task must be assigned to one of them
+	Priority    string   // "3" (at least 3) or with an operator: "=5", "<2"
+	DueBefore   string
+	DueAfter    string
+	Overdue     bool
+	Search      string
+	Favorite    bool
+	Sort        string // urgency (default, highest first) or an API field, "-" prefix for descending
+	Limit       int
+	Page        int
+	SavedFilter string   // Vikunja saved filter (ID or title) whose query is added
+	Columns     []string // table columns, see taskColumns
+}
+
+// taskSortFields maps the accepted --sort names to Vikunja's sort_by fields.
+var taskSortFields = map[string]string{
+	"id":       "id",
+	"title":    "title",
+	"priority": "priority",
+	"due":      "due_date",
+	"due_date": "due_date",
+	"start":    "start_date",
+	"end":      "end_date",
+	"done_at":  "done_at",
+	"created":  "created",
+	"updated":  "updated",
+	"percent":  "percent_done",
+	"position": "position",
+}
+
+// addTaskFilterFlags registers the list filter flags on fs.
+func addTaskFilterFlags(fs *pflag.FlagSet) {
+	fs.Bool("hide-blocked", false, "hide tasks that are blocked by open tasks")
+	fs.String("project", "", "only tasks in this project (ID, title or identifier)")
+	fs.StringSlice("label", nil, "only tasks with one of these labels (ID or title, repeatable)")
+	fs.StringSlice("assignee", nil, "only tasks assigned to one of these users (repeatable)")
+	fs.String("priority", "", "minimum priority, or a comparison such as =5 or <3")
+	fs.String("due-before", "", "only tasks due before this date (e.g. 2025-01-31, friday, in 2 weeks)")
+	fs.String("due-after", "", "only tasks due after this date")
+	fs.Bool("overdue", false, "only open tasks whose due date has passed")
+	fs.String("search", "", "only tasks whose title or description contains this text")
+	fs.Bool("favorite", false, "only tasks marked as favorite")
+	fs.String("sort", "", "sort by urgency (default), id, title, priority, due, start, end, done_at, created, updated, percent or position; prefix - for descending")
+	fs.Int("limit", defaultListLimit, "maximum number of tasks to show")
+	fs.Int("page", 0, "show only this page of --limit tasks, at most 50 per page (1-based)")
+	fs.StringSlice("columns", nil, "table columns to show: "+strings.Join(taskColumnNames, ", "))
+}
+
+// taskFilterFromFlags reads the flags registered by addTaskFilterFlags.
+func taskFilterFromFlags(fs *pflag.FlagSet) taskFilter {
+	f := taskFilter{ShowAll: ShowAll}
+	f.HideBlocked, _ = fs.GetBool("hide-blocked")
+	f.Project, _ = fs.GetString("project")
+	f.Labels, _ = fs.GetStringSlice("label")
+	f.Assignees, _ = fs.GetStringSlice("assignee")
+	f.Priority, _ = fs.GetString("priority")
+	f.DueBefore, _ = fs.GetString("due-before")
+	f.DueAfter, _ = fs.GetString("due-after")
+	f.Overdue, _ = fs.GetBool("overdue")
+	f.Search, _ = fs.GetString("search")
+	f.Favorite, _ = fs.GetBool("favorite")
+	f.Sort, _ = fs.GetString("sort")
+	f.Limit, _ = fs.GetInt("limit")
+	f.Page, _ = fs.GetInt("page")
+	f.Columns, _ = fs.GetStringSlice("columns")
+	return f
+}
+
+// taskFilterFromArgs reads the arguments of the MCP list tool.
+func taskFilterFromArgs(argMap map[string]interface{}) taskFilter {
+	var f taskFilter
+	f.Expr = pickArg(argMap, "filter")
+	f.ShowAll, _ = argMap["all"].(bool)
+	f.HideBlocked, _ = argMap["hide_blocked"].(bool)
+	f.Project = pickArg(argMap, "project")
+	f.Labels = splitList(pickArg(argMap, "label", "labels"))
+	f.Assignees = splitList(pickArg(argMap, "assignee", "assignees"))
+	f.Priority = pickArg(argMap, "priority")
+	f.DueBefore = pickArg(argMap, "due_before")
+	f.DueAfter = pickArg(argMap, "due_after")
+	f.Overdue, _ = argMap["overdue"].(bool)
+	f.Search = pickArg(argMap, "search")
+	f.Favorite, _ = argMap["favorite"].(bool)
+	f.Sort = pickArg(argMap, "sort")
+	f.Limit = defaultListLimit
+	if n, ok := argMap["limit"].(float64); ok && n > 0 {
+		f.Limit = int(n)
+	}
+	if n, ok := argMap["page"].(float64); ok && n > 0 {
+		f.Page = int(n)
+	}
+	f.Columns = splitList(pickArg(argMap, "columns"))
+	return f
+}
+
+// splitList splits a comma-separated argument, dropping empty items.
+func splitList(s string) []string {
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// params translates the filter into GetAllTasks parameters. Projects and
+// labels given by name are resolved to IDs, which is what the filter query
+// expects. rest is the part of the filter expression that has to be
+// evaluated locally, or nil.
+func (f taskFilter) params(ctx context.Context, svc Services) (params api.GetAllTasksParams, rest filter.Expr, err error) {
+	var conds []string
+	var expr filter.Expr
+	if f.Expr != "" {
+		if expr, err = filter.Parse(f.Expr); err != nil {
+			return params, nil, filterError(err)
+		}
+	}
+	var saved api.SavedFilter
+	if f.SavedFilter != "" {
+		if saved, err = resolveSavedFilter(ctx, svc, f.SavedFilter); err != nil {
+			return params, nil, err
+		}
+	}
+	// An expression or saved filter about done replaces the implicit "open
+	// tasks only".
+	mentionsDone := (expr != nil && filter.Mentions(expr, "done")) || doneCondition.MatchString(saved.Filters.Filter)
+	if (!f.ShowAll || f.Overdue) && !mentionsDone {
+		conds = append(conds, "done = false")
+	}
+	if q := strings.TrimSpace(saved.Filters.Filter); q != "" {
+		conds = append(conds, "("+q+")")
+	}
+	if f.Project != "" {
+		p, err := resolveProject(ctx, svc, f.Project)
+		if err != nil {
+			return params, nil, err
+		}
+		conds = append(conds, fmt.Sprintf("project = %d", p.ID))
+	}
+	if len(f.Labels) > 0 {
+		ids := make([]string, len(f.Labels))
+		for i, ref := range f.Labels {
+			l, err := resolveLabel(ctx, svc, ref)
+			if err != nil {
+				return params, nil, err
+			}
+			ids[i] = strconv.Itoa(l.ID)
+		}
+		conds = append(conds, "labels in "+strings.Join(ids, ", "))
+	}
+	if len(f.Assignees) > 0 {
+		conds = append(conds, "assignees in "+strings.Join(f.Assignees, ", "))
+	}
+	if f.Priority != "" {
+		cond, err := priorityCondition(f.Priority)
+		if err != nil {
+			return params, nil, err
+		}
+		conds = append(conds, cond)
+	}
+	for _, bound := range []struct{ value, op string }{{f.DueBefore, "<"}, {f.DueAfter, ">"}} {
+		if bound.value == "" {
+			continue
+		}
+		t, err := dateparse.Parse(bound.value)
+		if err != nil {
+			return params, nil, err
+		}
+		conds = append(conds, fmt.Sprintf("due_date %s %s", bound.op, t.UTC().Format(time.RFC3339)))
+	}
+	if f.Overdue {
+		conds = append(conds, "due_date < now")
+	}
+	if expr != nil {
+		query, local, err := filter.Compile(expr, filterEnv(ctx, svc))
+		if err != nil {
+			return params, nil, filterError(err)
+		}
+		if query != "" {
+			conds = append(conds, "("+query+")")
+		}
+		rest = local
+	}
+
+	search := f.Search
+	if search == "" {
+		search = saved.Filters.S
+	}
+	params = api.GetAllTasksParams{
+		S:      search,
+		Filter: strings.Join(conds, " && "),
+	}
+	if field, desc, ok := f.apiSort(); ok {
+		params.SortBy = field
+		params.OrderBy = "asc"
+		if desc {
+			params.OrderBy = "desc"
+		}
+	} else if name := strings.ToLower(strings.TrimSpace(f.Sort)); name != "" && name != "urgency" {
+		return api.GetAllTasksParams{}, nil, fmt.Errorf("unknown sort field %q", f.Sort)
+	}
+	return params, rest, nil
+}
+
+// doneCondition matches a test of the done field in a Vikunja filter query.
+var doneCondition = regexp.MustCompile(`\bdone\s*(=|!=)`)
+
+// filterEnv resolves project and label names of filter expressions through
+// the services.
+func filterEnv(ctx context.Context, svc Services) filter.Env {
+	return filter.Env{
+		ProjectID: func(ref string) (int, error) {
+			p, err := resolveProject(ctx, svc, ref)
+			return p.ID, err
+		},
+		LabelID: func(ref string) (int, error) {
+			l, err := resolveLabel(ctx, svc, ref)
+			return l.ID, err
+		},
+	}
+}
+
+// filterError appends the source line with a caret to filter syntax errors.
+func filterError(err error) error {
+	var fe *filter.Error
+	if errors.As(err, &fe) {
+		return fmt.Errorf("%w\n%s", err, fe.Pointer())
+	}
+	return err
+}
+
+// apiSort reports the Vikunja sort field when the tasks are sorted by the
+// API rather than locally by urgency.
+func (f taskFilter) apiSort() (field string, desc bool, ok bool) {
+	name := strings.ToLower(strings.TrimSpace(f.Sort))
+	desc = strings.HasPrefix(name, "-")
+	field, ok = taskSortFields[strings.TrimPrefix(name, "-")]
+	return field, desc, ok
+}
+
+// priorityCondition turns "3" into "priority >= 3" and "<3" into
+// "priority < 3".
+func priorityCondition(input string) (string, error) {
+	s := strings.ReplaceAll(input, " ", "")
+	op := ">="
+	for _, candidate := range []string{">=", "<=", "!=", "=", ">", "<"} {
+		if strings.HasPrefix(s, candidate) {
+			op, s = candidate, strings.TrimPrefix(s, candidate)
+			break
+		}
+	}
+	p, err := strconv.Atoi(s)
+	if err != nil || p < 0 || p > core.MaxPriority {
+		return "", fmt.Errorf("invalid priority %q (use 0-5, optionally with =, !=, <, <=, >, >=)", input)
+	}
+	return fmt.Sprintf("priority %s %d", op, p), nil
+}
+
+// fetchFilteredTasks fetches the tasks selected by f, one page of f.Limit
+// tasks (at most maxPerPage) when f.Page is set, otherwise up to f.Limit tasks across pages, and
+// returns them with the number of matching tasks. When part of f is checked
+// locally (--favorite, --hide-blocked or an expression the API cannot
+// express) all matching tasks are fetched, filtered, and then paged and
+// counted here.
+func fetchFilteredTasks(ctx context.Context, svc Services, f taskFilter) ([]api.Task, int, error) {
+	params, rest, err := f.params(ctx, svc)
+	if err != nil {
+		return nil, 0, err
+	}
+	limit := f.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+
+	if rest == nil && !f.Favorite && !f.HideBlocked {
+		listCtx, total := api.WithTotal(ctx)
+		var tasks []api.Task
+		if f.Page > 0 {
+			params.Page = f.Page
+			params.PerPage = min(limit, maxPerPage)
+			tasks, err = svc.Task.GetAllTasks(listCtx, params)
+		} else {
+			tasks, err = fetchTasks(listCtx, svc, params, limit)
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		n, ok := total()
+		if !ok { // the server did not send X-Total
+			n = len(tasks)
+		}
+		return tasks, n, nil
+	}
+
+	tasks, err := fetchTasks(ctx, svc, params, 0)
+	if err != nil {
+		return nil, 0, err
+	}
+	if rest != nil {
+		if tasks, err = filter.Filter(rest, tasks, filterEnv(ctx, svc)); err != nil {
+			return nil, 0, err
+		}
+	}
+	if f.Favorite || f.HideBlocked {
+		kept := tasks[:0]
+		for _, t := range tasks {
+			if (f.Favorite && !t.IsFavorite) || (f.HideBlocked && t.IsBlocked()) {
+				continue
+			}
+			kept = append(kept, t)
+		}
+		tasks = kept
+	}
+	total := len(tasks)
+	if f.Page > 0 {
+		return filter.Page(tasks, f.Page, min(limit, maxPerPage)), total, nil
+	}
+	if len(tasks) > limit {
+		tasks = tasks[:limit]
+	}
+	return tasks, total, nil
+}
@@ -7,9 +7,9 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"math"
 	"os"
 	"path/filepath"
-	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -22,58 +22,100 @@ import (
 
 	"kunja/adapter/vikunja"
 	"kunja/api"
+	"kunja/internal/core/dateparse"
 )
 
 /*
 prepareServices and listHandler provide a lightweight, MCP-native
 implementation of the “list” tool.  They bypass Cobra completely and call
-the shared business logic in buildTaskList(), so they do not rely on stdout
+the shared business logic of buildTaskList(), so they do not rely on stdout
 redirection and cannot dead-lock.
 */
 func prepareServices(ctx context.Context) (context.Context, Services, error) {
+	if memory, err := useMemoryBackend(); err != nil {
+		return ctx, Services{}, err
+	} else if memory {
+		svc := memoryServices()
+		return context.WithValue(ctx, servicesKey, svc), svc, nil
+	}
+	// Sessions of the HTTP transport may act as an account of their own.
+	if svc, ok, err := sessionServices(ctx); err != nil {
+		return ctx, Services{}, err
+	} else if ok {
+		return context.WithValue(ctx, servicesKey, svc), svc, nil
+	}
 	token := viper.GetString("token")
 	base := viper.GetString("baseurl")
 	if token == "" || base == "" {
+		if mcpSessionRegistry != nil {
+			return ctx, Services{}, fmt.Errorf("no Vikunja account for this session – call the login tool or send the X-Vikunja-Token header")
+		}
 		return ctx, Services{}, fmt.Errorf("missing token or baseurl – run `kunja login` first")
 	}
 
 	client := api.NewApiClient(base, token)
 	client.SetCredentials(viper.GetString("username"), viper.GetString("password"))
+	model, err := urgencyModel()
+	if err != nil {
+		return ctx, Services{}, err
+	}
+	client.Urgency = &model
 	adapter := vikunja.New(client)
 
 	svc := Services{
-		Auth:    adapter,
-		Task:    adapter,
-		Project: adapter,
-		User:    adapter,
+		Auth:       adapter,
+		Task:       adapter,
+		Project:    adapter,
+		User:       adapter,
+		Label:      adapter,
+		Bucket:     adapter,
+		Comment:    adapter,
+		Attachment: adapter,
+		Relation:   adapter,
+		Filter:     adapter,
 	}
+	svc = withOfflineCache(withServiceCache(svc))
 	ctx = context.WithValue(ctx, servicesKey, svc)
 	return ctx, svc, nil
 }
 
 func listHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	argMap, _ := req.Params.Arguments.(map[string]interface{})
-	showAll, _ := argMap["all"].(bool)
-	verbose, _ := argMap["verbose"].(bool)
+	opts, err := mcpOutputOptions(argMap)
+	if err != nil {
+		return nil, err
+	}
 
 	ctx, svc, err := prepareServices(ctx)
 	if err != nil {
 		return nil, err
 	}
-	out, err := buildTaskList(ctx, svc, verbose, showAll)
+	f := taskFilterFromArgs(argMap)
+	tasks, total, err := queryTaskList(ctx, svc, f)
 	if err != nil {
 		return nil, err
 	}
-	return mcp.NewToolResultText(out), nil
+	out, err := renderTaskList(tasks, total, opts, f.Columns)
+	if err != nil {
+		return nil, err
+	}
+	return taskListToolResult(tasks, total, out), nil
 }
 
 var mcpLog string
 
+// tsArgDescription documents the timestamp formats accepted by the time tools.
+const tsArgDescription = "RFC3339, YYYY-MM-DD, 'now', 'now+7d', unix seconds/ms, or phrases like 'tomorrow 9am', 'next friday 17:00 Europe/Berlin', 'end of month'"
+
 // buildMCPServer creates an MCP server and registers every eligible Cobra
 // command exactly once.  The same builder is reused by the help output and the
 // runtime server, so tool metadata is generated in a single place.
-func buildMCPServer() *server.MCPServer {
-	s := server.NewMCPServer(AppName, Version)
+func buildMCPServer(opts ...server.ServerOption) *server.MCPServer {
+	opts = append([]server.ServerOption{
+		server.WithResourceCapabilities(true, false),
+		server.WithPromptCapabilities(false),
+	}, opts...)
+	s := server.NewMCPServer(AppName, Version, opts...)
 
 	// Register simple diagnostic tools that are not backed by Cobra.
 	registerBuiltinTools(s)
@@ -83,9 +125,26 @@ func buildMCPServer() *server.MCPServer {
 	// ------------------------------------------------------------------
 	listTool := mcp.NewTool(
 		"list",
-		mcp.WithDescription("List tasks sorted by urgency. By default only open tasks are returned; set all=true if you also want to see completed (done) tasks."),
-		mcp.WithBoolean("all", mcp.Description("include done tasks – use only if you also want to see completed tasks")),
-		mcp.WithBoolean("verbose", mcp.Description("return raw JSON instead of table")),
+		append([]mcp.ToolOption{
+			mcp.WithDescription("List tasks sorted by urgency. By default only open tasks are returned; set all=true if you also want to see completed (done) tasks. All filters combine with AND."),
+			mcp.WithBoolean("all", mcp.Description("include done tasks – use only if you also want to see completed tasks")),
+			mcp.WithString("filter", mcp.Description("filter expression, e.g. 'priority >= 3 && (label in backend, api) && due < now+7d'; fields: done, priority, percent_done, due, start, end, done_at, created, updated, label, project, assignee, id, urgency, title, description, favorite, blocked; operators: = != > >= < <= like in, not in; combine with && || ! and parentheses")),
+			mcp.WithBoolean("hide_blocked", mcp.Description("hide tasks that are blocked by open tasks")),
+			mcp.WithString("project", mcp.Description("only tasks in this project (ID, title or identifier)")),
+			mcp.WithString("label", mcp.Description("only tasks with one of these labels (comma-separated IDs or titles)")),
+			mcp.WithString("assignee", mcp.Description("only tasks assigned to one of these users (comma-separated usernames)")),
+			mcp.WithString("priority", mcp.Description("minimum priority 0-5, or a comparison such as '=5' or '<3'")),
+			mcp.WithString("due_before", mcp.Description("only tasks due before this date, e.g. YYYY-MM-DD, 'friday', 'in 2 weeks'")),
+			mcp.WithString("due_after", mcp.Description("only tasks due after this date")),
+			mcp.WithBoolean("overdue", mcp.Description("only open tasks whose due date has passed")),
+			mcp.WithString("search", mcp.Description("only tasks whose title or description contains this text")),
+			mcp.WithBoolean("favorite", mcp.Description("only tasks marked as favorite")),
+			mcp.WithString("sort", mcp.Description("urgency (default), id, title, priority, due, start, end, done_at, created, updated, percent or position; prefix - for descending")),
+			mcp.WithNumber("limit", mcp.Description("maximum number of tasks (default 100)")),
+			mcp.WithNumber("page", mcp.Description("return only this page of limit tasks, at most 50 per page (1-based)")),
+			mcp.WithString("columns", mcp.Description("comma-separated table columns: "+strings.Join(taskColumnNames, ", "))),
+			mcp.WithOutputSchema[taskListResult](),
+		}, withOutputArgs()...)...,
 	)
 	s.AddTool(listTool, listHandler)
 	BuiltinTools = append(BuiltinTools, listTool)
@@ -95,30 +154,30 @@ func buildMCPServer() *server.MCPServer {
 	// ------------------------------------------------------------------
 	newTool := mcp.NewTool(
 		"new",
-		mcp.WithDescription("Create a new task in a project."),
-		mcp.WithString("title", mcp.Required(), mcp.Description("task title")),
-		mcp.WithString("due", mcp.Description("due date YYYY-MM-DD")),
-		mcp.WithNumber("project", mcp.Required(), mcp.Description("project ID")),
+		mcp.WithDescription("Create a new task in a project. The title understands quick-add magic: *label, +project, !1-!5 priority, @user assignee and date phrases such as 'tomorrow', 'next friday 17:00', 'in 3 days', 'december 24' or a weekday ending the title."),
+		mcp.WithString("title", mcp.Required(), mcp.Description("task title, optionally with quick-add magic")),
+		mcp.WithString("due", mcp.Description("due date, e.g. YYYY-MM-DD, 'tomorrow 9am', 'next friday', 'in 2 weeks'")),
+		mcp.WithNumber("project", mcp.Description("project ID (optional when the title contains +project)")),
+		mcp.WithBoolean("no_magic", mcp.Description("keep the title verbatim instead of parsing quick-add magic")),
+		mcp.WithOutputSchema[taskResult](),
 	)
 	s.AddTool(newTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		argMap, _ := req.Params.Arguments.(map[string]interface{})
 		title, _ := argMap["title"].(string)
 		due, _ := argMap["due"].(string)
-		projFloat, ok := argMap["project"].(float64)
-		if !ok {
-			return nil, fmt.Errorf("project argument is required")
-		}
+		projFloat, _ := argMap["project"].(float64)
+		noMagic, _ := argMap["no_magic"].(bool)
 		projectID := int(projFloat)
 
 		ctx, svc, err := prepareServices(ctx)
 		if err != nil {
 			return nil, err
 		}
-		out, err := createTaskSimple(ctx, svc, title, due, projectID)
+		task, out, err := createTaskSimple(ctx, svc, title, due, projectID, !noMagic)
 		if err != nil {
 			return nil, err
 		}
-		return mcp.NewToolResultText(out), nil
+		return taskToolResult(task, out), nil
 	})
 	BuiltinTools = append(BuiltinTools, newTool)
 
@@ -127,22 +186,35 @@ func buildMCPServer() *server.MCPServer {
 	// ------------------------------------------------------------------
 	projectsTool := mcp.NewTool(
 		"projects",
-		mcp.WithDescription("List projects; verbose=true returns raw JSON."),
-		mcp.WithBoolean("verbose", mcp.Description("raw JSON output")),
+		append([]mcp.ToolOption{
+			mcp.WithDescription("List projects as a table, or in another format via output."),
+			mcp.WithOutputSchema[projectListResult](),
+		}, withOutputArgs()...)...,
 	)
 	s.AddTool(projectsTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		argMap, _ := req.Params.Arguments.(map[string]interface{})
-		verbose, _ := argMap["verbose"].(bool)
+		opts, err := mcpOutputOptions(argMap)
+		if err != nil {
+			return nil, err
+		}
 
 		ctx, svc, err := prepareServices(ctx)
 		if err != nil {
 			return nil, err
 		}
-		out, err := buildProjectList(ctx, svc, verbose)
+		projects, err := svc.Project.GetAllProjects(ctx)
+		if err != nil {
+			return nil, err
+		}
+		out, err := renderProjectList(projects, opts)
 		if err != nil {
 			return nil, err
 		}
-		return mcp.NewToolResultText(out), nil
+		result := projectListResult{Projects: make([]mcpProject, len(projects))}
+		for i, p := range projects {
+			result.Projects[i] = mcpProjectOf(p)
+		}
+		return structuredResult(result, out), nil
 	})
 	BuiltinTools = append(BuiltinTools, projectsTool)
 
@@ -152,9 +224,10 @@ func buildMCPServer() *server.MCPServer {
 	nowTool := mcp.NewTool(
 		"now",
 		mcp.WithDescription("Return the current date and time in RFC 3339 format. Call this tool any time you need to calculate a relative date or time such as 'tomorrow', 'in three days', etc."),
+		mcp.WithOutputSchema[timeResult](),
 	)
 	s.AddTool(nowTool, func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		return mcp.NewToolResultText(time.Now().Format(time.RFC3339)), nil
+		return timeToolResult(time.Now()), nil
 	})
 	BuiltinTools = append(BuiltinTools, nowTool)
 
@@ -166,12 +239,13 @@ func buildMCPServer() *server.MCPServer {
 	addTool := mcp.NewTool(
 		"time_add",
 		mcp.WithDescription("Add a duration to a timestamp. Defaults to now."),
-		mcp.WithString("ts", mcp.Description("RFC3339, YYYY-MM-DD, 'now', or unix seconds/ms")),
+		mcp.WithString("ts", mcp.Description(tsArgDescription)),
 		mcp.WithNumber("seconds"),
 		mcp.WithNumber("minutes"),
 		mcp.WithNumber("hours"),
 		mcp.WithNumber("days"),
 		mcp.WithString("dur", mcp.Description("Go duration (e.g. 2h30m), ISO-8601 (e.g. P1DT30M) or human (e.g. '2 hours')")),
+		mcp.WithOutputSchema[timeResult](),
 	)
 	s.AddTool(addTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, _ := req.Params.Arguments.(map[string]interface{})
@@ -183,7 +257,7 @@ func buildMCPServer() *server.MCPServer {
 		if err != nil {
 			return nil, err
 		}
-		return mcp.NewToolResultText(base.Add(d).Format(time.RFC3339)), nil
+		return timeToolResult(base.Add(d)), nil
 	})
 	BuiltinTools = append(BuiltinTools, addTool)
 
@@ -191,12 +265,13 @@ func buildMCPServer() *server.MCPServer {
 	subTool := mcp.NewTool(
 		"time_sub",
 		mcp.WithDescription("Subtract a duration from a timestamp. Defaults to now."),
-		mcp.WithString("ts", mcp.Description("RFC3339, YYYY-MM-DD, 'now', or unix seconds/ms")),
+		mcp.WithString("ts", mcp.Description(tsArgDescription)),
 		mcp.WithNumber("seconds"),
 		mcp.WithNumber("minutes"),
 		mcp.WithNumber("hours"),
 		mcp.WithNumber("days"),
 		mcp.WithString("dur", mcp.Description("Go duration (e.g. 2h30m), ISO-8601 (e.g. P1DT30M) or human (e.g. '2 hours')")),
+		mcp.WithOutputSchema[timeResult](),
 	)
 	s.AddTool(subTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, _ := req.Params.Arguments.(map[string]interface{})
@@ -208,7 +283,7 @@ func buildMCPServer() *server.MCPServer {
 		if err != nil {
 			return nil, err
 		}
-		return mcp.NewToolResultText(base.Add(-d).Format(time.RFC3339)), nil
+		return timeToolResult(base.Add(-d)), nil
 	})
 	BuiltinTools = append(BuiltinTools, subTool)
 
@@ -216,9 +291,10 @@ func buildMCPServer() *server.MCPServer {
 	diffTool := mcp.NewTool(
 		"time_diff",
 		mcp.WithDescription("Difference between two timestamps. Returns a number (default seconds)."),
-		mcp.WithString("ts", mcp.Required(), mcp.Description("RFC3339, YYYY-MM-DD, 'now', or unix seconds/ms")),
-		mcp.WithString("ts2", mcp.Required(), mcp.Description("RFC3339, YYYY-MM-DD, or unix epoch")),
+		mcp.WithString("ts", mcp.Required(), mcp.Description(tsArgDescription)),
+		mcp.WithString("ts2", mcp.Required(), mcp.Description(tsArgDescription)),
 		mcp.WithString("unit", mcp.Description("seconds|minutes|hours|days (default: seconds)")),
+		mcp.WithOutputSchema[timeDiffResult](),
 	)
 	s.AddTool(diffTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, _ := req.Params.Arguments.(map[string]interface{})
@@ -230,17 +306,8 @@ func buildMCPServer() *server.MCPServer {
 		if err != nil {
 			return nil, err
 		}
-		u := strings.ToLower(pickArg(args, "unit", "units"))
-		delta := t2.Sub(t1).Seconds()
-		switch u {
-		case "minutes", "minute", "min", "mins", "m":
-			delta /= 60
-		case "hours", "hour", "hr", "hrs", "h":
-			delta /= 3600
-		case "days", "day", "d":
-			delta /= 86400
-		}
-		return mcp.NewToolResultText(fmt.Sprintf("%.0f", delta)), nil
+		value, unit := timeDiff(t1, t2, pickArg(args, "unit", "units"))
+		return structuredResult(timeDiffResult{Value: value, Unit: unit}, fmt.Sprintf("%.0f", value)), nil
 	})
 	BuiltinTools = append(BuiltinTools, diffTool)
 
@@ -248,9 +315,10 @@ func buildMCPServer() *server.MCPServer {
 	convertTool := mcp.NewTool(
 		"time_convert",
 		mcp.WithDescription("Convert a timestamp to another time-zone."),
-		mcp.WithString("ts", mcp.Required(), mcp.Description("RFC3339, YYYY-MM-DD, or unix epoch")),
+		mcp.WithString("ts", mcp.Required(), mcp.Description(tsArgDescription)),
 		mcp.WithString("toTZ", mcp.Required(), mcp.Description("IANA time-zone, e.g. Europe/Berlin")),
 		mcp.WithString("fromTZ", mcp.Description("interpret naive ts in this zone (if needed)")),
+		mcp.WithOutputSchema[timeResult](),
 	)
 	s.AddTool(convertTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, _ := req.Params.Arguments.(map[string]interface{})
@@ -265,7 +333,7 @@ func buildMCPServer() *server.MCPServer {
 		if err != nil {
 			return nil, fmt.Errorf("unknown time-zone: %s", to)
 		}
-		return mcp.NewToolResultText(t.In(loc).Format(time.RFC3339)), nil
+		return timeToolResult(t.In(loc)), nil
 	})
 	BuiltinTools = append(BuiltinTools, convertTool)
 
@@ -283,6 +351,7 @@ func buildMCPServer() *server.MCPServer {
 		mcp.WithNumber("minutes"),
 		mcp.WithNumber("hours"),
 		mcp.WithNumber("days"),
+		mcp.WithOutputSchema[timecalcResult](),
 	)
 	s.AddTool(timecalcTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, _ := req.Params.Arguments.(map[string]interface{})
@@ -297,7 +366,7 @@ func buildMCPServer() *server.MCPServer {
 			if err != nil {
 				return nil, err
 			}
-			return mcp.NewToolResultText(base.Add(d).Format(time.RFC3339)), nil
+			return timecalcToolResult(base.Add(d)), nil
 
 		case "sub", "minus", "-":
 			base, err := parseTS(pickArg(args, "ts"))
@@ -308,7 +377,7 @@ func buildMCPServer() *server.MCPServer {
 			if err != nil {
 				return nil, err
 			}
-			return mcp.NewToolResultText(base.Add(-d).Format(time.RFC3339)), nil
+			return timecalcToolResult(base.Add(-d)), nil
 
 		case "diff", "delta":
 			t1, err := parseTS(pickArg(args, "ts"))
@@ -319,17 +388,8 @@ func buildMCPServer() *server.MCPServer {
 			if err != nil {
 				return nil, err
 			}
-			u := strings.ToLower(pickArg(args, "unit", "units"))
-			delta := t2.Sub(t1).Seconds()
-			switch u {
-			case "minutes", "minute", "min", "mins", "m":
-				delta /= 60
-			case "hours", "hour", "hr", "hrs", "h":
-				delta /= 3600
-			case "days", "day", "d":
-				delta /= 86400
-			}
-			return mcp.NewToolResultText(fmt.Sprintf("%.0f", delta)), nil
+			value, unit := timeDiff(t1, t2, pickArg(args, "unit", "units"))
+			return structuredResult(timecalcResult{Value: &value, Unit: unit}, fmt.Sprintf("%.0f", value)), nil
 
 		case "convert", "tz":
 			tsStr := pickArg(args, "ts")
@@ -343,7 +403,7 @@ func buildMCPServer() *server.MCPServer {
 			if err != nil {
 				return nil, fmt.Errorf("unknown time-zone: %s", to)
 			}
-			return mcp.NewToolResultText(t.In(loc).Format(time.RFC3339)), nil
+			return timecalcToolResult(t.In(loc)), nil
 
 		default:
 			return nil, fmt.Errorf("unknown op: %s (use add/sub/diff/convert)", op)
@@ -358,6 +418,7 @@ func buildMCPServer() *server.MCPServer {
 		"createproject",
 		mcp.WithDescription("Create a new project."),
 		mcp.WithString("title", mcp.Required(), mcp.Description("project title")),
+		mcp.WithOutputSchema[projectResult](),
 	)
 	s.AddTool(createProjectTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		argMap, _ := req.Params.Arguments.(map[string]interface{})
@@ -374,7 +435,8 @@ func buildMCPServer() *server.MCPServer {
 		if err != nil {
 			return nil, err
 		}
-		return mcp.NewToolResultText(fmt.Sprintf("Project created: %d – %s", p.ID, p.Title)), nil
+		message := fmt.Sprintf("Project created: %d – %s", p.ID, p.Title)
+		return structuredResult(projectResult{Project: mcpProjectOf(p), Message: message}, message), nil
 	})
 	BuiltinTools = append(BuiltinTools, createProjectTool)
 
@@ -385,6 +447,7 @@ func buildMCPServer() *server.MCPServer {
 		"done",
 		mcp.WithDescription("Toggle the done status of a task."),
 		mcp.WithNumber("id", mcp.Required(), mcp.Description("task ID")),
+		mcp.WithOutputSchema[taskResult](),
 	)
 	s.AddTool(doneTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		argMap, _ := req.Params.Arguments.(map[string]interface{})
@@ -398,11 +461,11 @@ func buildMCPServer() *server.MCPServer {
 		if err != nil {
 			return nil, err
 		}
-		out, err := toggleTaskDone(ctx, svc, taskID)
+		task, out, err := toggleTaskDone(ctx, svc, taskID)
 		if err != nil {
 			return nil, err
 		}
-		return mcp.NewToolResultText(out), nil
+		return taskToolResult(task, out), nil
 	})
 	BuiltinTools = append(BuiltinTools, doneTool)
 
@@ -413,6 +476,7 @@ func buildMCPServer() *server.MCPServer {
 		"delete",
 		mcp.WithDescription("Delete one or more tasks by ID."),
 		mcp.WithString("ids", mcp.Required(), mcp.Description("comma-separated list of task IDs (e.g. \"12,34,56\")")),
+		mcp.WithOutputSchema[deleteResult](),
 	)
 	s.AddTool(deleteTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		argMap, _ := req.Params.Arguments.(map[string]interface{})
@@ -445,11 +509,14 @@ func buildMCPServer() *server.MCPServer {
 
 		var deleted []string
 		var failed []string
+		result := deleteResult{Deleted: []int{}, Failed: []deleteFailed{}}
 		for _, id := range ids {
 			if _, err := svc.Task.DeleteTask(ctx, id); err != nil {
 				failed = append(failed, fmt.Sprintf("%d (%v)", id, err))
+				result.Failed = append(result.Failed, deleteFailed{ID: id, Error: err.Error()})
 			} else {
 				deleted = append(deleted, strconv.Itoa(id))
+				result.Deleted = append(result.Deleted, id)
 			}
 		}
 
@@ -460,7 +527,7 @@ func buildMCPServer() *server.MCPServer {
 		if len(failed) > 0 {
 			fmt.Fprintf(&b, "Failed:  %s\n", strings.Join(failed, ", "))
 		}
-		return mcp.NewToolResultText(b.String()), nil
+		return structuredResult(result, b.String()), nil
 	})
 	BuiltinTools = append(BuiltinTools, deleteTool)
 
@@ -473,8 +540,9 @@ func buildMCPServer() *server.MCPServer {
 		mcp.WithNumber("id", mcp.Required(), mcp.Description("task ID")),
 		mcp.WithString("title", mcp.Description("new title")),
 		mcp.WithString("description", mcp.Description("new description")),
-		mcp.WithString("due", mcp.Description("new due date YYYY-MM-DD")),
+		mcp.WithString("due", mcp.Description("new due date, e.g. YYYY-MM-DD, 'tomorrow 9am', 'next friday', 'end of month'")),
 		mcp.WithNumber("project", mcp.Description("new project ID")),
+		mcp.WithOutputSchema[taskResult](),
 	)
 	s.AddTool(editTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, _ := req.Params.Arguments.(map[string]interface{})
@@ -496,20 +564,42 @@ func buildMCPServer() *server.MCPServer {
 		if err != nil {
 			return nil, err
 		}
-		out, err := editTaskSimple(ctx, svc, int(idFloat), title, desc, due, int(projFloat))
+		task, out, err := editTaskSimple(ctx, svc, int(idFloat), title, desc, due, int(projFloat))
 		if err != nil {
 			return nil, err
 		}
-		return mcp.NewToolResultText(out), nil
+		return taskToolResult(task, out), nil
 	})
 	BuiltinTools = append(BuiltinTools, editTool)
 
+	// Native MCP comment tools (list / add / edit / delete)
+	registerCommentTools(s)
+
+	// Native MCP report tools (one per configured report)
+	registerReportTools(s)
+
+	// Native MCP agenda and calendar tools
+	registerAgendaTools(s)
+
+	// kunja:// resources of tasks, projects and reports
+	registerResources(s)
+
+	// Planning prompts: daily plan, weekly review, inbox triage, breakdown
+	registerPrompts(s)
+
 	return s
 }
 
 var mcpCmd = &cobra.Command{
-	Use:         "mcp",
-	Short:       "Run Kunja as an MCP server over stdio",
+	Use:   "mcp",
+	Short: "Run Kunja as an MCP server over stdio or HTTP",
+	Long: `Run Kunja as an MCP server.
+
+By default it talks MCP over stdin/stdout to the client that started it.
+With --transport http it serves any number of clients on --listen, over
+streamable HTTP at /mcp and over SSE at /sse; they must authenticate with
+the bearer token mcp.token or a per-user key from mcp.api_keys in
+config.yaml, sent as "Authorization: Bearer <token>" or "X-API-Key".`,
 	Annotations: map[string]string{"skip_mcp": "true"},
 	RunE:        runMCP,
 }
@@ -518,10 +608,12 @@ func init() {
 	rootCmd.AddCommand(mcpCmd)
 	defaultLogPath := filepath.Join(defaultConfigDir(), "kunja-mcp.log")
 	mcpCmd.Flags().StringVarP(&mcpLog, "log", "l", defaultLogPath, "debug log file")
+	mcpCmd.Flags().StringVar(&mcpTransport, "transport", "stdio", "transport to serve MCP over: stdio or http")
+	mcpCmd.Flags().StringVar(&mcpListen, "listen", ":8080", "address to listen on with --transport http")
 
 	// Custom help prints a human-readable catalogue of all MCP tools.
 	mcpCmd.SetHelpFunc(func(cmd *cobra.Command, _ []string) {
-		fmt.Fprintln(cmd.OutOrStdout(), "Run Kunja as an MCP server over stdio.")
+		fmt.Fprintln(cmd.OutOrStdout(), "Run Kunja as an MCP server over stdio, or over HTTP with --transport http --listen :8080.")
 		fmt.Fprintln(cmd.OutOrStdout(), "Available tools:")
 
 		// Ensure the BuiltinTools slice is populated; this happens when the
@@ -542,6 +634,10 @@ func init() {
 
 // runMCP starts an MCP server that exposes all Cobra commands as tools.
 func runMCP(_ *cobra.Command, _ []string) error {
+	if mcpTransport != "stdio" && mcpTransport != "http" {
+		return fmt.Errorf("unknown transport %q (use stdio or http)", mcpTransport)
+	}
+
 	// optional log file
 	if strings.TrimSpace(mcpLog) != "" {
 		if err := os.MkdirAll(filepath.Dir(mcpLog), 0o755); err == nil {
@@ -552,11 +648,40 @@ func runMCP(_ *cobra.Command, _ []string) error {
 		}
 	}
 
+	if mcpTransport == "http" {
+		sessions, err := newMCPSessions()
+		if err != nil {
+			return err
+		}
+		mcpSessionRegistry = sessions
+		conns := newMCPConnections()
+		hooks := &server.Hooks{}
+		hooks.AddOnRegisterSession(conns.register)
+		hooks.AddOnUnregisterSession(conns.unregister)
+		hooks.AddOnUnregisterSession(sessions.unregister)
+		s := buildMCPServer(
+			server.WithHooks(hooks),
+			server.WithToolHandlerMiddleware(conns.middleware),
+			server.WithToolHandlerMiddleware(sessions.middleware),
+		)
+		subs, err := newMCPSubscriptions(s)
+		if err != nil {
+			return err
+		}
+		hooks.AddOnUnregisterSession(subs.unregister)
+		registerSessionTools(s, sessions)
+		return serveMCPHTTP(s, mcpListen, sessions, subs)
+	}
+
 	// Build the MCP server and register all tools
 	s := buildMCPServer()
+	subs, err := newMCPSubscriptions(s)
+	if err != nil {
+		return err
+	}
 
 	// Serve stdin/stdout
-	return server.ServeStdio(s)
+	return serveStdio(s, subs)
 }
 
 // genericHandler converts MCP parameters to CLI flags and executes the Cobra command.
@@ -675,54 +800,19 @@ func numArg(m map[string]interface{}, name string) float64 {
 
 func parseTS(input string) (time.Time, error) { return parseTSWithFrom(input, "") }
 
+// parseTSWithFrom parses a timestamp with the shared date parser; naive
+// input is interpreted in fromTZ when given, otherwise in local time.
 func parseTSWithFrom(input, fromTZ string) (time.Time, error) {
-	s := strings.TrimSpace(strings.ToLower(input))
-	if s == "" || s == "now" {
+	if strings.TrimSpace(input) == "" {
 		return time.Now(), nil
 	}
-
-	// Unix epoch (seconds or milliseconds)
-	if reNum := regexp.MustCompile(`^\d+$`); reNum.MatchString(s) {
-		if len(s) >= 13 {
-			ms, _ := strconv.ParseInt(s, 10, 64)
-			return time.Unix(0, ms*int64(time.Millisecond)), nil
-		}
-		sec, _ := strconv.ParseInt(s, 10, 64)
-		return time.Unix(sec, 0), nil
-	}
-
-	// RFC 3339 with timezone
-	if t, err := time.Parse(time.RFC3339, input); err == nil {
-		return t, nil
-	}
-
-	// RFC3339-like without zone -> interpret in fromTZ or local
-	if strings.Contains(input, "T") && !strings.ContainsAny(input, "zZ+-") {
-		loc := time.Local
-		if fromTZ != "" {
-			if l, err := time.LoadLocation(fromTZ); err == nil {
-				loc = l
-			}
-		}
-		if t, err := time.ParseInLocation("2006-01-02T15:04:05", input, loc); err == nil {
-			return t, nil
+	p := dateparse.Parser{}
+	if fromTZ != "" {
+		if l, err := time.LoadLocation(fromTZ); err == nil {
+			p.Location = l
 		}
 	}
-
-	// Date-only
-	if !strings.Contains(input, "T") {
-		loc := time.Local
-		if fromTZ != "" {
-			if l, err := time.LoadLocation(fromTZ); err == nil {
-				loc = l
-			}
-		}
-		if d, err := time.ParseInLocation("2006-01-02", input, loc); err == nil {
-			return d, nil
-		}
-	}
-
-	return time.Time{}, fmt.Errorf("invalid ts: %q (RFC3339, YYYY-MM-DD, 'now' or unix epoch)", input)
+	return p.Parse(input)
 }
 
 func parseDur(args map[string]interface{}) (time.Duration, error) {
@@ -737,68 +827,20 @@ func parseDur(args map[string]interface{}) (time.Duration, error) {
 	}
 
 	// String-based: Go duration, ISO-8601, or simple human phrases
-	s := pickArg(args, "dur", "duration", "delta")
-	if strings.TrimSpace(s) == "" {
-		return 0, fmt.Errorf("no duration provided")
-	}
-	if d, err := time.ParseDuration(s); err == nil {
-		return d, nil
-	}
-	if d, err := parseISODur(s); err == nil {
-		return d, nil
-	}
-	if d, err := parseHumanDur(s); err == nil {
-		return d, nil
-	}
-	return 0, fmt.Errorf("invalid duration: %q", s)
+	return dateparse.ParseDuration(pickArg(args, "dur", "duration", "delta"))
 }
 
-// Support a small ISO-8601 subset: PnDTnHnMnS (any part optional)
-func parseISODur(s string) (time.Duration, error) {
-	re := regexp.MustCompile(`(?i)^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
-	m := re.FindStringSubmatch(strings.TrimSpace(s))
-	if m == nil {
-		return 0, fmt.Errorf("not ISO-8601 duration")
-	}
-	val := func(i int) int64 {
-		if i >= len(m) || m[i] == "" {
-			return 0
-		}
-		v, _ := strconv.ParseInt(m[i], 10, 64)
-		return v
-	}
-	d := time.Duration(0)
-	d += time.Duration(val(1)) * 24 * time.Hour
-	d += time.Duration(val(2)) * time.Hour
-	d += time.Duration(val(3)) * time.Minute
-	d += time.Duration(val(4)) * time.Second
-	return d, nil
-}
-
-// Parse human phrases like "2 hours", "1 day 30 min", "90min"
-func parseHumanDur(s string) (time.Duration, error) {
-	re := regexp.MustCompile(`(?i)(\d+(?:\.\d+)?)\s*(days?|d|hours?|hrs?|h|minutes?|mins?|m|seconds?|secs?|s)`)
-	var sec float64
-	for _, m := range re.FindAllStringSubmatch(s, -1) {
-		numStr := m[1]
-		unit := strings.ToLower(m[2])
-		v, err := strconv.ParseFloat(numStr, 64)
-		if err != nil {
-			continue
-		}
-		switch unit {
-		case "day", "days", "d":
-			sec += v * 86400
-		case "hour", "hours", "hr", "hrs", "h":
-			sec += v * 3600
-		case "minute", "minutes", "min", "mins", "m":
-			sec += v * 60
-		case "second", "seconds", "sec", "secs", "s":
-			sec += v
-		}
-	}
-	if sec == 0 {
-		return 0, fmt.Errorf("not human duration")
+// timeDiff returns t2 - t1 rounded to whole units of unit (default
+// seconds), and the name of the unit.
+func timeDiff(t1, t2 time.Time, unit string) (float64, string) {
+	delta := t2.Sub(t1).Seconds()
+	switch strings.ToLower(unit) {
+	case "minutes", "minute", "min", "mins", "m":
+		return math.Round(delta / 60), "minutes"
+	case "hours", "hour", "hr", "hrs", "h":
+		return math.Round(delta / 3600), "hours"
+	case "days", "day", "d":
+		return math.Round(delta / 86400), "days"
 	}
-	return time.Duration(sec * float64(time.Second)), nil
+	return math.Round(delta), "seconds"
 }
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// registerAgendaTools adds the native MCP tools for the date based views of
+// the tasks: the agenda and the month calendar.
+func registerAgendaTools(s *server.MCPServer) {
+	// ---- agenda -------------------------------------------------------
+	agendaTool := mcp.NewTool(
+		"agenda",
+		append([]mcp.ToolOption{
+			mcp.WithDescription("Open tasks that are overdue or due within the next days, grouped into Overdue, Today, Tomorrow, This week and Later."),
+			mcp.WithNumber("days", mcp.Description("number of days ahead to include, starting today (default 7)")),
+			mcp.WithOutputSchema[agendaResult](),
+		}, withOutputArgs()...)...,
+	)
+	s.AddTool(agendaTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		argMap, _ := req.Params.Arguments.(map[string]interface{})
+		days := 7
+		if n, ok := argMap["days"].(float64); ok && n > 0 {
+			days = int(n)
+		}
+		opts, err := mcpOutputOptions(argMap)
+		if err != nil {
+			return nil, err
+		}
+
+		ctx, svc, err := prepareServices(ctx)
+		if err != nil {
+			return nil, err
+		}
+		groups, err := queryAgenda(ctx, svc, days)
+		if err != nil {
+			return nil, err
+		}
+		out, err := renderAgenda(groups, days, opts)
+		if err != nil {
+			return nil, err
+		}
+		result := agendaResult{Days: days, Groups: make([]mcpAgendaGroup, len(groups))}
+		for i, g := range groups {
+			result.Groups[i] = mcpAgendaGroup{Name: g.Name, Tasks: mcpTasksOf(g.Tasks)}
+		}
+		return structuredResult(result, out), nil
+	})
+	BuiltinTools = append(BuiltinTools, agendaTool)
+
+	// ---- calendar -----------------------------------------------------
+	calendarTool := mcp.NewTool(
+		"calendar",
+		append([]mcp.ToolOption{
+			mcp.WithDescription("Month calendar with the number of tasks due on, or scheduled across, each day, and the tasks per day."),
+			mcp.WithString("month", mcp.Description("month as YYYY-MM (default: current month)")),
+			mcp.WithBoolean("all", mcp.Description("count done tasks as well")),
+			mcp.WithOutputSchema[calendarResult](),
+		}, withOutputArgs()...)...,
+	)
+	s.AddTool(calendarTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		argMap, _ := req.Params.Arguments.(map[string]interface{})
+		month, _ := argMap["month"].(string)
+		all, _ := argMap["all"].(bool)
+		first, err := parseMonth(month, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		opts, err := mcpOutputOptions(argMap)
+		if err != nil {
+			return nil, err
+		}
+
+		ctx, svc, err := prepareServices(ctx)
+		if err != nil {
+			return nil, err
+		}
+		days, err := queryCalendar(ctx, svc, first, all)
+		if err != nil {
+			return nil, err
+		}
+		out, err := renderCalendar(first, days, opts)
+		if err != nil {
+			return nil, err
+		}
+		result := calendarResult{Month: first.Format("2006-01"), Days: []mcpCalendarDay{}}
+		for _, d := range busyDays(days) {
+			result.Days = append(result.Days, mcpCalendarDay{Date: d.Date, Count: d.Count, Tasks: mcpTasksOf(d.Tasks)})
+		}
+		return structuredResult(result, out), nil
+	})
+	BuiltinTools = append(BuiltinTools, calendarTool)
+}
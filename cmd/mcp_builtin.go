@@ -11,6 +11,14 @@ import (
 // List of built-in diagnostic tools (exposed e.g. in help)
 var BuiltinTools []mcp.Tool
 
+type echoResult struct {
+	Text string `json:"text"`
+}
+
+type sumResult struct {
+	Sum int `json:"sum"`
+}
+
 // registerBuiltinTools adds simple diagnostic tools that bypass Cobra.
 // They are useful to verify that the Kunja MCP server itself works
 // even when the Cobra integration fails.
@@ -19,9 +27,10 @@ func registerBuiltinTools(s *server.MCPServer) {
 	pingTool := mcp.NewTool(
 		"ping",
 		mcp.WithDescription("Return «pong» – verifies that the MCP server is alive."),
+		mcp.WithOutputSchema[messageResult](),
 	)
 	s.AddTool(pingTool, func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		return mcp.NewToolResultText("pong"), nil
+		return messageToolResult("pong"), nil
 	})
 	BuiltinTools = append(BuiltinTools, pingTool)
 
@@ -30,11 +39,12 @@ func registerBuiltinTools(s *server.MCPServer) {
 		"echo",
 		mcp.WithDescription("Echo back the supplied text argument."),
 		mcp.WithString("text", mcp.Required(), mcp.Description("text to echo")),
+		mcp.WithOutputSchema[echoResult](),
 	)
 	s.AddTool(echoTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, _ := req.Params.Arguments.(map[string]interface{})
 		text := fmt.Sprint(args["text"])
-		return mcp.NewToolResultText(text), nil
+		return structuredResult(echoResult{Text: text}, text), nil
 	})
 	BuiltinTools = append(BuiltinTools, echoTool)
 
@@ -44,6 +54,7 @@ func registerBuiltinTools(s *server.MCPServer) {
 		mcp.WithDescription("Return the sum of two integers."),
 		mcp.WithNumber("a", mcp.Required()),
 		mcp.WithNumber("b", mcp.Required()),
+		mcp.WithOutputSchema[sumResult](),
 	)
 	s.AddTool(sumTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, _ := req.Params.Arguments.(map[string]interface{})
@@ -51,7 +62,7 @@ func registerBuiltinTools(s *server.MCPServer) {
 		a, _ := args["a"].(float64)
 		b, _ := args["b"].(float64)
 		sum := int(a) + int(b)
-		return mcp.NewToolResultText(fmt.Sprintf("%d", sum)), nil
+		return structuredResult(sumResult{Sum: sum}, fmt.Sprintf("%d", sum)), nil
 	})
 	BuiltinTools = append(BuiltinTools, sumTool)
 }
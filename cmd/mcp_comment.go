@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// registerCommentTools adds the native MCP tools for reading and writing
+// task comments, e.g. so an assistant can leave progress notes on a task.
+func registerCommentTools(s *server.MCPServer) {
+	// ---- comments -----------------------------------------------------
+	commentsTool := mcp.NewTool(
+		"comments",
+		append([]mcp.ToolOption{
+			mcp.WithDescription("List the comments of a task, or render them in another format via output."),
+			mcp.WithNumber("id", mcp.Required(), mcp.Description("task ID")),
+			mcp.WithOutputSchema[commentListResult](),
+		}, withOutputArgs()...)...,
+	)
+	s.AddTool(commentsTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		argMap, _ := req.Params.Arguments.(map[string]interface{})
+		idFloat, ok := argMap["id"].(float64)
+		if !ok {
+			return nil, fmt.Errorf("id argument is required")
+		}
+		opts, err := mcpOutputOptions(argMap)
+		if err != nil {
+			return nil, err
+		}
+
+		ctx, svc, err := prepareServices(ctx)
+		if err != nil {
+			return nil, err
+		}
+		comments, err := svc.Comment.GetTaskComments(ctx, int(idFloat))
+		if err != nil {
+			return nil, err
+		}
+		out, err := renderCommentList(int(idFloat), comments, opts)
+		if err != nil {
+			return nil, err
+		}
+		result := commentListResult{TaskID: int(idFloat), Comments: make([]mcpComment, len(comments))}
+		for i, c := range comments {
+			result.Comments[i] = mcpCommentOf(c)
+		}
+		return structuredResult(result, out), nil
+	})
+	BuiltinTools = append(BuiltinTools, commentsTool)
+
+	// ---- comment_add --------------------------------------------------
+	addTool := mcp.NewTool(
+		"comment_add",
+		mcp.WithDescription("Add a comment to a task, e.g. a progress note."),
+		mcp.WithNumber("id", mcp.Required(), mcp.Description("task ID")),
+		mcp.WithString("text", mcp.Required(), mcp.Description("comment text")),
+		mcp.WithOutputSchema[commentResult](),
+	)
+	s.AddTool(addTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		argMap, _ := req.Params.Arguments.(map[string]interface{})
+		idFloat, ok := argMap["id"].(float64)
+		if !ok {
+			return nil, fmt.Errorf("id argument is required")
+		}
+		text, _ := argMap["text"].(string)
+
+		ctx, svc, err := prepareServices(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c, out, err := addTaskComment(ctx, svc, int(idFloat), text)
+		if err != nil {
+			return nil, err
+		}
+		return structuredResult(commentResult{TaskID: int(idFloat), Comment: mcpCommentOf(c), Message: out}, out), nil
+	})
+	BuiltinTools = append(BuiltinTools, addTool)
+
+	// ---- comment_edit -------------------------------------------------
+	editTool := mcp.NewTool(
+		"comment_edit",
+		mcp.WithDescription("Replace the text of an existing task comment."),
+		mcp.WithNumber("id", mcp.Required(), mcp.Description("task ID")),
+		mcp.WithNumber("comment_id", mcp.Required(), mcp.Description("comment ID")),
+		mcp.WithString("text", mcp.Required(), mcp.Description("new comment text")),
+		mcp.WithOutputSchema[commentResult](),
+	)
+	s.AddTool(editTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		argMap, _ := req.Params.Arguments.(map[string]interface{})
+		idFloat, ok := argMap["id"].(float64)
+		if !ok {
+			return nil, fmt.Errorf("id argument is required")
+		}
+		commentFloat, ok := argMap["comment_id"].(float64)
+		if !ok {
+			return nil, fmt.Errorf("comment_id argument is required")
+		}
+		text, _ := argMap["text"].(string)
+
+		ctx, svc, err := prepareServices(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c, out, err := editTaskComment(ctx, svc, int(idFloat), int(commentFloat), text)
+		if err != nil {
+			return nil, err
+		}
+		return structuredResult(commentResult{TaskID: int(idFloat), Comment: mcpCommentOf(c), Message: out}, out), nil
+	})
+	BuiltinTools = append(BuiltinTools, editTool)
+
+	// ---- comment_delete -----------------------------------------------
+	deleteTool := mcp.NewTool(
+		"comment_delete",
+		mcp.WithDescription("Delete a task comment."),
+		mcp.WithNumber("id", mcp.Required(), mcp.Description("task ID")),
+		mcp.WithNumber("comment_id", mcp.Required(), mcp.Description("comment ID")),
+		mcp.WithOutputSchema[messageResult](),
+	)
+	s.AddTool(deleteTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		argMap, _ := req.Params.Arguments.(map[string]interface{})
+		idFloat, ok := argMap["id"].(float64)
+		if !ok {
+			return nil, fmt.Errorf("id argument is required")
+		}
+		commentFloat, ok := argMap["comment_id"].(float64)
+		if !ok {
+			return nil, fmt.Errorf("comment_id argument is required")
+		}
+
+		ctx, svc, err := prepareServices(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := svc.Comment.DeleteTaskComment(ctx, int(idFloat), int(commentFloat)); err != nil {
+			return nil, err
+		}
+		return messageToolResult("Comment deleted."), nil
+	})
+	BuiltinTools = append(BuiltinTools, deleteTool)
+}
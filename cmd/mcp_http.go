@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/spf13/viper"
+)
+
+var (
+	mcpTransport string
+	mcpListen    string
+)
+
+// mcpShutdownGrace is how long in-flight tool calls may take to finish
+// after SIGINT or SIGTERM before they are cancelled.
+const mcpShutdownGrace = 10 * time.Second
+
+// mcpCallerKey is the context key of the name of the authenticated HTTP
+// caller: the user of the API key, or "" for the shared bearer token.
+type mcpCallerKey struct{}
+
+// mcpCaller returns the caller recorded by the HTTP authentication.
+func mcpCaller(ctx context.Context) (string, bool) {
+	name, ok := ctx.Value(mcpCallerKey{}).(string)
+	return name, ok
+}
+
+// mcpAuth holds the credentials callers of the HTTP transport
+// authenticate with: a shared bearer token and API keys per user.
+type mcpAuth struct {
+	token string
+	keys  map[string]string // user -> API key
+}
+
+// mcpAuthFromConfig reads mcp.token and mcp.api_keys. Serving over HTTP
+// without either is refused.
+func mcpAuthFromConfig() (mcpAuth, error) {
+	a := mcpAuth{
+		token: viper.GetString("mcp.token"),
+		keys:  viper.GetStringMapString("mcp.api_keys"),
+	}
+	for user, key := range a.keys {
+		if strings.TrimSpace(key) == "" {
+			return a, fmt.Errorf("empty API key for %q in mcp.api_keys", user)
+		}
+	}
+	if a.token == "" && len(a.keys) == 0 {
+		return a, errors.New("no mcp.token or mcp.api_keys configured – the HTTP transport requires authentication")
+	}
+	return a, nil
+}
+
+// caller returns who r is authenticated as. The credential is taken from
+// an "Authorization: Bearer" or an "X-API-Key" header.
+func (a mcpAuth) caller(r *http.Request) (string, bool) {
+	cred := r.Header.Get("X-API-Key")
+	if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		cred = strings.TrimSpace(bearer)
+	}
+	if cred == "" {
+		return "", false
+	}
+	if a.token != "" && subtle.ConstantTimeCompare([]byte(cred), []byte(a.token)) == 1 {
+		return "", true
+	}
+	for user, key := range a.keys {
+		if subtle.ConstantTimeCompare([]byte(cred), []byte(key)) == 1 {
+			return user, true
+		}
+	}
+	return "", false
+}
+
+// handler rejects unauthenticated requests and records the caller in the
+// request context for the tool handlers.
+func (a mcpAuth) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, ok := a.caller(r)
+		if !ok {
+			log.Printf("!! unauthorized %s %s from %s\n", r.Method, r.URL.Path, r.RemoteAddr)
+			w.Header().Set("WWW-Authenticate", `Bearer realm="kunja"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), mcpCallerKey{}, user)))
+	})
+}
+
+// mcpConnections tracks the long-lived connections of MCP sessions – SSE
+// streams and streamable HTTP listeners – so that tool calls of a session
+// are cancelled when its connection goes away. Streamable HTTP tool calls
+// already run in the context of their request; SSE answers on the stream,
+// so its tool calls would otherwise outlive the client.
+type mcpConnections struct {
+	mu   sync.Mutex
+	ctxs map[string]context.Context // session ID -> connection context
+}
+
+func newMCPConnections() *mcpConnections {
+	return &mcpConnections{ctxs: map[string]context.Context{}}
+}
+
+func (c *mcpConnections) register(ctx context.Context, session server.ClientSession) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ctxs[session.SessionID()] = ctx
+}
+
+func (c *mcpConnections) unregister(ctx context.Context, session server.ClientSession) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.ctxs, session.SessionID())
+}
+
+// middleware cancels the context of a tool call, and with it the API
+// requests it makes, when the connection of its session closes.
+func (c *mcpConnections) middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if session := server.ClientSessionFromContext(ctx); session != nil {
+			c.mu.Lock()
+			conn, ok := c.ctxs[session.SessionID()]
+			c.mu.Unlock()
+			if ok {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithCancel(ctx)
+				defer cancel()
+				stop := context.AfterFunc(conn, cancel)
+				defer stop()
+			}
+		}
+		if user, ok := mcpCaller(ctx); ok && user != "" {
+			log.Printf(">> %s calls %s\n", user, req.Params.Name)
+		}
+		return next(ctx, req)
+	}
+}
+
+// serveMCPHTTP serves s on addr, over streamable HTTP at /mcp and over SSE
+// at /sse and /message, until SIGINT or SIGTERM. On a signal it stops
+// accepting requests, closes the event streams and gives in-flight tool
+// calls mcpShutdownGrace to finish before cancelling them.
+func serveMCPHTTP(s *server.MCPServer, addr string, sessions *mcpSessions, subs *mcpSubscriptions) error {
+	auth, err := mcpAuthFromConfig()
+	if err != nil {
+		return err
+	}
+
+	// streams is cancelled when shutdown starts, to end the event streams,
+	// which would otherwise keep the server from going idle; base is
+	// cancelled when the grace period is over.
+	streams, closeStreams := context.WithCancel(context.Background())
+	defer closeStreams()
+	base, cancelBase := context.WithCancel(context.Background())
+	defer cancelBase()
+
+	httpSrv := &http.Server{
+		Addr:              addr,
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return base },
+	}
+	httpSrv.RegisterOnShutdown(closeStreams)
+
+	ids := &mcpSessionIDs{sessions: sessions, subscriptions: subs}
+	streamable := server.NewStreamableHTTPServer(s,
+		server.WithHTTPContextFunc(headerCredentials),
+		server.WithSessionIdManager(ids),
+	)
+	sse := server.NewSSEServer(s, server.WithSSEContextFunc(headerCredentials))
+	mux := http.NewServeMux()
+	mux.Handle("/mcp", subs.streamableHandler(streamable, ids))
+	mux.Handle("/sse", sse)
+	mux.Handle("/message", subs.sseHandler(sse, sse))
+	httpSrv.Handler = auth.handler(endOnShutdown(streams, mux))
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	log.Printf("MCP server listening on %s (streamable HTTP at /mcp, SSE at /sse)\n", ln.Addr())
+
+	errc := make(chan error, 1)
+	go func() { errc <- httpSrv.Serve(ln) }()
+
+	signals, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	select {
+	case err := <-errc:
+		return err
+	case <-signals.Done():
+	}
+	stop() // a second signal terminates immediately
+
+	log.Printf("Shutting down the MCP server …\n")
+	ctx, cancel := context.WithTimeout(context.Background(), mcpShutdownGrace)
+	defer cancel()
+	if err := httpSrv.Shutdown(ctx); err != nil {
+		log.Printf("!! graceful shutdown: %v – cancelling the remaining calls\n", err)
+		cancelBase()
+		httpSrv.Close()
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// endOnShutdown ties the context of GET requests, the event streams of
+// both transports, to streams.
+func endOnShutdown(streams context.Context, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			ctx, cancel := context.WithCancel(r.Context())
+			defer cancel()
+			stop := context.AfterFunc(streams, cancel)
+			defer stop()
+			r = r.WithContext(ctx)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
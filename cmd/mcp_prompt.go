@@ -0,0 +1,257 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"kunja/api"
+	"kunja/internal/render"
+)
+
+// promptSection is a titled block of task data in a prompt.
+type promptSection struct {
+	Title string
+	Body  string
+}
+
+// promptMessage joins the instructions and the sections into the single
+// user message the prompts consist of.
+func promptMessage(description, instructions string, sections ...promptSection) *mcp.GetPromptResult {
+	var b strings.Builder
+	b.WriteString(strings.TrimSpace(instructions))
+	b.WriteString("\n")
+	for _, s := range sections {
+		fmt.Fprintf(&b, "\n## %s\n\n", s.Title)
+		body := strings.TrimRight(s.Body, "\n")
+		if body == "" {
+			body = "(none)"
+		}
+		b.WriteString(body + "\n")
+	}
+	return mcp.NewGetPromptResult(description, []mcp.PromptMessage{
+		mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(b.String())),
+	})
+}
+
+// taskListText renders tasks as the table of the list tool, or returns ""
+// if there are none.
+func taskListText(tasks []api.Task, columns []string) (string, error) {
+	if len(tasks) == 0 {
+		return "", nil
+	}
+	table, err := taskTable(tasks, columns)
+	if err != nil {
+		return "", err
+	}
+	return render.Options{}.String(tasks, table)
+}
+
+// dailyPlanPrompt shows what is overdue or due today and tomorrow.
+func dailyPlanPrompt(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	ctx, svc, err := prepareServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	agenda, err := buildAgenda(ctx, svc, 2, render.Options{})
+	if err != nil {
+		return nil, err
+	}
+	return promptMessage("Plan today from the overdue tasks and those due today and tomorrow", `
+Help me plan my day. Below are my open tasks that are overdue or due today
+or tomorrow; call the "now" tool first to know the current date and time.
+
+1. Propose a realistic plan for today, most important first, and say which
+   overdue tasks I should do, reschedule or drop.
+2. For tasks that do not fit, suggest new due dates and, once I agree, set
+   them with the "edit" tool (id, due).
+3. When I report a task finished, mark it with the "done" tool; record
+   progress on unfinished work with "comment_add".
+
+Use the "list" tool (e.g. filter "priority >= 4") if you need tasks that
+are not shown here. Do not change anything without asking me first.`,
+		promptSection{"Overdue and due soon", agenda},
+	), nil
+}
+
+// weeklyReviewPrompt shows what was done in the last week and what is
+// overdue or due in the next one.
+func weeklyReviewPrompt(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	ctx, svc, err := prepareServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	done, _, err := fetchFilteredTasks(ctx, svc, taskFilter{Expr: "done_at >= now-7d", ShowAll: true, Sort: "done_at"})
+	if err != nil {
+		return nil, err
+	}
+	finished, err := taskListText(done, []string{"id", "title", "project", "done_at"})
+	if err != nil {
+		return nil, err
+	}
+	agenda, err := buildAgenda(ctx, svc, 7, render.Options{})
+	if err != nil {
+		return nil, err
+	}
+	return promptMessage("Review the past week and plan the next one", `
+Run a weekly review with me. Below are the tasks I finished in the last
+seven days, and my open tasks that are overdue or due within the next seven
+days; call the "now" tool first to know the current date.
+
+1. Summarise what I got done, grouped by project ("projects" lists their
+   names).
+2. Go through the overdue tasks one by one and ask whether to do,
+   reschedule ("edit" with due) or delete ("delete") each.
+3. Check that the coming week is not overloaded and suggest what to move.
+4. Ask whether anything is missing and add it with the "new" tool.
+
+Do not change anything without asking me first.`,
+		promptSection{"Done in the last 7 days", finished},
+		promptSection{"Overdue and due in the next 7 days", agenda},
+	), nil
+}
+
+// triageInboxPrompt shows the open tasks of the inbox project and the open
+// tasks elsewhere that nobody is assigned to.
+func triageInboxPrompt(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	ctx, svc, err := prepareServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var inbox api.Project
+	if ref := strings.TrimSpace(req.Params.Arguments["project"]); ref != "" {
+		inbox, err = resolveProject(ctx, svc, ref)
+	} else if inbox, err = resolveProject(ctx, svc, "Inbox"); err != nil {
+		err = fmt.Errorf("%w – pass the inbox as the project argument", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	queued, _, err := fetchFilteredTasks(ctx, svc, taskFilter{Project: strconv.Itoa(inbox.ID)})
+	if err != nil {
+		return nil, err
+	}
+	sortByUrgency(queued)
+	inboxTasks, err := taskListText(queued, []string{"id", "title", "due", "priority", "labels", "created"})
+	if err != nil {
+		return nil, err
+	}
+	open, _, err := fetchFilteredTasks(ctx, svc, taskFilter{})
+	if err != nil {
+		return nil, err
+	}
+	var unassigned []api.Task
+	for _, t := range open {
+		if len(t.Assignees) == 0 && t.ProjectID != inbox.ID {
+			unassigned = append(unassigned, t)
+		}
+	}
+	sortByUrgency(unassigned)
+	unassignedText, err := taskListText(unassigned, []string{"id", "title", "project", "due", "priority"})
+	if err != nil {
+		return nil, err
+	}
+
+	return promptMessage("Sort the inbox and the unassigned tasks", fmt.Sprintf(`
+Help me triage my inbox, the project %q (#%d). Below are its open tasks,
+and the open tasks in other projects that nobody is assigned to.
+
+1. For each inbox task, suggest a project (the "projects" tool lists them),
+   a due date if it has a deadline, and whether it is still worth doing.
+2. Once I agree, move and schedule it with the "edit" tool (id, project,
+   due), or remove it with "delete".
+3. Point out unassigned tasks that look stale or urgent and ask who should
+   take them.
+
+Use the "list" tool for more detail on a task. Do not change anything
+without asking me first.`, inbox.Title, inbox.ID),
+		promptSection{fmt.Sprintf("Open tasks in %s", inbox.Title), inboxTasks},
+		promptSection{"Unassigned open tasks in other projects", unassignedText},
+	), nil
+}
+
+// breakDownTaskPrompt shows a task with its description, to be split into
+// subtasks.
+func breakDownTaskPrompt(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	id, err := strconv.Atoi(strings.TrimSpace(req.Params.Arguments["id"]))
+	if err != nil || id <= 0 {
+		return nil, fmt.Errorf("invalid task ID %q", req.Params.Arguments["id"])
+	}
+	ctx, svc, err := prepareServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	t, err := svc.Task.GetTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Title: %s\n", t.Title)
+	if p, err := svc.Project.GetProject(ctx, t.ProjectID); err == nil {
+		fmt.Fprintf(&b, "Project: %s (#%d)\n", p.Title, p.ID)
+	} else {
+		fmt.Fprintf(&b, "Project: #%d\n", t.ProjectID)
+	}
+	if due, ok := dueTime(t); ok {
+		fmt.Fprintf(&b, "Due: %s\n", formatDue(due))
+	}
+	if t.Priority > 0 {
+		fmt.Fprintf(&b, "Priority: %d\n", t.Priority)
+	}
+	if len(t.Labels) > 0 {
+		labels := make([]string, len(t.Labels))
+		for i, l := range t.Labels {
+			labels[i] = l.Title
+		}
+		fmt.Fprintf(&b, "Labels: %s\n", strings.Join(labels, ", "))
+	}
+	if t.Done {
+		b.WriteString("Done: yes\n")
+	}
+	description := strings.TrimSpace(t.Description)
+	if description == "" {
+		description = "(no description)"
+	}
+
+	return promptMessage(fmt.Sprintf("Break task #%d down into subtasks", t.ID), fmt.Sprintf(`
+Help me break task #%d down into subtasks. Its details and description are
+below.
+
+1. Propose 3 to 8 concrete subtasks, each doable in one sitting, as a
+   numbered list in the order they should be done, with a due date before
+   the task's own where it has one. Ask about anything unclear first.
+2. Once I agree, create each with the "new" tool in project %d, passing
+   the due date in its "due" argument (YYYY-MM-DD or e.g. "next friday") and
+   not in the title.
+3. Then add a comment to task #%d with "comment_add" listing the IDs of the
+   new tasks.
+
+Do not change task #%d itself unless I ask.`, t.ID, t.ProjectID, t.ID, t.ID),
+		promptSection{fmt.Sprintf("Task #%d", t.ID), b.String()},
+		promptSection{"Description", description},
+	), nil
+}
+
+// registerPrompts adds the planning prompts.
+func registerPrompts(s *server.MCPServer) {
+	s.AddPrompt(mcp.NewPrompt("daily_plan",
+		mcp.WithPromptDescription("Plan today from the overdue tasks and those due today and tomorrow"),
+	), dailyPlanPrompt)
+	s.AddPrompt(mcp.NewPrompt("weekly_review",
+		mcp.WithPromptDescription("Review the tasks finished last week and plan the overdue and coming ones"),
+	), weeklyReviewPrompt)
+	s.AddPrompt(mcp.NewPrompt("triage_inbox",
+		mcp.WithPromptDescription("Sort the inbox project and the unassigned open tasks into projects and schedules"),
+		mcp.WithArgument("project", mcp.ArgumentDescription("inbox project (ID, title or identifier); defaults to Inbox")),
+	), triageInboxPrompt)
+	s.AddPrompt(mcp.NewPrompt("break_down_task",
+		mcp.WithPromptDescription("Split a task into subtasks and create them"),
+		mcp.WithArgument("id", mcp.RequiredArgument(), mcp.ArgumentDescription("task ID")),
+	), breakDownTaskPrompt)
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// toolNameUnsafe matches the characters a report name may contain but an
+// MCP tool name should not.
+var toolNameUnsafe = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// registerReportTools exposes every report as a native MCP tool named
+// report_<name>, plus a generic report tool that lists the reports or runs
+// one (including Vikunja saved filters) by name.
+func registerReportTools(s *server.MCPServer) {
+	// ---- report -------------------------------------------------------
+	reportTool := mcp.NewTool(
+		"report",
+		append([]mcp.ToolOption{
+			mcp.WithDescription("Run a named report (a saved task view from the config, e.g. standup or overdue, or a Vikunja saved filter by title). Without name, list the available reports."),
+			mcp.WithString("name", mcp.Description("report name or saved filter title/ID; omit to list reports")),
+			mcp.WithOutputSchema[reportResult](),
+		}, withOutputArgs()...)...,
+	)
+	s.AddTool(reportTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		argMap, _ := req.Params.Arguments.(map[string]interface{})
+		name, _ := argMap["name"].(string)
+		return reportHandler(ctx, strings.TrimSpace(name), argMap)
+	})
+	BuiltinTools = append(BuiltinTools, reportTool)
+
+	// ---- report_<name> ------------------------------------------------
+	defs, err := reports()
+	if err != nil {
+		log.Printf("!! %v\n", err)
+		return
+	}
+	for _, name := range sortedReportNames(defs) {
+		name := name
+		desc := defs[name].Description
+		if desc == "" {
+			desc = fmt.Sprintf("Tasks of the %s report", name)
+		}
+		tool := mcp.NewTool(
+			"report_"+strings.Trim(toolNameUnsafe.ReplaceAllString(name, "_"), "_"),
+			append([]mcp.ToolOption{
+				mcp.WithDescription(fmt.Sprintf("Run the %s report: %s.", name, strings.TrimSuffix(desc, "."))),
+				mcp.WithOutputSchema[reportResult](),
+			}, withOutputArgs()...)...,
+		)
+		s.AddTool(tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			argMap, _ := req.Params.Arguments.(map[string]interface{})
+			return reportHandler(ctx, name, argMap)
+		})
+		BuiltinTools = append(BuiltinTools, tool)
+	}
+}
+
+// reportHandler runs the named report, or lists the reports when name is
+// empty. Without an output argument the report's own format is used.
+func reportHandler(ctx context.Context, name string, argMap map[string]interface{}) (*mcp.CallToolResult, error) {
+	opts, err := mcpOutputOptions(argMap)
+	if err != nil {
+		return nil, err
+	}
+	ctx, svc, err := prepareServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		entries, err := reportEntries(ctx, svc)
+		if err != nil {
+			return nil, err
+		}
+		out, err := renderReportList(entries, opts)
+		if err != nil {
+			return nil, err
+		}
+		return structuredResult(reportResult{Reports: entries}, out), nil
+	}
+
+	f, opts, err := reportQuery(name, opts)
+	if err != nil {
+		return nil, err
+	}
+	tasks, total, err := queryTaskList(ctx, svc, f)
+	if err != nil {
+		return nil, err
+	}
+	out, err := renderTaskList(tasks, total, opts, f.Columns)
+	if err != nil {
+		return nil, err
+	}
+	return structuredResult(reportResult{Report: name, Tasks: mcpTasksOf(tasks), Shown: len(tasks), Total: total}, out), nil
+}
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"kunja/api"
+)
+
+// kunjaResource is a parsed kunja:// resource URI.
+type kunjaResource struct {
+	Kind string // "task", "project", "project-tasks" or "overdue"
+	ID   int
+}
+
+// parseResourceURI parses kunja://task/{id}, kunja://project/{id},
+// kunja://project/{id}/tasks and kunja://reports/overdue.
+func parseResourceURI(uri string) (kunjaResource, error) {
+	rest, ok := strings.CutPrefix(uri, "kunja://")
+	if !ok {
+		return kunjaResource{}, fmt.Errorf("unknown resource %q", uri)
+	}
+	parts := strings.Split(rest, "/")
+	if len(parts) == 2 && parts[0] == "reports" && parts[1] == "overdue" {
+		return kunjaResource{Kind: "overdue"}, nil
+	}
+	if len(parts) < 2 {
+		return kunjaResource{}, fmt.Errorf("unknown resource %q", uri)
+	}
+	id, err := strconv.Atoi(parts[1])
+	if err != nil || id <= 0 {
+		return kunjaResource{}, fmt.Errorf("invalid ID %q in resource %q", parts[1], uri)
+	}
+	switch {
+	case len(parts) == 2 && parts[0] == "task":
+		return kunjaResource{Kind: "task", ID: id}, nil
+	case len(parts) == 2 && parts[0] == "project":
+		return kunjaResource{Kind: "project", ID: id}, nil
+	case len(parts) == 3 && parts[0] == "project" && parts[2] == "tasks":
+		return kunjaResource{Kind: "project-tasks", ID: id}, nil
+	}
+	return kunjaResource{}, fmt.Errorf("unknown resource %q", uri)
+}
+
+// load fetches what the resource shows: an api.Task, an api.Project or, for
+// the task lists, []api.Task.
+func (r kunjaResource) load(ctx context.Context, svc Services) (interface{}, error) {
+	switch r.Kind {
+	case "task":
+		return svc.Task.GetTask(ctx, r.ID)
+	case "project":
+		return svc.Project.GetProject(ctx, r.ID)
+	case "project-tasks":
+		tasks, _, err := fetchFilteredTasks(ctx, svc, taskFilter{Project: strconv.Itoa(r.ID)})
+		if err != nil {
+			return nil, err
+		}
+		sortByUrgency(tasks)
+		return tasks, nil
+	}
+	report, err := lookupReport("overdue")
+	if err != nil {
+		return nil, err
+	}
+	tasks, _, err := fetchFilteredTasks(ctx, svc, report.taskFilter())
+	return tasks, err
+}
+
+// resourceVersion fingerprints a loaded resource by the IDs and Updated
+// times of what it shows, so that it changes whenever a task of it is
+// changed, added or removed.
+func resourceVersion(v interface{}) string {
+	h := fnv.New64a()
+	switch v := v.(type) {
+	case api.Task:
+		fmt.Fprintf(h, "%d %s\n", v.ID, v.Updated.UTC().Format(time.RFC3339Nano))
+	case api.Project:
+		fmt.Fprintf(h, "%d %s\n", v.ID, v.Updated)
+	case []api.Task:
+		for _, t := range v {
+			fmt.Fprintf(h, "%d %s\n", t.ID, t.Updated.UTC().Format(time.RFC3339Nano))
+		}
+	}
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+// readResource answers resources/read for every kunja:// resource.
+func readResource(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	uri := req.Params.URI
+	r, err := parseResourceURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	ctx, svc, err := prepareServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	v, err := r.load(ctx, svc)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Kind == "overdue" {
+		projects := map[int]string{}
+		if all, err := svc.Project.GetAllProjects(ctx); err == nil {
+			for _, p := range all {
+				projects[p.ID] = p.Title
+			}
+		}
+		return []mcp.ResourceContents{mcp.TextResourceContents{
+			URI:      uri,
+			MIMEType: "text/markdown",
+			Text:     overdueMarkdown(v.([]api.Task), projects),
+		}}, nil
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return []mcp.ResourceContents{mcp.TextResourceContents{
+		URI:      uri,
+		MIMEType: "application/json",
+		Text:     string(data),
+	}}, nil
+}
+
+// overdueMarkdown renders the overdue report as a Markdown list.
+func overdueMarkdown(tasks []api.Task, projects map[int]string) string {
+	var b strings.Builder
+	b.WriteString("# Overdue tasks\n\n")
+	if len(tasks) == 0 {
+		b.WriteString("Nothing is overdue.\n")
+		return b.String()
+	}
+	for _, t := range tasks {
+		fmt.Fprintf(&b, "- **#%d %s**", t.ID, t.Title)
+		if due, ok := dueTime(t); ok {
+			fmt.Fprintf(&b, " – due %s", formatDue(due))
+		}
+		if t.Priority > 0 {
+			fmt.Fprintf(&b, ", priority %d", t.Priority)
+		}
+		if title, ok := projects[t.ProjectID]; ok {
+			fmt.Fprintf(&b, ", project %s", title)
+		}
+		if len(t.Labels) > 0 {
+			labels := make([]string, len(t.Labels))
+			for i, l := range t.Labels {
+				labels[i] = l.Title
+			}
+			fmt.Fprintf(&b, ", labels %s", strings.Join(labels, ", "))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// registerResources adds the kunja:// resources and resource templates.
+func registerResources(s *server.MCPServer) {
+	s.AddResource(mcp.NewResource(
+		"kunja://reports/overdue",
+		"Overdue tasks",
+		mcp.WithResourceDescription("Open tasks past their due date, oldest first"),
+		mcp.WithMIMEType("text/markdown"),
+	), readResource)
+
+	s.AddResourceTemplate(mcp.NewResourceTemplate(
+		"kunja://task/{id}",
+		"Task",
+		mcp.WithTemplateDescription("A task with its labels, assignees and relations"),
+		mcp.WithTemplateMIMEType("application/json"),
+	), readResource)
+	s.AddResourceTemplate(mcp.NewResourceTemplate(
+		"kunja://project/{id}",
+		"Project",
+		mcp.WithTemplateDescription("A project"),
+		mcp.WithTemplateMIMEType("application/json"),
+	), readResource)
+	s.AddResourceTemplate(mcp.NewResourceTemplate(
+		"kunja://project/{id}/tasks",
+		"Project tasks",
+		mcp.WithTemplateDescription("The open tasks of a project, most urgent first"),
+		mcp.WithTemplateMIMEType("application/json"),
+	), readResource)
+}
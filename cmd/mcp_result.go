@@ -0,0 +1,256 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"kunja/api"
+)
+
+// The native MCP tools return their result twice: as structuredContent, a
+// JSON object described by the tool's output schema, and as text for the
+// clients that do not read structuredContent. The text is what the tools
+// always returned; the output argument only changes the text.
+
+// mcpTask is a task in structured tool results.
+type mcpTask struct {
+	ID          int      `json:"id"`
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	Done        bool     `json:"done"`
+	Priority    int      `json:"priority" jsonschema:"description=0 (unset) to 5 (do now)"`
+	Urgency     float64  `json:"urgency" jsonschema:"description=computed urgency; higher is more urgent"`
+	Due         string   `json:"due,omitempty" jsonschema:"description=due date (RFC 3339)"`
+	Start       string   `json:"start,omitempty" jsonschema:"description=start date (RFC 3339)"`
+	End         string   `json:"end,omitempty" jsonschema:"description=end date (RFC 3339)"`
+	DoneAt      string   `json:"done_at,omitempty" jsonschema:"description=when the task was done (RFC 3339)"`
+	PercentDone float64  `json:"percent_done"`
+	Favorite    bool     `json:"favorite"`
+	ProjectID   int      `json:"project_id"`
+	Labels      []string `json:"labels" jsonschema:"description=label titles"`
+	Assignees   []string `json:"assignees" jsonschema:"description=usernames"`
+	URI         string   `json:"uri" jsonschema:"description=kunja:// resource of the task"`
+}
+
+// mcpTaskOf converts a task for a structured result.
+func mcpTaskOf(t api.Task) mcpTask {
+	task := mcpTask{
+		ID:          t.ID,
+		Title:       t.Title,
+		Description: t.Description,
+		Done:        t.Done,
+		Priority:    t.Priority,
+		Urgency:     t.Urgency,
+		Due:         formatDate(t.DueDate, time.RFC3339),
+		Start:       formatDate(t.StartDate, time.RFC3339),
+		End:         formatDate(t.EndDate, time.RFC3339),
+		PercentDone: t.PercentDone,
+		Favorite:    t.IsFavorite,
+		ProjectID:   t.ProjectID,
+		Labels:      []string{},
+		Assignees:   []string{},
+		URI:         fmt.Sprintf("kunja://task/%d", t.ID),
+	}
+	if t.Done {
+		task.DoneAt = formatDate(t.DoneAt, time.RFC3339)
+	}
+	for _, l := range t.Labels {
+		task.Labels = append(task.Labels, l.Title)
+	}
+	for _, u := range t.Assignees {
+		task.Assignees = append(task.Assignees, u.Username)
+	}
+	return task
+}
+
+// mcpTasksOf converts tasks for a structured result; it never returns nil,
+// so that an empty list is [] rather than null.
+func mcpTasksOf(tasks []api.Task) []mcpTask {
+	out := make([]mcpTask, len(tasks))
+	for i, t := range tasks {
+		out[i] = mcpTaskOf(t)
+	}
+	return out
+}
+
+// mcpProject is a project in structured tool results.
+type mcpProject struct {
+	ID              int    `json:"id"`
+	Title           string `json:"title"`
+	Description     string `json:"description,omitempty"`
+	Identifier      string `json:"identifier,omitempty"`
+	ParentProjectID int    `json:"parent_project_id" jsonschema:"description=0 for top-level projects"`
+	Favorite        bool   `json:"favorite"`
+	Archived        bool   `json:"archived"`
+	URI             string `json:"uri" jsonschema:"description=kunja:// resource of the project"`
+}
+
+func mcpProjectOf(p api.Project) mcpProject {
+	return mcpProject{
+		ID:              p.ID,
+		Title:           p.Title,
+		Description:     p.Description,
+		Identifier:      p.Identifier,
+		ParentProjectID: p.ParentProjectID,
+		Favorite:        p.IsFavorite,
+		Archived:        p.IsArchived,
+		URI:             fmt.Sprintf("kunja://project/%d", p.ID),
+	}
+}
+
+// mcpComment is a task comment in structured tool results.
+type mcpComment struct {
+	ID      int    `json:"id"`
+	Author  string `json:"author" jsonschema:"description=username"`
+	Text    string `json:"text"`
+	Created string `json:"created" jsonschema:"description=RFC 3339"`
+	Updated string `json:"updated" jsonschema:"description=RFC 3339"`
+}
+
+func mcpCommentOf(c api.TaskComment) mcpComment {
+	return mcpComment{
+		ID:      c.ID,
+		Author:  c.Author.Username,
+		Text:    c.Comment,
+		Created: formatDate(c.Created, time.RFC3339),
+		Updated: formatDate(c.Updated, time.RFC3339),
+	}
+}
+
+// ---------------------------------------------------------------------
+// Result objects, one per kind of tool; the output schemas are derived
+// from them.
+// ---------------------------------------------------------------------
+
+type taskListResult struct {
+	Tasks []mcpTask `json:"tasks"`
+	Shown int       `json:"shown" jsonschema:"description=number of tasks returned"`
+	Total int       `json:"total" jsonschema:"description=number of matching tasks\\, possibly more than shown"`
+}
+
+type taskResult struct {
+	Task    mcpTask `json:"task"`
+	Message string  `json:"message"`
+}
+
+type deleteResult struct {
+	Deleted []int          `json:"deleted" jsonschema:"description=IDs of the deleted tasks"`
+	Failed  []deleteFailed `json:"failed"`
+}
+
+type deleteFailed struct {
+	ID    int    `json:"id"`
+	Error string `json:"error"`
+}
+
+type projectListResult struct {
+	Projects []mcpProject `json:"projects"`
+}
+
+type projectResult struct {
+	Project mcpProject `json:"project"`
+	Message string     `json:"message"`
+}
+
+type commentListResult struct {
+	TaskID   int          `json:"task_id"`
+	Comments []mcpComment `json:"comments"`
+}
+
+type commentResult struct {
+	TaskID  int        `json:"task_id"`
+	Comment mcpComment `json:"comment"`
+	Message string     `json:"message"`
+}
+
+// reportResult is the result of the report tools: the tasks of a report or,
+// from the report tool without a name, the available reports.
+type reportResult struct {
+	Report  string        `json:"report,omitempty" jsonschema:"description=name of the report that was run"`
+	Tasks   []mcpTask     `json:"tasks,omitempty" jsonschema:"description=tasks of the report"`
+	Shown   int           `json:"shown,omitempty" jsonschema:"description=number of tasks returned"`
+	Total   int           `json:"total,omitempty" jsonschema:"description=number of matching tasks\\, possibly more than shown"`
+	Reports []reportEntry `json:"reports,omitempty" jsonschema:"description=available reports\\, when no name was given"`
+}
+
+type agendaResult struct {
+	Days   int              `json:"days" jsonschema:"description=number of days ahead included"`
+	Groups []mcpAgendaGroup `json:"groups" jsonschema:"description=non-empty groups in order: Overdue\\, Today\\, Tomorrow\\, This week\\, Later"`
+}
+
+type mcpAgendaGroup struct {
+	Name  string    `json:"name"`
+	Tasks []mcpTask `json:"tasks"`
+}
+
+type calendarResult struct {
+	Month string           `json:"month" jsonschema:"description=YYYY-MM"`
+	Days  []mcpCalendarDay `json:"days" jsonschema:"description=days of the month that have tasks"`
+}
+
+type mcpCalendarDay struct {
+	Date  string    `json:"date" jsonschema:"description=YYYY-MM-DD"`
+	Count int       `json:"count"`
+	Tasks []mcpTask `json:"tasks"`
+}
+
+type timeResult struct {
+	Time string `json:"time" jsonschema:"description=RFC 3339 timestamp"`
+}
+
+type timeDiffResult struct {
+	Value float64 `json:"value" jsonschema:"description=difference rounded to whole units"`
+	Unit  string  `json:"unit" jsonschema:"enum=seconds,enum=minutes,enum=hours,enum=days"`
+}
+
+// timecalcResult has time for add, sub and convert, and value and unit for
+// diff.
+type timecalcResult struct {
+	Time  string   `json:"time,omitempty" jsonschema:"description=RFC 3339 timestamp (add\\, sub\\, convert)"`
+	Value *float64 `json:"value,omitempty" jsonschema:"description=difference rounded to whole units (diff)"`
+	Unit  string   `json:"unit,omitempty" jsonschema:"description=unit of value (diff)"`
+}
+
+type messageResult struct {
+	Message string `json:"message"`
+}
+
+// ---------------------------------------------------------------------
+// Result builders shared by the native tools
+// ---------------------------------------------------------------------
+
+// structuredResult returns a tool result with v, one of the result objects
+// above, as its structured content and text as its text content.
+func structuredResult(v interface{}, text string) *mcp.CallToolResult {
+	return mcp.NewToolResultStructured(v, text)
+}
+
+// taskListToolResult returns tasks, of total matching ones, rendered as
+// text.
+func taskListToolResult(tasks []api.Task, total int, text string) *mcp.CallToolResult {
+	return structuredResult(taskListResult{Tasks: mcpTasksOf(tasks), Shown: len(tasks), Total: total}, text)
+}
+
+// taskToolResult returns a task a tool changed and the message about it.
+func taskToolResult(t api.Task, message string) *mcp.CallToolResult {
+	return structuredResult(taskResult{Task: mcpTaskOf(t), Message: message}, message)
+}
+
+// timeToolResult returns a timestamp in RFC 3339 format.
+func timeToolResult(t time.Time) *mcp.CallToolResult {
+	s := t.Format(time.RFC3339)
+	return structuredResult(timeResult{Time: s}, s)
+}
+
+// messageToolResult returns a plain message.
+func messageToolResult(message string) *mcp.CallToolResult {
+	return structuredResult(messageResult{Message: message}, message)
+}
+
+// timecalcToolResult returns a timestamp of the timecalc tool.
+func timecalcToolResult(t time.Time) *mcp.CallToolResult {
+	s := t.Format(time.RFC3339)
+	return structuredResult(timecalcResult{Time: s}, s)
+}
@@ -0,0 +1,315 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/spf13/viper"
+
+	"kunja/adapter/vikunja"
+	"kunja/api"
+)
+
+// defaultMCPSessionTTL is how long the Vikunja credentials of an idle MCP
+// session are kept when mcp.session_ttl is not set.
+const defaultMCPSessionTTL = 12 * time.Hour
+
+// mcpCredentials is the Vikunja account an MCP session acts as.
+type mcpCredentials struct {
+	BaseURL  string
+	Token    string
+	Username string // empty when the token came from a header
+}
+
+// mcpSessions is the registry of the Vikunja credentials of the sessions of
+// the HTTP transport. Entries expire after ttl without a tool call.
+type mcpSessions struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]mcpSession
+}
+
+type mcpSession struct {
+	mcpCredentials
+	expires time.Time
+}
+
+// mcpSessionRegistry is set while serving over HTTP; prepareServices
+// resolves the services of a tool call from it.
+var mcpSessionRegistry *mcpSessions
+
+// newMCPSessions returns a registry with the TTL from mcp.session_ttl.
+func newMCPSessions() (*mcpSessions, error) {
+	ttl := defaultMCPSessionTTL
+	if viper.IsSet("mcp.session_ttl") {
+		ttl = viper.GetDuration("mcp.session_ttl")
+		if ttl <= 0 {
+			return nil, fmt.Errorf("invalid mcp.session_ttl %q", viper.GetString("mcp.session_ttl"))
+		}
+	}
+	return &mcpSessions{ttl: ttl, now: time.Now, entries: map[string]mcpSession{}}, nil
+}
+
+// set stores the credentials of a session, replacing earlier ones.
+func (r *mcpSessions) set(id string, creds mcpCredentials) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sweep()
+	if old, ok := r.entries[id]; ok && old.mcpCredentials != creds {
+		dropServiceCache(cacheKey(old.BaseURL, old.Token))
+	}
+	r.entries[id] = mcpSession{mcpCredentials: creds, expires: r.now().Add(r.ttl)}
+}
+
+// get returns the credentials of a session and extends its expiry.
+func (r *mcpSessions) get(id string) (mcpCredentials, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sweep()
+	s, ok := r.entries[id]
+	if !ok {
+		return mcpCredentials{}, false
+	}
+	s.expires = r.now().Add(r.ttl)
+	r.entries[id] = s
+	return s.mcpCredentials, true
+}
+
+// drop forgets the credentials of a session.
+func (r *mcpSessions) drop(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if s, ok := r.entries[id]; ok {
+		delete(r.entries, id)
+		dropServiceCache(cacheKey(s.BaseURL, s.Token))
+	}
+}
+
+// sweep removes expired sessions; r.mu must be held.
+func (r *mcpSessions) sweep() {
+	now := r.now()
+	for id, s := range r.entries {
+		if now.After(s.expires) {
+			delete(r.entries, id)
+			dropServiceCache(cacheKey(s.BaseURL, s.Token))
+			log.Printf("-- session %s expired\n", id)
+		}
+	}
+}
+
+// unregister drops the credentials of a session whose connection closed.
+func (r *mcpSessions) unregister(ctx context.Context, session server.ClientSession) {
+	r.drop(session.SessionID())
+}
+
+// mcpHeaderCredentialsKey is the context key of the credentials sent as
+// X-Vikunja-Token and X-Vikunja-Url headers.
+type mcpHeaderCredentialsKey struct{}
+
+// headerCredentials is an HTTP context function of both transports that
+// records the Vikunja credentials of the request headers, if any.
+func headerCredentials(ctx context.Context, r *http.Request) context.Context {
+	token := strings.TrimSpace(r.Header.Get("X-Vikunja-Token"))
+	if token == "" {
+		return ctx
+	}
+	creds := mcpCredentials{BaseURL: strings.TrimSpace(r.Header.Get("X-Vikunja-Url")), Token: token}
+	return context.WithValue(ctx, mcpHeaderCredentialsKey{}, creds)
+}
+
+// middleware stores credentials sent as headers in the session, so later
+// calls of the session need not repeat them, and keeps the session alive.
+func (r *mcpSessions) middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if err := r.touch(ctx); err != nil {
+			return nil, err
+		}
+		return next(ctx, req)
+	}
+}
+
+// touch stores the header credentials of a request in its session, or
+// extends the expiry of the session if the request has none.
+func (r *mcpSessions) touch(ctx context.Context) error {
+	creds, ok := ctx.Value(mcpHeaderCredentialsKey{}).(mcpCredentials)
+	id, err := mcpSessionID(ctx)
+	if !ok {
+		if err == nil {
+			r.get(id)
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if creds.BaseURL, err = allowedBaseURL(creds.BaseURL); err != nil {
+		return err
+	}
+	r.set(id, creds)
+	return nil
+}
+
+// mcpSessionIDKey is the context key of the session ID of the requests the
+// transports answer themselves, which have no ClientSession.
+type mcpSessionIDKey struct{}
+
+// withMCPSessionID returns ctx with the session ID of such a request.
+func withMCPSessionID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, mcpSessionIDKey{}, id)
+}
+
+// mcpSessionID returns the ID of the MCP session a call belongs to.
+func mcpSessionID(ctx context.Context) (string, error) {
+	if session := server.ClientSessionFromContext(ctx); session != nil && session.SessionID() != "" {
+		return session.SessionID(), nil
+	}
+	if id, ok := ctx.Value(mcpSessionIDKey{}).(string); ok && id != "" {
+		return id, nil
+	}
+	return "", fmt.Errorf("no MCP session – initialize one first")
+}
+
+// allowedBaseURL returns the Vikunja URL a session asked for, or the
+// configured baseurl if it asked for none. Sessions may only use the
+// configured baseurl and the URLs listed in mcp.allowed_baseurls, so the
+// server cannot be pointed at arbitrary hosts.
+func allowedBaseURL(base string) (string, error) {
+	configured := viper.GetString("baseurl")
+	base = strings.TrimRight(strings.TrimSpace(base), "/")
+	if base == "" {
+		if configured == "" {
+			return "", fmt.Errorf("no Vikunja URL given and no baseurl configured")
+		}
+		return configured, nil
+	}
+	if base == strings.TrimRight(configured, "/") {
+		return configured, nil
+	}
+	for _, allowed := range viper.GetStringSlice("mcp.allowed_baseurls") {
+		if base == strings.TrimRight(allowed, "/") {
+			return allowed, nil
+		}
+	}
+	return "", fmt.Errorf("Vikunja URL %s is not allowed – add it to mcp.allowed_baseurls", base)
+}
+
+// sessionServices returns the services of the Vikunja account of the
+// session of ctx, if the session has one. They use a cache of their own
+// but, unlike the configured account, no offline cache, which is stored
+// per machine rather than per account.
+func sessionServices(ctx context.Context) (Services, bool, error) {
+	if mcpSessionRegistry == nil {
+		return Services{}, false, nil
+	}
+	id, err := mcpSessionID(ctx)
+	if err != nil {
+		return Services{}, false, nil
+	}
+	creds, ok := mcpSessionRegistry.get(id)
+	if !ok {
+		return Services{}, false, nil
+	}
+
+	client := api.NewApiClient(creds.BaseURL, creds.Token)
+	model, err := urgencyModel()
+	if err != nil {
+		return Services{}, false, err
+	}
+	client.Urgency = &model
+	adapter := vikunja.New(client)
+	svc := Services{
+		Auth:       adapter,
+		Task:       adapter,
+		Project:    adapter,
+		User:       adapter,
+		Label:      adapter,
+		Bucket:     adapter,
+		Comment:    adapter,
+		Attachment: adapter,
+		Relation:   adapter,
+		Filter:     adapter,
+	}
+	return withServiceCacheFor(svc, cacheKey(creds.BaseURL, creds.Token)), true, nil
+}
+
+// mcpSessionIDs drops the credentials and resource subscriptions of
+// streamable HTTP sessions the client terminates.
+type mcpSessionIDs struct {
+	server.InsecureStatefulSessionIdManager
+	sessions      *mcpSessions
+	subscriptions *mcpSubscriptions
+}
+
+func (m *mcpSessionIDs) Terminate(id string) (bool, error) {
+	m.sessions.drop(id)
+	m.subscriptions.drop(id)
+	return m.InsecureStatefulSessionIdManager.Terminate(id)
+}
+
+type loginResult struct {
+	Username string `json:"username"`
+	BaseURL  string `json:"base_url"`
+	Message  string `json:"message"`
+}
+
+// registerSessionTools adds the login and logout tools of the HTTP
+// transport.
+func registerSessionTools(s *server.MCPServer, sessions *mcpSessions) {
+	// ---- login --------------------------------------------------------
+	loginTool := mcp.NewTool(
+		"login",
+		mcp.WithDescription("Log this MCP session in to Vikunja; later tool calls of the session act as that user. Alternatively send the X-Vikunja-Token (and X-Vikunja-Url) headers."),
+		mcp.WithString("username", mcp.Required(), mcp.Description("Vikunja username")),
+		mcp.WithString("password", mcp.Required(), mcp.Description("Vikunja password")),
+		mcp.WithString("totp_passcode", mcp.Description("TOTP passcode, if the account uses two-factor authentication")),
+		mcp.WithString("baseurl", mcp.Description("Vikunja API URL, e.g. https://vikunja.example.com/api/v1; defaults to the server's")),
+		mcp.WithOutputSchema[loginResult](),
+	)
+	s.AddTool(loginTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		argMap, _ := req.Params.Arguments.(map[string]interface{})
+		id, err := mcpSessionID(ctx)
+		if err != nil {
+			return nil, err
+		}
+		base, err := allowedBaseURL(pickArg(argMap, "baseurl"))
+		if err != nil {
+			return nil, err
+		}
+		username := pickArg(argMap, "username")
+		client := api.NewApiClient(base, "")
+		token, err := client.Login(ctx, username, pickArg(argMap, "password"), pickArg(argMap, "totp_passcode"))
+		if err != nil {
+			return nil, fmt.Errorf("login failed: %w", err)
+		}
+		sessions.set(id, mcpCredentials{BaseURL: base, Token: token, Username: username})
+		log.Printf("-- session %s logged in as %s\n", id, username)
+		message := fmt.Sprintf("Logged in as %s at %s. The login is forgotten after %s without tool calls.", username, base, sessions.ttl)
+		return structuredResult(loginResult{Username: username, BaseURL: base, Message: message}, message), nil
+	})
+	BuiltinTools = append(BuiltinTools, loginTool)
+
+	// ---- logout -------------------------------------------------------
+	logoutTool := mcp.NewTool(
+		"logout",
+		mcp.WithDescription("Forget the Vikunja login of this MCP session."),
+		mcp.WithOutputSchema[messageResult](),
+	)
+	s.AddTool(logoutTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		id, err := mcpSessionID(ctx)
+		if err != nil {
+			return nil, err
+		}
+		sessions.drop(id)
+		return messageToolResult("Logged out."), nil
+	})
+	BuiltinTools = append(BuiltinTools, logoutTool)
+}
@@ -0,0 +1,351 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"kunja/adapter/cache"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/spf13/viper"
+)
+
+// defaultMCPPollInterval is how often subscribed resources are checked for
+// changes when mcp.poll_interval is not set.
+const defaultMCPPollInterval = 30 * time.Second
+
+// stdioSessionID is the ID mcp-go gives the single session of the stdio
+// transport.
+const stdioSessionID = "stdio"
+
+// mcpSubscriptions polls the resources clients subscribed to and sends
+// notifications/resources/updated when they change. mcp-go answers
+// resources/subscribe with "method not found", so the transports hand
+// subscribe and unsubscribe requests to handle before the server sees them.
+type mcpSubscriptions struct {
+	server   *server.MCPServer
+	interval time.Duration
+
+	mu   sync.Mutex
+	subs map[string]map[string]*mcpSubscription // session ID -> URI -> subscription
+}
+
+type mcpSubscription struct {
+	stop context.CancelFunc
+}
+
+// newMCPSubscriptions returns the subscriptions of s, polled every
+// mcp.poll_interval.
+func newMCPSubscriptions(s *server.MCPServer) (*mcpSubscriptions, error) {
+	interval := defaultMCPPollInterval
+	if viper.IsSet("mcp.poll_interval") {
+		interval = viper.GetDuration("mcp.poll_interval")
+		if interval <= 0 {
+			return nil, fmt.Errorf("invalid mcp.poll_interval %q", viper.GetString("mcp.poll_interval"))
+		}
+	}
+	return &mcpSubscriptions{server: s, interval: interval, subs: map[string]map[string]*mcpSubscription{}}, nil
+}
+
+// handle answers a resources/subscribe or resources/unsubscribe request of
+// a session; it reports false for any other message.
+func (m *mcpSubscriptions) handle(ctx context.Context, session string, raw []byte) (mcp.JSONRPCMessage, bool) {
+	var req struct {
+		ID     interface{} `json:"id"`
+		Method string      `json:"method"`
+		Params struct {
+			URI string `json:"uri"`
+		} `json:"params"`
+	}
+	if json.Unmarshal(raw, &req) != nil {
+		return nil, false
+	}
+	var err error
+	switch req.Method {
+	case "resources/subscribe":
+		err = m.subscribe(withMCPSessionID(ctx, session), session, req.Params.URI)
+	case "resources/unsubscribe":
+		m.unsubscribe(session, req.Params.URI)
+	default:
+		return nil, false
+	}
+
+	id := mcp.NewRequestId(req.ID)
+	if err != nil {
+		resp := mcp.JSONRPCError{JSONRPC: mcp.JSONRPC_VERSION, ID: id}
+		resp.Error.Code = mcp.INVALID_PARAMS
+		resp.Error.Message = err.Error()
+		return resp, true
+	}
+	return mcp.JSONRPCResponse{JSONRPC: mcp.JSONRPC_VERSION, ID: id, Result: mcp.EmptyResult{}}, true
+}
+
+// subscribe starts polling uri for the session. The resource is loaded
+// once right away, so that a resource the session cannot read is refused.
+func (m *mcpSubscriptions) subscribe(ctx context.Context, session, uri string) error {
+	r, err := parseResourceURI(uri)
+	if err != nil {
+		return err
+	}
+	if mcpSessionRegistry != nil {
+		if err := mcpSessionRegistry.touch(ctx); err != nil {
+			return err
+		}
+	}
+	// The polling outlives the request but keeps its values, which select
+	// the Vikunja account of the session.
+	ctx = context.WithoutCancel(ctx)
+	version, err := m.version(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := context.WithCancel(ctx)
+	sub := &mcpSubscription{stop: stop}
+	m.mu.Lock()
+	if m.subs[session] == nil {
+		m.subs[session] = map[string]*mcpSubscription{}
+	}
+	if old, ok := m.subs[session][uri]; ok {
+		old.stop()
+	}
+	m.subs[session][uri] = sub
+	m.mu.Unlock()
+
+	log.Printf("-- session %s subscribed to %s\n", session, uri)
+	go m.poll(ctx, session, uri, r, version, sub)
+	return nil
+}
+
+// unsubscribe stops polling uri for the session.
+func (m *mcpSubscriptions) unsubscribe(session, uri string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if sub, ok := m.subs[session][uri]; ok {
+		sub.stop()
+		delete(m.subs[session], uri)
+	}
+}
+
+// remove forgets sub, unless it has been replaced in the meantime.
+func (m *mcpSubscriptions) remove(session, uri string, sub *mcpSubscription) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sub.stop()
+	if m.subs[session][uri] == sub {
+		delete(m.subs[session], uri)
+	}
+}
+
+// drop stops all subscriptions of a session.
+func (m *mcpSubscriptions) drop(session string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, sub := range m.subs[session] {
+		sub.stop()
+	}
+	delete(m.subs, session)
+}
+
+// unregister drops the subscriptions of a session whose event stream
+// closed, as there is nowhere left to send its notifications.
+func (m *mcpSubscriptions) unregister(ctx context.Context, session server.ClientSession) {
+	m.drop(session.SessionID())
+}
+
+// version loads a resource from the server and returns its fingerprint.
+// It reads past the in-memory cache, whose times to live would delay the
+// notifications, and past the offline cache, which would otherwise rewrite
+// its snapshot on every poll.
+func (m *mcpSubscriptions) version(ctx context.Context, r kunjaResource) (string, error) {
+	ctx, svc, err := prepareServices(ctx)
+	if err != nil {
+		return "", err
+	}
+	v, err := r.load(cache.Fresh(ctx), withoutOfflineCache(svc))
+	if err != nil {
+		return "", err
+	}
+	return resourceVersion(v), nil
+}
+
+// poll checks the resource every interval and notifies the session when its
+// version changed, until the subscription is stopped or the session is
+// gone. Failed checks are logged and retried.
+func (m *mcpSubscriptions) poll(ctx context.Context, session, uri string, r kunjaResource, last string, sub *mcpSubscription) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		version, err := m.version(ctx, r)
+		if err != nil && strings.Contains(err.Error(), "status code: 404") {
+			// a deleted task or project has a version too, so that the
+			// subscriber learns of the deletion
+			version, err = "deleted", nil
+		}
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("!! polling %s for session %s: %v\n", uri, session, err)
+			}
+			continue
+		}
+		if version == last {
+			continue
+		}
+		last = version
+		err = m.server.SendNotificationToSpecificClient(session, mcp.MethodNotificationResourceUpdated, map[string]any{"uri": uri})
+		if errors.Is(err, server.ErrSessionNotFound) {
+			log.Printf("-- session %s has no event stream – dropping its subscription to %s\n", session, uri)
+			m.remove(session, uri, sub)
+			return
+		}
+		if err != nil {
+			log.Printf("!! notifying session %s of %s: %v\n", session, uri, err)
+		}
+	}
+}
+
+// serveStdio serves s on stdin and stdout like server.ServeStdio, until
+// stdin is closed or SIGINT or SIGTERM, answering subscribe requests with
+// subs.
+func serveStdio(s *server.MCPServer, subs *mcpSubscriptions) error {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	out := &lockedWriter{w: os.Stdout}
+	in, pipe := io.Pipe()
+	go func() { pipe.CloseWithError(subs.filter(ctx, os.Stdin, pipe, out)) }()
+	return server.NewStdioServer(s).Listen(ctx, in, out)
+}
+
+// filter copies the messages of the stdio session from in to next, except
+// for the subscribe requests, which it answers on out itself.
+func (m *mcpSubscriptions) filter(ctx context.Context, in io.Reader, next, out io.Writer) error {
+	reader := bufio.NewReader(in)
+	for {
+		line, err := reader.ReadString('\n')
+		if len(line) > 0 {
+			if resp, ok := m.handle(ctx, stdioSessionID, []byte(line)); ok {
+				data, merr := json.Marshal(resp)
+				if merr != nil {
+					return merr
+				}
+				if _, werr := fmt.Fprintf(out, "%s\n", data); werr != nil {
+					return werr
+				}
+			} else if _, werr := io.WriteString(next, line); werr != nil {
+				return werr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// lockedWriter serialises the writes of the stdio server and of filter,
+// which answer on the same stdout.
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
+
+// mcpMaxMessageSize caps the request bodies read to find subscribe
+// requests.
+const mcpMaxMessageSize = 4 << 20
+
+// httpHandler answers the subscribe requests posted to next, one of the
+// HTTP transports: sessionID returns the valid session of a request or "",
+// and reply sends the response the way the transport does.
+func (m *mcpSubscriptions) httpHandler(next http.Handler, sessionID func(*http.Request) string, reply func(http.ResponseWriter, string, mcp.JSONRPCMessage)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			next.ServeHTTP(w, r)
+			return
+		}
+		id := sessionID(r)
+		if id == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		body, err := io.ReadAll(io.LimitReader(r.Body, mcpMaxMessageSize))
+		r.Body.Close()
+		if err != nil {
+			http.Error(w, "Bad Request", http.StatusBadRequest)
+			return
+		}
+		ctx := headerCredentials(r.Context(), r)
+		if resp, ok := m.handle(ctx, id, body); ok {
+			reply(w, id, resp)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		next.ServeHTTP(w, r)
+	})
+}
+
+// streamableHandler answers the subscribe requests of streamable HTTP
+// sessions in the body of the response, like the transport answers other
+// requests.
+func (m *mcpSubscriptions) streamableHandler(next http.Handler, ids server.SessionIdManager) http.Handler {
+	sessionID := func(r *http.Request) string {
+		id := r.Header.Get("Mcp-Session-Id")
+		if id == "" {
+			return ""
+		}
+		if terminated, err := ids.Validate(id); err != nil || terminated {
+			return "" // let the transport refuse it
+		}
+		return id
+	}
+	reply := func(w http.ResponseWriter, id string, resp mcp.JSONRPCMessage) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Mcp-Session-Id", id)
+		json.NewEncoder(w).Encode(resp)
+	}
+	return m.httpHandler(next, sessionID, reply)
+}
+
+// sseHandler answers the subscribe requests of SSE sessions on their event
+// stream, like the transport answers other requests.
+func (m *mcpSubscriptions) sseHandler(next http.Handler, sse *server.SSEServer) http.Handler {
+	sessionID := func(r *http.Request) string {
+		return r.URL.Query().Get("sessionId")
+	}
+	reply := func(w http.ResponseWriter, id string, resp mcp.JSONRPCMessage) {
+		if err := sse.SendEventToSession(id, resp); err != nil {
+			m.drop(id) // no such session
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}
+	return m.httpHandler(next, sessionID, reply)
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+
+	"kunja/internal/render"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+var (
+	Output         string
+	OutputTemplate string
+)
+
+func init() {
+	rootCmd.PersistentFlags().StringVarP(&Output, "output", "o", "", "output format: table|json|yaml|csv|tsv|template")
+	rootCmd.PersistentFlags().StringVar(&OutputTemplate, "template", "", "Go text/template (or a file containing one) for --output template; fields use the JSON names, e.g. '{{range .}}{{.id}} {{.title}}{{\"\\n\"}}{{end}}'")
+}
+
+// outputOptions returns the render options selected by --output and
+// --template. Without --output, --verbose keeps selecting JSON as it always
+// has.
+func outputOptions() (render.Options, error) {
+	format := Output
+	if format == "" && OutputTemplate == "" && Verbose {
+		format = string(render.FormatJSON)
+	}
+	return render.NewOptions(format, OutputTemplate)
+}
+
+// mcpOutputOptions reads the output (and legacy verbose) argument of a
+// native MCP tool. Templates are a CLI feature and are not offered here.
+func mcpOutputOptions(argMap map[string]interface{}) (render.Options, error) {
+	format, _ := argMap["output"].(string)
+	if verbose, _ := argMap["verbose"].(bool); verbose && format == "" {
+		format = string(render.FormatJSON)
+	}
+	f, err := render.ParseFormat(format)
+	if err != nil {
+		return render.Options{}, err
+	}
+	if f == render.FormatTemplate {
+		return render.Options{}, fmt.Errorf("output template is not supported over MCP")
+	}
+	return render.Options{Format: f}, nil
+}
+
+// withOutputArgs adds the output and verbose arguments shared by the native
+// MCP list tools. They select the format of the text content only; the
+// structured content is the same in every format.
+func withOutputArgs() []mcp.ToolOption {
+	return []mcp.ToolOption{
+		mcp.WithString("output", mcp.Description("format of the text result: table (default), json, yaml, csv or tsv; the structured result is always JSON"), mcp.Enum("table", "json", "yaml", "csv", "tsv")),
+		mcp.WithBoolean("verbose", mcp.Description("raw JSON text (same as output=json)")),
+	}
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"kunja/api"
 	"strconv"
@@ -26,10 +27,11 @@ var projectNewCmd = &cobra.Command{
 }
 
 var projectDelCmd = &cobra.Command{
-	Use:         "project-del [ID]",
-	Short:       "Delete a project",
-	Annotations: map[string]string{"skip_mcp": "true"},
-	Args:        cobra.ExactArgs(1),
+	Use:               "project-del [PROJECT_ID]",
+	Short:             "Delete a project",
+	Annotations:       map[string]string{"skip_mcp": "true"},
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeArgs(completeProjects, false),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		id, _ := strconv.Atoi(args[0])
 		svc := getServices(cmd)
@@ -45,3 +47,22 @@ func init() {
 	rootCmd.AddCommand(projectNewCmd)
 	rootCmd.AddCommand(projectDelCmd)
 }
+
+// resolveProject looks up a project by numeric ID or, failing that, by its
+// title or identifier (case-insensitive).
+func resolveProject(ctx context.Context, svc Services, ref string) (api.Project, error) {
+	ref = strings.TrimSpace(ref)
+	if id, err := strconv.Atoi(ref); err == nil {
+		return svc.Project.GetProject(ctx, id)
+	}
+	projects, err := svc.Project.GetAllProjects(ctx)
+	if err != nil {
+		return api.Project{}, err
+	}
+	for _, p := range projects {
+		if strings.EqualFold(p.Title, ref) || (p.Identifier != "" && strings.EqualFold(p.Identifier, ref)) {
+			return p, nil
+		}
+	}
+	return api.Project{}, fmt.Errorf("project not found: %q", ref)
+}
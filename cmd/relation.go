@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"kunja/api"
+	"kunja/internal/core"
+
+	"github.com/spf13/cobra"
+)
+
+var relateCmd = &cobra.Command{
+	Use:   "relate [TASK_ID] [KIND] [OTHER_ID]",
+	Short: "Relate two tasks (subtask, parent, blocking, blocked, related, duplicateof, …)",
+	Long:  `Create a relation of KIND from TASK_ID to OTHER_ID, e.g. "kunja relate 12 blocked 10" marks task 12 as blocked by task 10. Vikunja adds the inverse relation automatically.`,
+	Args:  cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		taskID, kind, otherID, err := parseRelationArgs(args)
+		if err != nil {
+			return err
+		}
+		if _, err := getServices(cmd).Relation.CreateTaskRelation(cmd.Context(), taskID, otherID, kind); err != nil {
+			fmt.Println("Error creating relation:", err)
+			return err
+		}
+		fmt.Printf("Relation added: %s.\n", kind.Describe(taskID, otherID))
+		return nil
+	},
+}
+
+var unrelateCmd = &cobra.Command{
+	Use:   "unrelate [TASK_ID] [KIND] [OTHER_ID]",
+	Short: "Remove a relation between two tasks",
+	Args:  cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		taskID, kind, otherID, err := parseRelationArgs(args)
+		if err != nil {
+			return err
+		}
+		if _, err := getServices(cmd).Relation.DeleteTaskRelation(cmd.Context(), taskID, otherID, kind); err != nil {
+			fmt.Println("Error removing relation:", err)
+			return err
+		}
+		fmt.Printf("Relation %s between task %d and task %d removed.\n", kind, taskID, otherID)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(relateCmd)
+	rootCmd.AddCommand(unrelateCmd)
+}
+
+func parseRelationArgs(args []string) (int, api.RelationKind, int, error) {
+	taskID, err := strconv.Atoi(args[0])
+	if err != nil {
+		return 0, "", 0, fmt.Errorf("invalid task ID: %q", args[0])
+	}
+	kind, err := core.ParseRelationKind(args[1])
+	if err != nil {
+		return 0, "", 0, err
+	}
+	otherID, err := strconv.Atoi(args[2])
+	if err != nil {
+		return 0, "", 0, fmt.Errorf("invalid task ID: %q", args[2])
+	}
+	if taskID == otherID {
+		return 0, "", 0, fmt.Errorf("a task cannot be related to itself")
+	}
+	return taskID, kind, otherID, nil
+}
+
+// renderRelationGraph draws the relations of a task as a tree. Related tasks
+// are expanded up to depth levels by fetching them; tasks already shown are
+// not expanded again, so cycles (blocking ⇄ blocked) terminate.
+func renderRelationGraph(ctx context.Context, svc Services, task api.Task, depth int) string {
+	if len(task.RelatedTasks) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "#%d %s\n", task.ID, task.Title)
+	seen := map[int]bool{task.ID: true}
+	writeRelations(ctx, svc, &b, task, "", depth, seen)
+	return b.String()
+}
+
+func writeRelations(ctx context.Context, svc Services, b *strings.Builder, task api.Task, indent string, depth int, seen map[int]bool) {
+	var kinds []api.RelationKind
+	for _, k := range core.RelationKinds {
+		if len(task.RelatedTasks[k]) > 0 {
+			kinds = append(kinds, k)
+		}
+	}
+	for ki, k := range kinds {
+		lastKind := ki == len(kinds)-1
+		branch, childIndent := "├─ ", indent+"│  "
+		if lastKind {
+			branch, childIndent = "└─ ", indent+"   "
+		}
+		fmt.Fprintf(b, "%s%s%s\n", indent, branch, k.Label())
+
+		others := task.RelatedTasks[k]
+		for oi, other := range others {
+			lastOther := oi == len(others)-1
+			obranch, oindent := "├─ ", childIndent+"│  "
+			if lastOther {
+				obranch, oindent = "└─ ", childIndent+"   "
+			}
+			check := " "
+			if other.Done {
+				check = "x"
+			}
+			fmt.Fprintf(b, "%s%s[%s] #%d %s\n", childIndent, obranch, check, other.ID, other.Title)
+
+			if depth <= 1 || seen[other.ID] {
+				continue
+			}
+			seen[other.ID] = true
+			full, err := svc.Task.GetTask(ctx, other.ID)
+			if err != nil {
+				continue
+			}
+			writeRelations(ctx, svc, b, full, oindent, depth-1, seen)
+		}
+	}
+}
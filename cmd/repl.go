@@ -1,64 +1,139 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/chzyer/readline"
+	"github.com/kballard/go-shellquote"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
 )
 
-func listFiles(path string) func(string) []string {
-	return func(line string) []string {
-		names := make([]string, 0)
-		files, _ := os.ReadDir(path)
-		for _, f := range files {
-			names = append(names, f.Name())
-		}
-		return names
+// replSession reads commands at the "> " prompt and runs them against the
+// root command. It keeps one readline instance, and with it the history and
+// the completion cache, for its whole lifetime.
+type replSession struct {
+	root  *cobra.Command
+	rl    *readline.Instance
+	cache *completionCache
+}
+
+// newREPLSession prepares a session for root with history in ConfigDir.
+func newREPLSession(root *cobra.Command) (*replSession, error) {
+	s := &replSession{root: root, cache: newCompletionCache()}
+	cfg := &readline.Config{
+		Prompt:          "> ",
+		HistoryFile:     filepath.Join(ConfigDir, "cmd.history"),
+		InterruptPrompt: "^C",
+		EOFPrompt:       "exit",
+	}
+	// Complete only for a person at a terminal; piped input may contain tabs.
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		cfg.AutoComplete = &replCompleter{root: root, cache: s.cache}
 	}
+	rl, err := readline.NewEx(cfg)
+	if err != nil {
+		return nil, err
+	}
+	s.rl = rl
+	return s, nil
 }
 
-var completer = readline.NewPrefixCompleter(
-	readline.PcItemDynamic(listFiles("./")),
-)
+// Close releases the terminal.
+func (s *replSession) Close() error {
+	return s.rl.Close()
+}
 
-func GetUserInput() string {
-	historyFile := filepath.Join(ConfigDir, "cmd.history")
-	rl, err := readline.NewEx(&readline.Config{
-		Prompt:       "> ",
-		AutoComplete: completer,
-		HistoryFile:  historyFile,
-	})
+// Run reads and executes lines until EOF (Ctrl-D). Ctrl-C discards the
+// current line; empty lines are ignored.
+func (s *replSession) Run() error {
+	for {
+		line, err := s.rl.Readline()
+		if errors.Is(err, readline.ErrInterrupt) {
+			continue
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		s.exec(line)
+	}
+}
+
+// exec runs one input line. The line is split like a shell would, so quoted
+// titles stay one argument, and all flags are reset first so that values
+// from the previous command do not leak into this one. Errors of the
+// command itself have already been reported by cobra.
+func (s *replSession) exec(line string) {
+	args, err := shellquote.Split(line)
 	if err != nil {
-		panic(err)
+		fmt.Fprintln(os.Stderr, "Error: cannot parse input:", err)
+		return
 	}
-	defer rl.Close()
+	resetFlags(s.root)
+	s.root.SetArgs(args)
+	s.root.Execute()
+	s.cache.invalidate() // the command may have changed tasks, projects or labels
+	flushServiceCache()  // and the next one should see changes made elsewhere
+}
 
-	line, err := rl.Readline()
-	if err != nil { // io.EOF
-		return ""
+// resetFlags restores every flag of c and its subcommands to its default
+// and clears its changed state.
+func resetFlags(c *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if !f.Changed {
+			return
+		}
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			def := strings.Trim(f.DefValue, "[]")
+			var items []string
+			if def != "" {
+				items = strings.Split(def, ",")
+			}
+			sv.Replace(items)
+		} else {
+			f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	c.Flags().VisitAll(reset)
+	c.PersistentFlags().VisitAll(reset)
+	for _, sub := range c.Commands() {
+		resetFlags(sub)
 	}
-	return line
 }
 
-// this enters the main loop of asking for user input and executing commands
+// Execute runs the command given on the command line and then enters the
+// REPL for further commands.
 func Execute() {
 	// execute once on startup for commandline params etc.
-	if err := rootCmd.Execute(); err != nil {
+	cmd, err := rootCmd.ExecuteC()
+	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
-	// enter continuous loop for further commands
-	for {
-		input := GetUserInput()
-		args := strings.Fields(input)
-		rootCmd.SetArgs(args)
-		if err := rootCmd.Execute(); err != nil {
-			fmt.Fprintln(os.Stderr, err)
-		}
+	if isCompletionCmd(cmd) {
+		return // the shell reads our output; there is nobody to prompt
+	}
+	session, err := newREPLSession(rootCmd)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer session.Close()
+	if err := session.Run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
 	}
 }
 
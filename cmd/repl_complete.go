@@ -0,0 +1,387 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"kunja/internal/core"
+	"kunja/internal/render"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// completionCache keeps the values fetched for completion (task IDs,
+// projects, labels, users) until the next command runs.
+type completionCache struct {
+	mu     sync.Mutex
+	values map[string][]string
+}
+
+func newCompletionCache() *completionCache {
+	return &completionCache{values: map[string][]string{}}
+}
+
+// get returns the cached values of kind, calling load on a miss. Failed
+// loads are not cached so that the next Tab tries again.
+func (c *completionCache) get(kind string, load func() ([]string, error)) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if v, ok := c.values[kind]; ok {
+		return v
+	}
+	v, err := load()
+	if err != nil {
+		return nil
+	}
+	c.values[kind] = v
+	return v
+}
+
+// invalidate drops all cached values, including the on-disk cache of the
+// shell completion.
+func (c *completionCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values = map[string][]string{}
+	clearCompletionCache()
+}
+
+// replCompleter completes REPL input: subcommands, flags, flag values and
+// the positional arguments named in a command's Use line, such as TASK_ID
+// or LABEL. It implements readline.AutoCompleter.
+type replCompleter struct {
+	root  *cobra.Command
+	cache *completionCache
+}
+
+// Do returns the completions of the word before pos as suffixes to insert,
+// and the length of that word.
+func (c *replCompleter) Do(line []rune, pos int) ([][]rune, int) {
+	words, cur, quote := splitPartial(string(line[:pos]))
+	var out [][]rune
+	for _, cand := range c.candidates(words, cur) {
+		if strings.HasPrefix(cand, cur) && cand != cur {
+			out = append(out, []rune(quoteSuffix(cand[len(cur):], quote)))
+		}
+	}
+	return out, len([]rune(cur))
+}
+
+// candidates returns the possible values of the word cur that follows the
+// complete words.
+func (c *replCompleter) candidates(words []string, cur string) []string {
+	cmd, positional, pending := c.resolve(words)
+
+	// value of a flag: "--project <Tab>" or "--project=<Tab>"
+	if pending != nil {
+		return c.flagValues(pending, cur, "")
+	}
+	if strings.HasPrefix(cur, "--") && strings.Contains(cur, "=") {
+		name, _, _ := strings.Cut(cur[2:], "=")
+		if f := lookupFlag(cmd, name); f != nil {
+			return c.flagValues(f, cur, "--"+name+"=")
+		}
+		return nil
+	}
+	if strings.HasPrefix(cur, "-") {
+		return flagNames(cmd)
+	}
+
+	var cands []string
+	if len(positional) == 0 {
+		for _, sub := range cmd.Commands() {
+			if sub.IsAvailableCommand() {
+				cands = append(cands, sub.Name())
+			}
+		}
+	}
+	if cmd.ValidArgsFunction != nil {
+		// the same values the shell completion offers
+		items, _ := cmd.ValidArgsFunction(cmd, positional, cur)
+		return append(cands, stripDescriptions(items)...)
+	}
+	if ph := placeholder(cmd, len(positional)); ph != "" {
+		cands = append(cands, c.argValues(cmd, ph, cur)...)
+	}
+	return cands
+}
+
+// resolve finds the command named by words, the positional arguments given
+// to it so far, and the flag whose value comes next, if any.
+func (c *replCompleter) resolve(words []string) (*cobra.Command, []string, *pflag.Flag) {
+	cmd := c.root
+	var positional []string
+	var pending *pflag.Flag
+	for _, w := range words {
+		switch {
+		case pending != nil:
+			pending = nil
+		case w == "--":
+			// everything after is positional; nothing to do here
+		case strings.HasPrefix(w, "-"):
+			if strings.Contains(w, "=") {
+				continue
+			}
+			name := strings.TrimLeft(w, "-")
+			var f *pflag.Flag
+			if strings.HasPrefix(w, "--") {
+				f = lookupFlag(cmd, name)
+			} else if len(name) == 1 {
+				f = lookupShorthand(cmd, name)
+			}
+			if f != nil && f.NoOptDefVal == "" {
+				pending = f
+			}
+		default:
+			if len(positional) == 0 {
+				if sub := findSubcommand(cmd, w); sub != nil {
+					cmd = sub
+					continue
+				}
+			}
+			positional = append(positional, w)
+		}
+	}
+	return cmd, positional, pending
+}
+
+// findSubcommand returns the subcommand of cmd called name or one of its
+// aliases.
+func findSubcommand(cmd *cobra.Command, name string) *cobra.Command {
+	for _, sub := range cmd.Commands() {
+		if sub.Name() == name || sub.HasAlias(name) {
+			return sub
+		}
+	}
+	return nil
+}
+
+func lookupFlag(cmd *cobra.Command, name string) *pflag.Flag {
+	if f := cmd.Flags().Lookup(name); f != nil {
+		return f
+	}
+	return cmd.InheritedFlags().Lookup(name)
+}
+
+func lookupShorthand(cmd *cobra.Command, name string) *pflag.Flag {
+	if f := cmd.Flags().ShorthandLookup(name); f != nil {
+		return f
+	}
+	return cmd.InheritedFlags().ShorthandLookup(name)
+}
+
+// flagNames returns "--name" for every visible flag of cmd.
+func flagNames(cmd *cobra.Command) []string {
+	var names []string
+	add := func(f *pflag.Flag) {
+		if !f.Hidden {
+			names = append(names, "--"+f.Name)
+		}
+	}
+	cmd.Flags().VisitAll(add)
+	cmd.InheritedFlags().VisitAll(add)
+	sort.Strings(names)
+	return names
+}
+
+// placeholder returns the name of the n-th positional argument of cmd as
+// written in its Use line, e.g. "TASK_ID" for "done [TASK_ID...]". A
+// trailing "..." makes the last argument repeat.
+func placeholder(cmd *cobra.Command, n int) string {
+	fields := strings.Fields(cmd.Use)
+	if len(fields) < 2 {
+		return ""
+	}
+	args := fields[1:]
+	if n >= len(args) {
+		if !strings.HasSuffix(args[len(args)-1], "...]") {
+			return ""
+		}
+		n = len(args) - 1
+	}
+	return strings.TrimSuffix(strings.Trim(args[n], "[]"), "...")
+}
+
+// argValues returns the candidates for the positional argument ph of cmd.
+func (c *replCompleter) argValues(cmd *cobra.Command, ph, cur string) []string {
+	switch {
+	case ph == "TASK_ID" || ph == "OTHER_ID":
+		return c.values(completeTasks)
+	case ph == "PROJECT_ID":
+		return c.values(completeProjects)
+	case ph == "LABEL":
+		return c.values(completeLabels)
+	case ph == "FILTER":
+		return c.values(completeFilters)
+	case ph == "KIND":
+		kinds := make([]string, len(core.RelationKinds))
+		for i, k := range core.RelationKinds {
+			kinds[i] = string(k)
+		}
+		return kinds
+	case ph == "FILE":
+		return fileNames(cur)
+	case ph == "NAME" && cmd.Name() == "report":
+		defs, err := reports()
+		if err != nil {
+			return nil
+		}
+		return sortedReportNames(defs)
+	}
+	return nil
+}
+
+// flagValues returns the candidates for the value of f, each prefixed with
+// prefix. Values of slice flags are completed after the last comma.
+func (c *replCompleter) flagValues(f *pflag.Flag, cur, prefix string) []string {
+	var vals []string
+	switch f.Name {
+	case "project":
+		if f.Value.Type() == "int" {
+			vals = c.values(completeProjects)
+		} else {
+			vals = c.values(completeProjectTitles)
+		}
+	case "label":
+		vals = c.values(completeLabels)
+	case "assignee":
+		vals = c.values(completeUsers)
+	case "output":
+		for _, format := range render.Formats {
+			vals = append(vals, string(format))
+		}
+	case "sort":
+		vals = append(vals, "urgency")
+		for name := range taskSortFields {
+			vals = append(vals, name)
+		}
+		sort.Strings(vals)
+	case "columns":
+		vals = taskColumnNames
+	case "dest", "template":
+		vals = fileNames(strings.TrimPrefix(cur, prefix))
+	}
+
+	if strings.Contains(f.Value.Type(), "Slice") {
+		if i := strings.LastIndex(cur, ","); i >= len(prefix) {
+			prefix = cur[:i+1]
+		}
+	}
+	out := make([]string, len(vals))
+	for i, v := range vals {
+		out[i] = prefix + v
+	}
+	return out
+}
+
+// values returns the cached completion values of kind, fetching them from
+// the services on first use.
+func (c *replCompleter) values(kind string) []string {
+	return c.cache.get(kind, func() ([]string, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+		defer cancel()
+		ctx, svc, err := prepareServices(ctx)
+		if err != nil {
+			return nil, err
+		}
+		items, err := completionItems(ctx, svc, kind)
+		if err != nil {
+			return nil, err
+		}
+		return stripDescriptions(items), nil
+	})
+}
+
+// stripDescriptions drops the "\tdescription" part of completion items;
+// readline shows plain values only.
+func stripDescriptions(items []string) []string {
+	vals := make([]string, len(items))
+	for i, item := range items {
+		vals[i], _, _ = strings.Cut(item, "\t")
+	}
+	return vals
+}
+
+// fileNames returns the entries of the directory part of cur, with a
+// trailing slash on directories.
+func fileNames(cur string) []string {
+	dir, _ := filepath.Split(cur)
+	entries, err := os.ReadDir(filepath.Join(".", dir))
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, e := range entries {
+		name := dir + e.Name()
+		if e.IsDir() {
+			name += "/"
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// splitPartial splits s like a shell would, returning the complete words,
+// the unfinished last word and the quote that is still open, if any.
+func splitPartial(s string) (words []string, cur string, quote rune) {
+	var b strings.Builder
+	inWord, escaped := false, false
+	for _, r := range s {
+		switch {
+		case escaped:
+			b.WriteRune(r)
+			escaped = false
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else if r == '\\' && quote == '"' {
+				escaped = true
+			} else {
+				b.WriteRune(r)
+			}
+		case r == '\\':
+			escaped, inWord = true, true
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, b.String())
+				b.Reset()
+				inWord = false
+			}
+		default:
+			b.WriteRune(r)
+			inWord = true
+		}
+	}
+	return words, b.String(), quote
+}
+
+// quoteSuffix escapes the completed rest of a word for the REPL's shell
+// syntax and ends the word, closing the open quote if there is one.
+// Directories are left open so their contents can be completed next.
+func quoteSuffix(s string, quote rune) string {
+	end := " "
+	if strings.HasSuffix(s, "/") {
+		end = ""
+	}
+	if quote != 0 {
+		if end == "" {
+			return s
+		}
+		return s + string(quote) + end
+	}
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(" \t'\"\\$`&|;<>()*?!#", r) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String() + end
+}
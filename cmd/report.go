@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"kunja/internal/render"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// reportDef is a named task view: a filter together with how its tasks are
+// sorted and shown. Reports come from the "reports" section of the config
+// file, on top of defaultReports.
+type reportDef struct {
+	Description string   `mapstructure:"description" json:"description,omitempty"`
+	Filter      string   `mapstructure:"filter" json:"filter,omitempty"`             // filter expression, see package filter
+	SavedFilter string   `mapstructure:"saved_filter" json:"saved_filter,omitempty"` // Vikunja saved filter (ID or title)
+	All         bool     `mapstructure:"all" json:"all,omitempty"`
+	HideBlocked bool     `mapstructure:"hide_blocked" json:"hide_blocked,omitempty"`
+	Sort        string   `mapstructure:"sort" json:"sort,omitempty"`
+	Columns     []string `mapstructure:"columns" json:"columns,omitempty"`
+	Limit       int      `mapstructure:"limit" json:"limit,omitempty"`
+	Output      string   `mapstructure:"output" json:"output,omitempty"`
+	Template    string   `mapstructure:"template" json:"template,omitempty"`
+}
+
+// defaultReports are available without any configuration. A report of the
+// same name in the config file replaces them.
+var defaultReports = map[string]reportDef{
+	"standup": {
+		Description: "Tasks finished since yesterday and open tasks due within the next two days",
+		Filter:      "done_at >= yesterday || (done = false && due < in 3 days)",
+		Columns:     []string{"id", "title", "done", "due", "project"},
+	},
+	"overdue": {
+		Description: "Open tasks past their due date, oldest first",
+		Filter:      "due < now",
+		Sort:        "due",
+		Columns:     []string{"id", "title", "due", "priority", "project"},
+	},
+}
+
+// reports returns the default reports merged with the configured ones.
+func reports() (map[string]reportDef, error) {
+	configured := map[string]reportDef{}
+	if err := viper.UnmarshalKey("reports", &configured); err != nil {
+		return nil, fmt.Errorf("invalid reports config: %w", err)
+	}
+	all := make(map[string]reportDef, len(defaultReports)+len(configured))
+	for name, r := range defaultReports {
+		all[name] = r
+	}
+	for name, r := range configured {
+		all[strings.ToLower(name)] = r
+	}
+	return all, nil
+}
+
+// sortedReportNames returns the names of defs in alphabetical order.
+func sortedReportNames(defs map[string]reportDef) []string {
+	names := make([]string, 0, len(defs))
+	for name := range defs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookupReport finds a report by name. Names that are no report fall back to
+// the Vikunja saved filter with that ID or title, showing all of its tasks
+// like the web interface does.
+func lookupReport(name string) (reportDef, error) {
+	defs, err := reports()
+	if err != nil {
+		return reportDef{}, err
+	}
+	if r, ok := defs[strings.ToLower(name)]; ok {
+		return r, nil
+	}
+	return reportDef{SavedFilter: name, All: true}, nil
+}
+
+// taskFilter returns the list query of the report.
+func (r reportDef) taskFilter() taskFilter {
+	limit := r.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	return taskFilter{
+		Expr:        r.Filter,
+		SavedFilter: r.SavedFilter,
+		ShowAll:     r.All,
+		HideBlocked: r.HideBlocked,
+		Sort:        r.Sort,
+		Columns:     r.Columns,
+		Limit:       limit,
+	}
+}
+
+// buildReport runs the named report. out replaces the report's own output
+// settings when it selects a format.
+func buildReport(ctx context.Context, svc Services, name string, out render.Options) (string, error) {
+	f, out, err := reportQuery(name, out)
+	if err != nil {
+		return "", err
+	}
+	return buildTaskList(ctx, svc, out, f)
+}
+
+// reportQuery returns the list query of the named report and the output
+// settings to render it with: out when it selects a format, otherwise the
+// report's own.
+func reportQuery(name string, out render.Options) (taskFilter, render.Options, error) {
+	r, err := lookupReport(name)
+	if err != nil {
+		return taskFilter{}, out, err
+	}
+	if out.Format == "" {
+		if out, err = render.NewOptions(r.Output, r.Template); err != nil {
+			return taskFilter{}, out, fmt.Errorf("report %s: %w", name, err)
+		}
+	}
+	return r.taskFilter(), out, nil
+}
+
+// reportEntry is a report in the list of reports.
+type reportEntry struct {
+	Name        string    `json:"name"`
+	Source      string    `json:"source" jsonschema:"description=config\\, default or saved filter"`
+	Description string    `json:"description,omitempty"`
+	Report      reportDef `json:"report"`
+}
+
+// buildReportList renders the available reports followed by the saved
+// filters, which can be run as reports too.
+func buildReportList(ctx context.Context, svc Services, out render.Options) (string, error) {
+	entries, err := reportEntries(ctx, svc)
+	if err != nil {
+		return "", err
+	}
+	return renderReportList(entries, out)
+}
+
+// reportEntries returns the available reports followed by the saved
+// filters.
+func reportEntries(ctx context.Context, svc Services) ([]reportEntry, error) {
+	defs, err := reports()
+	if err != nil {
+		return nil, err
+	}
+	entries := []reportEntry{}
+	for _, name := range sortedReportNames(defs) {
+		source := "config"
+		if !viper.IsSet("reports." + name) {
+			source = "default"
+		}
+		entries = append(entries, reportEntry{Name: name, Source: source, Description: defs[name].Description, Report: defs[name]})
+	}
+	filters, err := svc.Filter.GetSavedFilters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range filters {
+		entries = append(entries, reportEntry{
+			Name:        f.Title,
+			Source:      "saved filter",
+			Description: f.Description,
+			Report:      reportDef{SavedFilter: f.Title, All: true},
+		})
+	}
+	return entries, nil
+}
+
+// renderReportList renders report entries in the format selected by out.
+func renderReportList(entries []reportEntry, out render.Options) (string, error) {
+	table := render.Table{Header: []string{"Name", "Source", "Description"}}
+	for _, e := range entries {
+		table.AddRow(e.Name, e.Source, e.Description)
+	}
+	return out.String(entries, table)
+}
+
+var reportCmd = &cobra.Command{
+	Use:   "report [NAME]",
+	Short: "Run a named report, or list the reports (arg NAME)",
+	Long: `Run a named task view. Reports are defined in the "reports" section of
+config.yaml; standup and overdue are built in. Any Vikunja saved filter can
+also be run by its title or ID. Without NAME the available reports are listed.
+
+  reports:
+    mine:
+      description: My open work by due date
+      filter: assignee = alice && priority >= 2
+      sort: due
+      columns: [id, title, due, priority, labels]
+      limit: 20
+      output: table
+
+  kunja report standup
+  kunja report mine -o json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		svc := getServices(cmd)
+		opts, err := outputOptions()
+		if err != nil {
+			return err
+		}
+		var out string
+		if len(args) == 0 {
+			out, err = buildReportList(cmd.Context(), svc, opts)
+		} else {
+			out, err = buildReport(cmd.Context(), svc, args[0], opts)
+		}
+		if err != nil {
+			fmt.Println("Error running report:", err)
+			return err
+		}
+		fmt.Print(out)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+}
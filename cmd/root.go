@@ -2,26 +2,33 @@ package cmd
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"kunja/adapter/vikunja"
 	"kunja/api" // Added for api package
+	"kunja/internal/render"
 	"kunja/internal/service"
 	"os"
 	"os/exec"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 type Services struct {
-	Auth    service.AuthService
-	Task    service.TaskService
-	Project service.ProjectService
-	User    service.UserService
+	Auth       service.AuthService
+	Task       service.TaskService
+	Project    service.ProjectService
+	User       service.UserService
+	Label      service.LabelService
+	Bucket     service.BucketService
+	Comment    service.CommentService
+	Attachment service.AttachmentService
+	Relation   service.RelationService
+	Filter     service.FilterService
 }
 
 type ctxKey int
@@ -46,8 +53,20 @@ var rootCmd = &cobra.Command{
 	Short: "A CLI client for the Vikunja task management API",
 	Long:  `A CLI client for the Vikunja task management API. It allows you to interact with the Vikunja API from the command line.`,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
-		// Skip authentication check when running the `login` command
-		if cmd.Name() == "login" {
+		// Skip authentication check when running the `login` command or
+		// generating completions
+		if cmd.Name() == "login" || isCompletionCmd(cmd) {
+			return
+		}
+		// The MCP server over HTTP resolves the account per session.
+		if cmd.Name() == "mcp" && mcpTransport == "http" {
+			return
+		}
+		if memory, err := useMemoryBackend(); err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		} else if memory {
+			cmd.SetContext(context.WithValue(cmd.Context(), servicesKey, memoryServices()))
 			return
 		}
 		token := viper.GetString("token")
@@ -58,54 +77,56 @@ var rootCmd = &cobra.Command{
 
 		client := api.NewApiClient(viper.GetString("baseUrl"), token)
 		client.SetCredentials(viper.GetString("username"), viper.GetString("password"))
+		if model, err := urgencyModel(); err != nil {
+			fmt.Println("Warning:", err)
+		} else {
+			client.Urgency = &model
+		}
 		adapter := vikunja.New(client)
 
 		services := Services{
-			Auth:    adapter,
-			Task:    adapter,
-			Project: adapter,
-			User:    adapter,
+			Auth:       adapter,
+			Task:       adapter,
+			Project:    adapter,
+			User:       adapter,
+			Label:      adapter,
+			Bucket:     adapter,
+			Comment:    adapter,
+			Attachment: adapter,
+			Relation:   adapter,
+			Filter:     adapter,
 		}
+		services = withOfflineCache(withServiceCache(services))
 
 		ctx := context.WithValue(cmd.Context(), servicesKey, services)
 		cmd.SetContext(ctx)
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		svc := getServices(cmd)
-		// Ask the API to return only open tasks unless --all is set.
-		params := api.GetAllTasksParams{PerPage: 100}
-		if !ShowAll {
-			params.FilterBy = "done"
-			params.FilterValue = "false"
-			params.FilterComparator = "equals"
-		}
-		allTasks, err := fetchTasks(cmd.Context(), svc, params, 100)
+		out, err := outputOptions()
 		if err != nil {
 			return err
 		}
-		if Verbose {
-			formattedTasks, _ := json.MarshalIndent(allTasks, "", "  ")
-			fmt.Println(string(formattedTasks))
-		}
-
-		// API already filtered when --all is not set.
-		// tasks := allTasks
-
-		// Sort tasks by urgency in descending order, then by ID in descending order
-		out, err := buildTaskList(cmd.Context(), svc, Verbose, ShowAll)
+		f := taskFilterFromFlags(cmd.Flags())
+		f.Expr = strings.Join(args, " ")
+		text, err := buildTaskList(cmd.Context(), svc, out, f)
 		if err != nil {
 			return err
 		}
-		fmt.Print(out)
+		fmt.Print(text)
 		return nil
 	},
 }
 
- // fetchTasks retrieves tasks across multiple pages until it has either
- // collected `limit` tasks or there are no more pages.  Vikunja currently
- // caps per_page at 50, so we request that maximum and loop.
+// maxPerPage is the most tasks Vikunja returns per page, whatever per_page
+// asks for.
+const maxPerPage = 50
+
+// fetchTasks retrieves tasks across multiple pages until it has either
+// collected `limit` tasks or there are no more pages.  Vikunja currently
+// caps per_page at 50, so we request that maximum and loop.
 func fetchTasks(ctx context.Context, svc Services, base api.GetAllTasksParams, limit int) ([]api.Task, error) {
-	const perPage = 50
+	const perPage = maxPerPage
 	base.PerPage = perPage
 
 	var all []api.Task
@@ -128,54 +149,64 @@ func fetchTasks(ctx context.Context, svc Services, base api.GetAllTasksParams, l
 	return all, nil
 }
 
-func buildTaskList(ctx context.Context, svc Services, verbose, showAll bool) (string, error) {
-	// When --all is not set, ask Vikunja to return only the open tasks.
-	params := api.GetAllTasksParams{PerPage: 100}
-	if !showAll {
-		params.FilterBy = "done"
-		params.FilterValue = "false"
-		params.FilterComparator = "equals"
-	}
-
-	allTasks, err := fetchTasks(ctx, svc, params, 100)
+// buildTaskList returns the tasks selected by f, sorted by urgency unless
+// f asks for an API sort order, rendered in the format selected by out.
+func buildTaskList(ctx context.Context, svc Services, out render.Options, f taskFilter) (string, error) {
+	tasks, total, err := queryTaskList(ctx, svc, f)
 	if err != nil {
 		return "", err
 	}
+	return renderTaskList(tasks, total, out, f.Columns)
+}
 
-	// pretty-print and return raw JSON when verbose
-	if verbose {
-		if pretty, err := json.MarshalIndent(allTasks, "", "  "); err == nil {
-			return string(pretty) + "\n", nil
-		}
+// queryTaskList returns the tasks selected by f, sorted by urgency unless f
+// asks for an API sort order, and the total number of matching tasks.
+func queryTaskList(ctx context.Context, svc Services, f taskFilter) ([]api.Task, int, error) {
+	tasks, total, err := fetchFilteredTasks(ctx, svc, f)
+	if err != nil {
+		return nil, 0, err
+	}
+	if _, _, apiSorted := f.apiSort(); !apiSorted {
+		sortByUrgency(tasks)
 	}
+	return tasks, total, nil
+}
 
-	// The API already returned the correct set (open or all).
-	tasks := allTasks
+// renderTaskList renders tasks with the given table columns in the format
+// selected by out; tables start with the count <shown>/<total>.
+func renderTaskList(tasks []api.Task, total int, out render.Options, columns []string) (string, error) {
+	table, err := taskTable(tasks, columns)
+	if err != nil {
+		return "", err
+	}
+	text, err := out.String(tasks, table)
+	if err != nil {
+		return "", err
+	}
+	if out.Tabular() {
+		// print result count: <shown>/<total>
+		text = fmt.Sprintf("%d/%d\n", len(tasks), total) + text
+	}
+	return text, nil
+}
 
-	// sort by urgency desc, then ID desc
+// sortByUrgency sorts tasks by urgency, highest first, then by ID, newest
+// first.
+func sortByUrgency(tasks []api.Task) {
 	sort.Slice(tasks, func(i, j int) bool {
 		if tasks[i].Urgency == tasks[j].Urgency {
 			return tasks[i].ID > tasks[j].ID
 		}
 		return tasks[i].Urgency > tasks[j].Urgency
 	})
+}
 
-	var b strings.Builder
-	// print result count: <shown>/<total>
-	fmt.Fprintf(&b, "%d/%d\n", len(tasks), api.GetLastTotal())
-	for _, task := range tasks {
-		fmt.Fprintf(&b, "%d:  %s (Urgency: %.3f)\n", task.ID, task.Title, task.Urgency)
-		if task.Description != "" {
-			fmt.Fprintf(&b, "Description: %s\n", task.Description)
-		}
-		if !task.DueDate.IsZero() {
-			fmt.Fprintf(&b, "Due Date: %s\n", task.DueDate.Format("2006-01-02"))
-		}
-		if !task.DoneAt.IsZero() {
-			fmt.Fprintf(&b, "Done At: %s\n", task.DoneAt.Format("2006-01-02 15:04:05"))
-		}
+// formatDate formats t with layout, or returns "" for Vikunja's zero date.
+func formatDate(t time.Time, layout string) string {
+	if t.IsZero() {
+		return ""
 	}
-	return b.String(), nil
+	return t.Format(layout)
 }
 
 func init() {
@@ -185,6 +216,8 @@ func init() {
 	rootCmd.PersistentFlags().StringVarP(&Password, "password", "p", "", "password for the API (can also be set with KUNJA_PASSWORD environment variable)")
 	rootCmd.PersistentFlags().StringVarP(&BaseUrl, "baseurl", "b", "", "base URL for the API (can also be set with KUNJA_BASEURL environment variable)")
 	rootCmd.PersistentFlags().BoolVarP(&ShowAll, "all", "a", false, "show all tasks")
+	addTaskFilterFlags(rootCmd.Flags())
+	registerTaskFilterCompletions(rootCmd)
 	viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
 	viper.BindPFlag("username", rootCmd.PersistentFlags().Lookup("username"))
 	viper.BindPFlag("password", rootCmd.PersistentFlags().Lookup("password"))
@@ -195,9 +228,10 @@ func init() {
 
 // projectUsersCmd represents the project-users command
 var projectUsersCmd = &cobra.Command{
-	Use:   "project-users [PROJECT_ID]",
-	Short: "List users a project is shared with (arg PROJECT_ID)",
-	Args:  cobra.ExactArgs(1),
+	Use:               "project-users [PROJECT_ID]",
+	Short:             "List users a project is shared with (arg PROJECT_ID)",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeArgs(completeProjects, false),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		projectID, err := strconv.Atoi(args[0])
 		if err != nil {
@@ -205,23 +239,16 @@ var projectUsersCmd = &cobra.Command{
 			return fmt.Errorf("project ID must be a number")
 		}
 
-		svc := getServices(cmd)
-		project, err := svc.Project.GetProject(cmd.Context(), projectID)
+		opts, err := outputOptions()
 		if err != nil {
-			fmt.Printf("Error retrieving project: %s\n", err)
 			return err
 		}
-		fmt.Printf("Owner: ID: %d, Username: %s\n", project.Owner.ID, project.Owner.Username)
-
-		users, err := svc.Project.GetProjectUsers(cmd.Context(), projectID)
+		out, err := buildProjectUserList(cmd.Context(), getServices(cmd), projectID, opts)
 		if err != nil {
-			fmt.Printf("Error retrieving project users: %s\n", err)
+			fmt.Println("Error retrieving project users:", err)
 			return err
 		}
-
-		for _, user := range users {
-			fmt.Printf("User: ID: %d, Username: %s, Right: %d\n", user.ID, user.Username, user.Right)
-		}
+		fmt.Print(out)
 		return nil
 	},
 }
@@ -230,6 +257,27 @@ func init() {
 	rootCmd.AddCommand(projectUsersCmd)
 }
 func EditStringInEditor(initialContent string) (string, error) {
+	content, err := editInEditor(initialContent)
+	if err != nil {
+		return "", err
+	}
+
+	// Remove comments from the content
+	lines := strings.Split(content, "\n")
+	var filteredLines []string
+	for _, line := range lines {
+		if !strings.HasPrefix(strings.TrimSpace(line), "#") {
+			filteredLines = append(filteredLines, line)
+		}
+	}
+	filteredContent := strings.Join(filteredLines, "\n")
+
+	return filteredContent, nil
+}
+
+// editInEditor opens initialContent in $EDITOR and returns the saved text
+// unchanged.
+func editInEditor(initialContent string) (string, error) {
 	// Create a temporary file
 	file, err := os.CreateTemp("", "example")
 	if err != nil {
@@ -261,16 +309,5 @@ func EditStringInEditor(initialContent string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-
-	// Remove comments from the content
-	lines := strings.Split(string(content), "\n")
-	var filteredLines []string
-	for _, line := range lines {
-		if !strings.HasPrefix(strings.TrimSpace(line), "#") {
-			filteredLines = append(filteredLines, line)
-		}
-	}
-	filteredContent := strings.Join(filteredLines, "\n")
-
-	return filteredContent, nil
+	return string(content), nil
 }
@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"kunja/api"
+	"kunja/internal/core/filter"
+	"kunja/internal/render"
+
+	"github.com/spf13/cobra"
+)
+
+var filterCmd = &cobra.Command{
+	Use:   "filter",
+	Short: "Manage Vikunja saved filters",
+	Long: `List, create, edit and delete the saved filters stored in Vikunja. Saved filters
+can be referenced by ID or by (case-insensitive) title, and run with
+'kunja report TITLE' or from a report's saved_filter setting.
+
+Queries are written in kunja's filter language (see 'kunja list --help') and
+translated to Vikunja's syntax; only fields the server can evaluate may be
+used. Pass --raw to store a query in Vikunja's syntax as is.`,
+}
+
+var filterListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved filters",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		svc := getServices(cmd)
+		opts, err := outputOptions()
+		if err != nil {
+			return err
+		}
+		out, err := buildSavedFilterList(cmd.Context(), svc, opts)
+		if err != nil {
+			fmt.Println("Error retrieving saved filters:", err)
+			return err
+		}
+		fmt.Print(out)
+		return nil
+	},
+}
+
+var filterNewCmd = &cobra.Command{
+	Use:   "new [TITLE]",
+	Short: "Create a saved filter (arg TITLE, flag --query)",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		query, _ := cmd.Flags().GetString("query")
+		raw, _ := cmd.Flags().GetBool("raw")
+		desc, _ := cmd.Flags().GetString("description")
+		favorite, _ := cmd.Flags().GetBool("favorite")
+		if strings.TrimSpace(query) == "" {
+			return fmt.Errorf("--query is required")
+		}
+
+		svc := getServices(cmd)
+		compiled, err := savedFilterQuery(cmd.Context(), svc, query, raw)
+		if err != nil {
+			return err
+		}
+		f, err := svc.Filter.CreateSavedFilter(cmd.Context(), api.SavedFilter{
+			Title:       strings.Join(args, " "),
+			Description: desc,
+			Filters:     api.SavedFilterQuery{Filter: compiled},
+			IsFavorite:  favorite,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Saved filter created: %d – %s\n", f.ID, f.Title)
+		return nil
+	},
+}
+
+var filterEditCmd = &cobra.Command{
+	Use:   "edit [FILTER]",
+	Short: "Edit a saved filter's title, query, description or favorite flag",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		flags := cmd.Flags()
+		if !flags.Changed("title") && !flags.Changed("query") && !flags.Changed("description") && !flags.Changed("favorite") {
+			return fmt.Errorf("at least one of --title/--query/--description/--favorite is required")
+		}
+
+		svc := getServices(cmd)
+		f, err := resolveSavedFilter(cmd.Context(), svc, args[0])
+		if err != nil {
+			return err
+		}
+		if flags.Changed("title") {
+			f.Title, _ = flags.GetString("title")
+		}
+		if flags.Changed("description") {
+			f.Description, _ = flags.GetString("description")
+		}
+		if flags.Changed("favorite") {
+			f.IsFavorite, _ = flags.GetBool("favorite")
+		}
+		if flags.Changed("query") {
+			query, _ := flags.GetString("query")
+			raw, _ := flags.GetBool("raw")
+			if f.Filters.Filter, err = savedFilterQuery(cmd.Context(), svc, query, raw); err != nil {
+				return err
+			}
+		}
+		if _, err := svc.Filter.UpdateSavedFilter(cmd.Context(), f.ID, f); err != nil {
+			return err
+		}
+		fmt.Println("Saved filter updated successfully")
+		return nil
+	},
+}
+
+var filterDeleteCmd = &cobra.Command{
+	Use:         "delete [FILTER]",
+	Short:       "Delete a saved filter",
+	Annotations: map[string]string{"skip_mcp": "true"},
+	Args:        cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		svc := getServices(cmd)
+		f, err := resolveSavedFilter(cmd.Context(), svc, args[0])
+		if err != nil {
+			return err
+		}
+		if _, err := svc.Filter.DeleteSavedFilter(cmd.Context(), f.ID); err != nil {
+			return err
+		}
+		fmt.Println("Saved filter deleted.")
+		return nil
+	},
+}
+
+func init() {
+	for _, c := range []*cobra.Command{filterNewCmd, filterEditCmd} {
+		c.Flags().String("query", "", "filter expression, e.g. 'priority >= 3 && due < in 7 days'")
+		c.Flags().Bool("raw", false, "store --query verbatim in Vikunja's filter syntax")
+		c.Flags().String("description", "", "filter description")
+		c.Flags().Bool("favorite", false, "mark the filter as favorite")
+	}
+	filterEditCmd.Flags().String("title", "", "new title")
+
+	filterCmd.AddCommand(filterListCmd, filterNewCmd, filterEditCmd, filterDeleteCmd)
+	rootCmd.AddCommand(filterCmd)
+}
+
+// buildSavedFilterList renders the saved filters of the current user.
+func buildSavedFilterList(ctx context.Context, svc Services, out render.Options) (string, error) {
+	filters, err := svc.Filter.GetSavedFilters(ctx)
+	if err != nil {
+		return "", err
+	}
+	table := render.Table{Header: []string{"ID", "Title", "Query", "Description"}}
+	for _, f := range filters {
+		table.AddRow(f.ID, f.Title, f.Filters.Filter, f.Description)
+	}
+	return out.String(filters, table)
+}
+
+// resolveSavedFilter finds a saved filter by ID or (case-insensitive) title.
+func resolveSavedFilter(ctx context.Context, svc Services, ref string) (api.SavedFilter, error) {
+	ref = strings.TrimSpace(ref)
+	if id, err := strconv.Atoi(ref); err == nil {
+		return svc.Filter.GetSavedFilter(ctx, id)
+	}
+	filters, err := svc.Filter.GetSavedFilters(ctx)
+	if err != nil {
+		return api.SavedFilter{}, err
+	}
+	for _, f := range filters {
+		if strings.EqualFold(f.Title, ref) {
+			return f, nil
+		}
+	}
+	return api.SavedFilter{}, fmt.Errorf("saved filter not found: %q", ref)
+}
+
+// savedFilterQuery translates a filter expression into the Vikunja query
+// stored in a saved filter. Saved filters are evaluated by the server, so
+// expressions with local-only parts are rejected.
+func savedFilterQuery(ctx context.Context, svc Services, src string, raw bool) (string, error) {
+	if raw {
+		return strings.TrimSpace(src), nil
+	}
+	expr, err := filter.Parse(src)
+	if err != nil {
+		return "", filterError(err)
+	}
+	query, rest, err := filter.Compile(expr, filterEnv(ctx, svc))
+	if err != nil {
+		return "", filterError(err)
+	}
+	if rest != nil {
+		return "", fmt.Errorf("%s cannot be evaluated by Vikunja and would be lost in a saved filter", rest)
+	}
+	return query, nil
+}
@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"kunja/adapter/offline"
+	"kunja/internal/render"
+	"kunja/internal/service"
+	"kunja/internal/store"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Offline forces the offline cache even when the server is reachable.
+var Offline bool
+
+// offlineStores holds the offline store of each account, opened once per
+// process so that all Services built for it, e.g. one per MCP tool call,
+// share the store and its lock.
+var offlineStores struct {
+	sync.Mutex
+	stores map[string]*store.Store
+}
+
+// offlineDir returns the offline cache directory of the configured
+// account, ConfigDir/offline/<accountHash>, so changes queued for one
+// account are never replayed to another.
+func offlineDir() string {
+	return filepath.Join(ConfigDir, "offline", accountHash())
+}
+
+// openOfflineStore returns the store in dir, opening it on first use.
+func openOfflineStore(dir string) (*store.Store, error) {
+	offlineStores.Lock()
+	defer offlineStores.Unlock()
+	if st, ok := offlineStores.stores[dir]; ok {
+		return st, nil
+	}
+	st, err := store.Open(dir)
+	if err != nil {
+		return nil, err
+	}
+	if offlineStores.stores == nil {
+		offlineStores.stores = map[string]*store.Store{}
+	}
+	offlineStores.stores[dir] = st
+	return st, nil
+}
+
+// withOfflineCache routes the task, project, label and user services
+// through the offline cache of the configured account (see offlineDir),
+// which mirrors what they return and takes over when the server cannot be
+// reached. If the cache cannot be opened svc is returned unchanged.
+func withOfflineCache(svc Services) Services {
+	if ConfigDir == "" {
+		return svc
+	}
+	remote := struct {
+		service.TaskService
+		service.ProjectService
+		service.LabelService
+		service.UserService
+	}{svc.Task, svc.Project, svc.Label, svc.User}
+	st, err := openOfflineStore(offlineDir())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Warning: offline cache unavailable:", err)
+		return svc
+	}
+	cache := offline.New(remote, st, viper.GetBool("offline"))
+	if model, err := urgencyModel(); err == nil {
+		cache.Urgency = &model
+	}
+	cache.Notify = func(msg string) {
+		fmt.Fprintln(os.Stderr, "Note:", msg)
+	}
+	svc.Task = cache
+	svc.Project = cache
+	svc.Label = cache
+	svc.User = cache
+	return svc
+}
+
+// withoutOfflineCache returns svc with the offline cache, if any, taken
+// out again, for reads that should neither fall back to it nor be mirrored
+// into it.
+func withoutOfflineCache(svc Services) Services {
+	cache, ok := svc.Task.(*offline.Adapter)
+	if !ok {
+		return svc
+	}
+	remote := cache.Remote()
+	svc.Task = remote
+	svc.Project = remote
+	svc.Label = remote
+	svc.User = remote
+	return svc
+}
+
+// offlineAdapter returns the offline cache behind svc.
+func offlineAdapter(svc Services) (*offline.Adapter, error) {
+	cache, ok := svc.Task.(*offline.Adapter)
+	if !ok {
+		return nil, fmt.Errorf("the offline cache is not available")
+	}
+	return cache, nil
+}
+
+// buildSync replays the changes queued offline, refreshes the cache and
+// returns a report in the format selected by out.
+func buildSync(ctx context.Context, svc Services, opts offline.SyncOptions, out render.Options) (string, error) {
+	if opts.Force && opts.Discard {
+		return "", fmt.Errorf("--force and --discard are mutually exclusive")
+	}
+	cache, err := offlineAdapter(svc)
+	if err != nil {
+		return "", err
+	}
+	res, err := cache.Sync(ctx, opts)
+	if err != nil {
+		return "", err
+	}
+
+	if !out.Tabular() {
+		table := render.Table{Header: []string{"Status", "Op", "ID", "Local ID", "Title", "Reason"}}
+		for _, group := range []struct {
+			status  string
+			changes []offline.Change
+		}{{"applied", res.Applied}, {"conflict", res.Conflicts}, {"discarded", res.Discarded}, {"failed", res.Failed}} {
+			for _, c := range group.changes {
+				table.AddRow(group.status, c.Op, c.TaskID, c.LocalID, c.Title, c.Reason)
+			}
+		}
+		return out.String(res, table)
+	}
+
+	var b strings.Builder
+	section := func(title string, changes []offline.Change) {
+		if len(changes) == 0 {
+			return
+		}
+		b.WriteString(title + "\n")
+		table := render.Table{}
+		for _, c := range changes {
+			id := fmt.Sprintf("#%d", c.TaskID)
+			if c.LocalID != 0 {
+				id += fmt.Sprintf(" (was #%d)", c.LocalID)
+			}
+			table.AddRow("", c.Op, id, c.Title, c.Reason)
+		}
+		out.Render(&b, nil, table)
+	}
+	section(fmt.Sprintf("Applied %d queued change(s):", len(res.Applied)), res.Applied)
+	section("Conflicts, still queued (use --force to overwrite the server or --discard to drop them):", res.Conflicts)
+	section("Discarded:", res.Discarded)
+	section("Failed, still queued:", res.Failed)
+	fmt.Fprintf(&b, "Cached %d projects, %d tasks, %d labels and %d users.\n", res.Projects, res.Tasks, res.Labels, res.Users)
+	return b.String(), nil
+}
+
+// buildPending returns the changes queued offline.
+func buildPending(svc Services, out render.Options) (string, error) {
+	cache, err := offlineAdapter(svc)
+	if err != nil {
+		return "", err
+	}
+	queue, err := cache.Pending()
+	if err != nil {
+		return "", err
+	}
+	if out.Tabular() && len(queue) == 0 {
+		return "No queued changes.\n", nil
+	}
+	table := render.Table{Header: []string{"Op", "ID", "Title", "Queued"}}
+	for _, m := range queue {
+		table.AddRow(m.Op, m.TaskID, m.Task.Title, m.Queued.Local().Format(time.DateTime))
+	}
+	if queue == nil {
+		queue = []store.Mutation{}
+	}
+	return out.String(queue, table)
+}
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Replay changes made offline and refresh the offline cache",
+	Long: `kunja mirrors projects, tasks, labels and users into a cache in the config
+directory. When the server cannot be reached (or with --offline) commands
+read from the cache, and creating, editing, completing or deleting tasks is
+recorded there and queued. Tasks created offline get negative IDs until
+they are synced; put -- before them on the command line, e.g.
+"kunja done -- -1".
+
+sync replays the queue in order and then refreshes the whole cache. A
+queued edit or delete of a task that was changed on the server since is a
+conflict: it stays queued unless --force (apply it anyway) or --discard
+(drop it) is given. Use --pending to see the queue without syncing.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out, err := outputOptions()
+		if err != nil {
+			return err
+		}
+		var text string
+		if pending, _ := cmd.Flags().GetBool("pending"); pending {
+			text, err = buildPending(getServices(cmd), out)
+		} else {
+			var opts offline.SyncOptions
+			opts.Force, _ = cmd.Flags().GetBool("force")
+			opts.Discard, _ = cmd.Flags().GetBool("discard")
+			text, err = buildSync(cmd.Context(), getServices(cmd), opts, out)
+		}
+		if err != nil {
+			fmt.Println("Error syncing:", err)
+			return err
+		}
+		fmt.Print(text)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&Offline, "offline", false, "work from the offline cache without contacting the server")
+	viper.BindPFlag("offline", rootCmd.PersistentFlags().Lookup("offline"))
+	syncCmd.Flags().Bool("force", false, "apply conflicting changes, overwriting the server's version")
+	syncCmd.Flags().Bool("discard", false, "drop conflicting changes, keeping the server's version")
+	syncCmd.Flags().Bool("pending", false, "list the queued changes without syncing")
+	rootCmd.AddCommand(syncCmd)
+}
@@ -1,15 +1,17 @@
 package cmd
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"sort"
+	"kunja/adapter/cache"
 	"kunja/api"
+	"kunja/internal/core"
+	"kunja/internal/core/dateparse"
+	"kunja/internal/render"
 	"strconv"
 	"strings"
-	"text/tabwriter"
 	"time"
 
 	"github.com/AlecAivazis/survey/v2" // Import the survey library
@@ -20,15 +22,23 @@ import (
 var newCmd = &cobra.Command{
 	Use:   "new",
 	Short: "Create a new task in a project (--project) with optional --due",
-	Long:  `Create a new task using the provided title and due date.`,
+	Long: `Create a new task using the provided title and due date.
+
+The title understands Vikunja's quick-add magic: *label adds a label (created
+if missing), +project selects the project, !1-!5 sets the priority, @user
+assigns a user and phrases like "today", "tomorrow 9am", "next monday 17:00",
+"in 3 days", "december 24" or a weekday ending the title ("friday 5pm") set the
+due date. Quote values containing spaces (*"needs review").
+Use --no-magic to keep the title verbatim.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		title := strings.Join(args, " ")
 		svc := getServices(cmd)
 		due, _ := cmd.Flags().GetString("due")
-
+		noMagic, _ := cmd.Flags().GetBool("no-magic")
 
 		projectId := viper.GetInt("project")
-		if projectId == 0 {
+		hasMagicProject := !noMagic && core.ParseQuickAdd(title, time.Now()).Project != ""
+		if projectId == 0 && !hasMagicProject {
 			projects, err := svc.Project.GetAllProjects(cmd.Context())
 			if err != nil {
 				fmt.Println("Error retrieving projects:", err)
@@ -51,7 +61,7 @@ var newCmd = &cobra.Command{
 			projectId, _ = strconv.Atoi(strings.TrimSpace(parts[0]))
 		}
 
-		msg, err := createTaskSimple(cmd.Context(), svc, title, due, projectId)
+		_, msg, err := createTaskSimple(cmd.Context(), svc, title, due, projectId, !noMagic)
 		if err != nil {
 			fmt.Println("Error creating task:", err)
 			return err
@@ -62,10 +72,11 @@ var newCmd = &cobra.Command{
 }
 
 var doneCmd = &cobra.Command{
-	Use:   "done [TASK_ID...]",
-	Short: "Toggle the done status of one or more tasks",
-	Long:  `Toggle the done status of the specified task IDs. If no IDs are provided an interactive multi-select is shown.`,
-	Args:  cobra.ArbitraryArgs,
+	Use:               "done [TASK_ID...]",
+	Short:             "Toggle the done status of one or more tasks",
+	Long:              `Toggle the done status of the specified task IDs. If no IDs are provided an interactive multi-select is shown.`,
+	Args:              cobra.ArbitraryArgs,
+	ValidArgsFunction: completeArgs(completeTasks, true),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		svc := getServices(cmd)
 		ctx := cmd.Context()
@@ -138,7 +149,7 @@ var doneCmd = &cobra.Command{
 		var toggled []string
 		var failed []string
 		for _, id := range ids {
-			msg, err := toggleTaskDone(ctx, svc, id)
+			_, msg, err := toggleTaskDone(ctx, svc, id)
 			if err != nil {
 				failed = append(failed, fmt.Sprintf("%d (%v)", id, err))
 			} else {
@@ -157,11 +168,12 @@ var doneCmd = &cobra.Command{
 }
 
 var deleteCmd = &cobra.Command{
-	Use:         "delete",
-	Short:       "Delete a task",
-	Annotations: map[string]string{"skip_mcp": "true"},
-	Long:        `Delete a task permanently using the provided task ID.`,
-	Args:        cobra.ExactArgs(1),
+	Use:               "delete [TASK_ID]",
+	Short:             "Delete a task",
+	Annotations:       map[string]string{"skip_mcp": "true"},
+	Long:              `Delete a task permanently using the provided task ID.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeArgs(completeTasks, false),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		taskID, err := strconv.Atoi(args[0])
 		if err != nil {
@@ -179,10 +191,11 @@ var deleteCmd = &cobra.Command{
 }
 
 var showCmd = &cobra.Command{
-	Use:   "show",
-	Short: "Show details of a task (arg TASK_ID)",
-	Long:  `Show the details of a task in raw indented JSON format.`,
-	Args:  cobra.ExactArgs(1),
+	Use:               "show [TASK_ID]",
+	Short:             "Show details of a task (arg TASK_ID)",
+	Long:              `Show the details of a task in raw indented JSON format, followed by its relation graph when the task has related tasks.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeArgs(completeTasks, false),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		taskID, _ := strconv.Atoi(args[0])
 		svc := getServices(cmd)
@@ -197,6 +210,10 @@ var showCmd = &cobra.Command{
 			return err
 		}
 		fmt.Println(string(jsonTask))
+		depth, _ := cmd.Flags().GetInt("depth")
+		if graph := renderRelationGraph(cmd.Context(), svc, task, depth); graph != "" {
+			fmt.Print("\nRelations:\n" + graph)
+		}
 		return nil
 	},
 }
@@ -207,7 +224,11 @@ var projectsCmd = &cobra.Command{
 	Long:  `List all the projects from the API.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		svc := getServices(cmd)
-		out, err := buildProjectList(cmd.Context(), svc, Verbose)
+		opts, err := outputOptions()
+		if err != nil {
+			return err
+		}
+		out, err := buildProjectList(cmd.Context(), svc, opts)
 		if err != nil {
 			fmt.Println("Error retrieving projects:", err)
 			return err
@@ -218,30 +239,38 @@ var projectsCmd = &cobra.Command{
 }
 
 func init() {
+	showCmd.Flags().Int("depth", 1, "How many levels of related tasks to expand in the relation graph")
 	rootCmd.AddCommand(showCmd)
 	rootCmd.AddCommand(projectsCmd)
 }
 
 var assignedCmd = &cobra.Command{
-	Use:   "assigned",
-	Short: "List assignees for a task (arg TASK_ID)",
-	Long:  `List all the assignees assigned to a task using the provided task ID.`,
-	Args:  cobra.ExactArgs(1),
+	Use:               "assigned [TASK_ID]",
+	Short:             "List assignees for a task (arg TASK_ID)",
+	Long:              `List all the assignees assigned to a task using the provided task ID.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeArgs(completeTasks, false),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		taskID, err := strconv.Atoi(args[0])
 		if err != nil {
 			fmt.Println("Error converting task ID:", err)
 			return err
 		}
+		opts, err := outputOptions()
+		if err != nil {
+			return err
+		}
 		svc := getServices(cmd)
 		assignees, err := svc.Task.GetTaskAssignees(cmd.Context(), taskID)
 		if err != nil {
 			fmt.Println("Error getting assignees for task:", err)
 			return err
 		}
-		for _, assignee := range assignees {
-			fmt.Printf("ID: %d, Username: %s, Name: %s\n", assignee.ID, assignee.Username, assignee.Name)
+		out, err := buildUserList(assignees, opts)
+		if err != nil {
+			return err
 		}
+		fmt.Print(out)
 		return nil
 	},
 }
@@ -251,22 +280,30 @@ var usersCmd = &cobra.Command{
 	Short: "List all users",
 	Long:  `List all the users from the API.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		opts, err := outputOptions()
+		if err != nil {
+			return err
+		}
 		svc := getServices(cmd)
 		users, err := svc.User.GetAllUsers(cmd.Context())
 		if err != nil {
 			fmt.Println("Error retrieving users:", err)
 			return err
 		}
-		for _, user := range users {
-			fmt.Printf("ID: %d, Username: %s, Name: %s\n", user.ID, user.Username, user.Name)
+		out, err := buildUserList(users, opts)
+		if err != nil {
+			return err
 		}
+		fmt.Print(out)
 		return nil
 	},
 }
 
 func init() {
-	newCmd.Flags().StringP("due", "d", "", "Due date for the task")
+	newCmd.Flags().StringP("due", "d", "", "Due date for the task (YYYY-MM-DD or e.g. \"tomorrow 9am\", \"in 2 weeks\")")
 	newCmd.Flags().IntP("project", "P", 0, "Project ID to create the task in")
+	newCmd.RegisterFlagCompletionFunc("project", completeFlag(completeProjects))
+	newCmd.Flags().Bool("no-magic", false, "Do not parse quick-add magic (*label +project !priority @user dates) in the title")
 
 	// Make project flag required for MCP (but optional for CLI).
 	f := newCmd.Flags().Lookup("project")
@@ -279,8 +316,9 @@ func init() {
 	// Flags for non-interactive updates in edit command
 	editCmd.Flags().StringP("title", "t", "", "New title for the task")
 	editCmd.Flags().String("description", "", "New description for the task")
-	editCmd.Flags().String("due", "", "New due date (YYYY-MM-DD)")
+	editCmd.Flags().String("due", "", "New due date (YYYY-MM-DD or e.g. \"tomorrow 9am\", \"next friday\")")
 	editCmd.Flags().IntP("project", "P", 0, "New project ID")
+	editCmd.RegisterFlagCompletionFunc("project", completeFlag(completeProjects))
 
 	rootCmd.AddCommand(editCmd) // Add the edit command to the root command
 	rootCmd.AddCommand(assignedCmd)
@@ -289,10 +327,11 @@ func init() {
 
 // editCmd represents the edit command
 var editCmd = &cobra.Command{
-	Use:   "edit",
-	Short: "Edit a task (interactive or via flags)",
-	Long:  `Edit a task's title, description, or due date. Provide --title, --description, or --due for non-interactive updates; otherwise an interactive editor is opened.`,
-	Args:  cobra.ExactArgs(1),
+	Use:               "edit [TASK_ID]",
+	Short:             "Edit a task (interactive or via flags)",
+	Long:              `Edit a task's title, description, or due date. Provide --title, --description, or --due for non-interactive updates; otherwise an interactive editor is opened.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeArgs(completeTasks, false),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		taskID, _ := strconv.Atoi(args[0])
 		svc := getServices(cmd)
@@ -304,15 +343,16 @@ var editCmd = &cobra.Command{
 		newProject, _ := cmd.Flags().GetInt("project")
 		scriptable := newTitle != "" || newDesc != "" || newDue != "" || newProject != 0
 
-		// fetch current task (needed for both paths)
-		task, err := svc.Task.GetTask(cmd.Context(), taskID)
+		// fetch current task (needed for both paths), bypassing the cache
+		// since it is saved back
+		task, err := svc.Task.GetTask(cache.Fresh(cmd.Context()), taskID)
 		if err != nil {
 			fmt.Println("Error getting task:", err)
 			return err
 		}
 
 		if scriptable {
-			msg, err := editTaskSimple(cmd.Context(), svc, taskID, newTitle, newDesc, newDue, newProject)
+			_, msg, err := editTaskSimple(cmd.Context(), svc, taskID, newTitle, newDesc, newDue, newProject)
 			if err != nil {
 				fmt.Println("Error updating task:", err)
 				return err
@@ -350,11 +390,11 @@ var editCmd = &cobra.Command{
 				}
 				task.Description = editedDescription
 			case "Due Date":
-				prompt := &survey.Input{Message: "Enter new due date (YYYY-MM-DD):"}
+				prompt := &survey.Input{Message: "Enter new due date (e.g. 2006-01-02, tomorrow 9am, next friday):"}
 				var newDueDate string
 				survey.AskOne(prompt, &newDueDate)
 				if newDueDate != "" {
-					parsedDate, err := time.Parse("2006-01-02", newDueDate)
+					parsedDate, err := dateparse.Parse(newDueDate)
 					if err != nil {
 						fmt.Println("Error parsing due date:", err)
 						continue
@@ -376,96 +416,226 @@ var editCmd = &cobra.Command{
 }
 
 // createTaskSimple contains the non-interactive business logic for creating a
-// task.  It is reused by both the CLI and the MCP “new” tool.
-func createTaskSimple(ctx context.Context, svc Services, title, dueStr string, projectID int) (string, error) {
+// task.  It is reused by both the CLI and the MCP “new” tool and returns the
+// created task and a message for the user.  With magic set,
+// the title is parsed for quick-add syntax (see core.ParseQuickAdd); a
+// +project in the title takes precedence over projectID and an explicit
+// dueStr over a date phrase.
+func createTaskSimple(ctx context.Context, svc Services, title, dueStr string, projectID int, magic bool) (api.Task, string, error) {
+	q := core.QuickAdd{Title: title}
+	if magic {
+		q = core.ParseQuickAdd(title, time.Now())
+	}
+	if strings.TrimSpace(q.Title) == "" {
+		return api.Task{}, "", fmt.Errorf("task title must not be empty")
+	}
+
+	if q.Project != "" {
+		p, err := resolveProject(ctx, svc, q.Project)
+		if err != nil {
+			return api.Task{}, "", err
+		}
+		projectID = p.ID
+	}
 	if projectID == 0 {
-		return "", fmt.Errorf("project ID must be provided (flag --project)")
+		return api.Task{}, "", fmt.Errorf("project ID must be provided (flag --project)")
 	}
 
-	var dueDate time.Time
-	var err error
+	dueDate := q.DueDate
 	if dueStr != "" {
-		dueDate, err = time.Parse("2006-01-02", dueStr)
+		var err error
+		dueDate, err = dateparse.Parse(dueStr)
 		if err != nil {
-			return "", fmt.Errorf("invalid due date: %w", err)
+			return api.Task{}, "", fmt.Errorf("invalid due date: %w", err)
 		}
 	}
 
+	// Resolve names before creating anything so a typo does not leave a
+	// half-configured task behind.
+	var assignees []api.User
+	if len(q.Assignees) > 0 {
+		users, err := svc.User.GetAllUsers(ctx)
+		if err != nil {
+			return api.Task{}, "", err
+		}
+		for _, name := range q.Assignees {
+			u, ok := findUser(users, name)
+			if !ok {
+				return api.Task{}, "", fmt.Errorf("user not found: %q", name)
+			}
+			assignees = append(assignees, u)
+		}
+	}
+	labels, err := ensureLabels(ctx, svc, q.Labels)
+	if err != nil {
+		return api.Task{}, "", err
+	}
+
 	task := api.Task{
-		Title:     title,
+		Title:     q.Title,
+		Priority:  q.Priority,
 		DueDate:   dueDate,
 		ProjectID: projectID,
 	}
 
 	created, err := svc.Task.CreateTask(ctx, projectID, task)
 	if err != nil {
-		return "", err
+		return api.Task{}, "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Task created successfully: %d", created.ID)
+	for _, l := range labels {
+		if _, err := svc.Label.AddLabelToTask(ctx, created.ID, l.ID); err != nil {
+			fmt.Fprintf(&b, "\nFailed to add label %q: %v", l.Title, err)
+		} else {
+			created.Labels = append(created.Labels, l)
+		}
+	}
+	for _, u := range assignees {
+		if _, err := svc.Task.AssignUserToTask(ctx, created.ID, u.ID); err != nil {
+			fmt.Fprintf(&b, "\nFailed to assign %s: %v", u.Username, err)
+		} else {
+			created.Assignees = append(created.Assignees, u)
+		}
+	}
+	return created, b.String(), nil
+}
+
+// findUser looks up a user by username (case-insensitive, optional @).
+func findUser(users []api.User, name string) (api.User, bool) {
+	name = strings.TrimPrefix(strings.TrimSpace(name), "@")
+	for _, u := range users {
+		if strings.EqualFold(u.Username, name) {
+			return u, true
+		}
 	}
-	return fmt.Sprintf("Task created successfully: %d", created.ID), nil
+	return api.User{}, false
+}
+
+// ensureLabels resolves label names to labels, creating the missing ones
+// the same way Vikunja's quick add does.
+func ensureLabels(ctx context.Context, svc Services, names []string) ([]api.Label, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	existing, err := svc.Label.GetAllLabels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var labels []api.Label
+	for _, name := range names {
+		found := false
+		for _, l := range existing {
+			if strings.EqualFold(l.Title, name) {
+				labels = append(labels, l)
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+		l, err := svc.Label.CreateLabel(ctx, api.Label{Title: name})
+		if err != nil {
+			return nil, fmt.Errorf("creating label %q: %w", name, err)
+		}
+		existing = append(existing, l)
+		labels = append(labels, l)
+	}
+	return labels, nil
 }
 
 // ---------------------------------------------------------------------
 // Shared helpers used by both CLI commands and native MCP tools
 // ---------------------------------------------------------------------
 
-// buildProjectList returns a table (or JSON when verbose) of all projects.
-func buildProjectList(ctx context.Context, svc Services, verbose bool) (string, error) {
+// buildProjectList returns all projects rendered in the format selected by out.
+func buildProjectList(ctx context.Context, svc Services, out render.Options) (string, error) {
 	projects, err := svc.Project.GetAllProjects(ctx)
 	if err != nil {
 		return "", err
 	}
+	return renderProjectList(projects, out)
+}
 
-	// pretty-print JSON for verbose output
-	if verbose {
-		if pretty, err := json.MarshalIndent(projects, "", "  "); err == nil {
-			return string(pretty) + "\n", nil
-		}
-	}
-
-	var buf bytes.Buffer
-	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "ID\tTitle\tFav")
+// renderProjectList renders projects in the format selected by out.
+func renderProjectList(projects []api.Project, out render.Options) (string, error) {
+	table := render.Table{Header: []string{"ID", "Title", "Fav"}}
 	for _, p := range projects {
 		fav := ""
 		if p.IsFavorite {
 			fav = "★"
 		}
-		fmt.Fprintf(w, "%d\t%s\t%s\n", p.ID, p.Title, fav)
+		table.AddRow(p.ID, p.Title, fav)
+	}
+	return out.String(projects, table)
+}
+
+// buildUserList returns users rendered in the format selected by out.
+func buildUserList(users []api.User, out render.Options) (string, error) {
+	table := render.Table{Header: []string{"ID", "Username", "Name"}}
+	for _, u := range users {
+		table.AddRow(u.ID, u.Username, u.Name)
 	}
-	w.Flush()
-	return buf.String(), nil
+	return out.String(users, table)
 }
 
-// toggleTaskDone flips the Done flag of a task and saves it.
-func toggleTaskDone(ctx context.Context, svc Services, taskID int) (string, error) {
-	task, err := svc.Task.GetTask(ctx, taskID)
+// buildProjectUserList returns the owner of a project and the users it is
+// shared with, rendered in the format selected by out.
+func buildProjectUserList(ctx context.Context, svc Services, projectID int, out render.Options) (string, error) {
+	project, err := svc.Project.GetProject(ctx, projectID)
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("retrieving project: %w", err)
+	}
+	users, err := svc.Project.GetProjectUsers(ctx, projectID)
+	if err != nil {
+		return "", fmt.Errorf("retrieving project users: %w", err)
+	}
+
+	table := render.Table{Header: []string{"ID", "Username", "Right"}}
+	table.AddRow(project.Owner.ID, project.Owner.Username, "owner")
+	for _, u := range users {
+		table.AddRow(u.ID, u.Username, u.Right)
+	}
+	result := struct {
+		Owner api.User            `json:"owner"`
+		Users []api.UserWithRight `json:"users"`
+	}{project.Owner, users}
+	return out.String(result, table)
+}
+
+// toggleTaskDone flips the Done flag of a task and saves it, returning the
+// updated task and a message for the user.
+func toggleTaskDone(ctx context.Context, svc Services, taskID int) (api.Task, string, error) {
+	task, err := svc.Task.GetTask(cache.Fresh(ctx), taskID)
+	if err != nil {
+		return api.Task{}, "", err
 	}
 	task.Done = !task.Done
 	updated, err := svc.Task.UpdateTask(ctx, taskID, task)
 	if err != nil {
-		return "", err
+		return api.Task{}, "", err
 	}
 	if updated.Done {
-		return "Task marked as done successfully", nil
+		return updated, "Task marked as done successfully", nil
 	}
-	return "Task marked as not done successfully", nil
+	return updated, "Task marked as not done successfully", nil
 }
 
 // ---------------------------------------------------------------------
 // editTaskSimple – shared helper for non-interactive task updates
 // ---------------------------------------------------------------------
 func editTaskSimple(ctx context.Context, svc Services, taskID int,
-	title, desc, due string, projectID int) (string, error) {
+	title, desc, due string, projectID int) (api.Task, string, error) {
 
 	if title == "" && desc == "" && due == "" && projectID == 0 {
-		return "", fmt.Errorf("at least one of --title/--description/--due/--project is required")
+		return api.Task{}, "", fmt.Errorf("at least one of --title/--description/--due/--project is required")
 	}
 
-	task, err := svc.Task.GetTask(ctx, taskID)
+	task, err := svc.Task.GetTask(cache.Fresh(ctx), taskID)
 	if err != nil {
-		return "", err
+		return api.Task{}, "", err
 	}
 
 	if title != "" {
@@ -475,9 +645,9 @@ func editTaskSimple(ctx context.Context, svc Services, taskID int,
 		task.Description = desc
 	}
 	if due != "" {
-		dt, err := time.Parse("2006-01-02", due)
+		dt, err := dateparse.Parse(due)
 		if err != nil {
-			return "", fmt.Errorf("invalid --due: %w", err)
+			return api.Task{}, "", fmt.Errorf("invalid --due: %w", err)
 		}
 		task.DueDate = dt
 	}
@@ -486,8 +656,9 @@ func editTaskSimple(ctx context.Context, svc Services, taskID int,
 		task.ProjectID = projectID
 	}
 
-	if _, err := svc.Task.UpdateTask(ctx, taskID, task); err != nil {
-		return "", err
+	updated, err := svc.Task.UpdateTask(ctx, taskID, task)
+	if err != nil {
+		return api.Task{}, "", err
 	}
-	return "Task updated successfully", nil
+	return updated, "Task updated successfully", nil
 }
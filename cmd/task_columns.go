@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"kunja/api"
+	"kunja/internal/render"
+)
+
+// taskColumn is a column of the task table: its header and how to print a
+// task's cell.
+type taskColumn struct {
+	header string
+	value  func(t api.Task) string
+}
+
+// taskColumns lists the columns the task table can show, by the names
+// accepted by --columns and the columns setting of reports.
+var taskColumns = map[string]taskColumn{
+	"id":       {"ID", func(t api.Task) string { return strconv.Itoa(t.ID) }},
+	"title":    {"Title", func(t api.Task) string { return t.Title }},
+	"urgency":  {"Urgency", func(t api.Task) string { return fmt.Sprintf("%.3f", t.Urgency) }},
+	"due":      {"Due", func(t api.Task) string { return formatDate(t.DueDate, "2006-01-02") }},
+	"done_at":  {"Done At", func(t api.Task) string { return formatDate(t.DoneAt, "2006-01-02 15:04") }},
+	"done":     {"Done", func(t api.Task) string { return strconv.FormatBool(t.Done) }},
+	"priority": {"Priority", func(t api.Task) string { return strconv.Itoa(t.Priority) }},
+	"project":  {"Project", func(t api.Task) string { return strconv.Itoa(t.ProjectID) }},
+	"start":    {"Start", func(t api.Task) string { return formatDate(t.StartDate, "2006-01-02") }},
+	"end":      {"End", func(t api.Task) string { return formatDate(t.EndDate, "2006-01-02") }},
+	"created":  {"Created", func(t api.Task) string { return formatDate(t.Created, "2006-01-02") }},
+	"updated":  {"Updated", func(t api.Task) string { return formatDate(t.Updated, "2006-01-02 15:04") }},
+	"percent":  {"Percent", func(t api.Task) string { return fmt.Sprintf("%.0f%%", t.PercentDone*100) }},
+	"favorite": {"Favorite", func(t api.Task) string { return strconv.FormatBool(t.IsFavorite) }},
+	"blocked":  {"Blocked", func(t api.Task) string { return strconv.FormatBool(t.IsBlocked()) }},
+	"labels": {"Labels", func(t api.Task) string {
+		titles := make([]string, len(t.Labels))
+		for i, l := range t.Labels {
+			titles[i] = l.Title
+		}
+		return strings.Join(titles, ", ")
+	}},
+	"assignees": {"Assignees", func(t api.Task) string {
+		names := make([]string, len(t.Assignees))
+		for i, u := range t.Assignees {
+			names[i] = u.Username
+		}
+		return strings.Join(names, ", ")
+	}},
+}
+
+// defaultTaskColumns are the columns shown when none are selected.
+var defaultTaskColumns = []string{"id", "title", "urgency", "due", "done_at"}
+
+// taskColumnNames lists the keys of taskColumns in the order used in help
+// and error messages.
+var taskColumnNames = []string{
+	"id", "title", "urgency", "due", "done_at", "done", "priority", "project", "labels",
+	"assignees", "start", "end", "created", "updated", "percent", "favorite", "blocked",
+}
+
+// taskTable builds the table of tasks with the given columns, or the
+// default ones when columns is empty.
+func taskTable(tasks []api.Task, columns []string) (render.Table, error) {
+	if len(columns) == 0 {
+		columns = defaultTaskColumns
+	}
+	cols := make([]taskColumn, len(columns))
+	table := render.Table{Header: make([]string, len(columns))}
+	for i, name := range columns {
+		c, ok := taskColumns[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return render.Table{}, fmt.Errorf("unknown column %q (use %s)", name, strings.Join(taskColumnNames, ", "))
+		}
+		cols[i] = c
+		table.Header[i] = c.header
+	}
+	for _, t := range tasks {
+		row := make([]string, len(cols))
+		for i, c := range cols {
+			row[i] = c.value(t)
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table, nil
+}
@@ -0,0 +1,529 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"kunja/api"
+	"kunja/internal/tui"
+
+	"github.com/spf13/cobra"
+)
+
+// tuiMode is what key presses currently act on.
+type tuiMode int
+
+const (
+	tuiNormal  tuiMode = iota
+	tuiInput           // typing into the prompt line
+	tuiConfirm         // answering a y/n question
+	tuiMove            // picking the project to move a task to
+)
+
+// tuiHelp is shown in the status line.
+const tuiHelp = "j/k move  pgup/pgdn page  tab pane  n new  e edit  d done  x delete  m move  r reload  q quit"
+
+// tuiModel is the full-screen task browser: a project sidebar, the open
+// tasks of the selected project sorted by urgency and the details of the
+// selected task. All data goes through Services, so it runs against any
+// adapter.
+type tuiModel struct {
+	ctx context.Context
+	svc Services
+
+	projects []api.Project
+	tasks    []api.Task // open tasks of all projects, by urgency
+
+	sidebar bool // focus is on the project sidebar
+	project int  // selected sidebar entry; 0 is "All"
+	cursor  int  // selected task among the visible ones
+	offset  int  // first visible task row
+	rows    int  // task rows on the screen, set by View
+
+	mode   tuiMode
+	prompt string
+	input  []rune
+	submit func(text string)
+	pick   int // selected project in tuiMove
+
+	status string
+	quit   bool
+}
+
+// newTUIModel loads projects and tasks and returns the model.
+func newTUIModel(ctx context.Context, svc Services) *tuiModel {
+	m := &tuiModel{ctx: ctx, svc: svc, status: tuiHelp}
+	m.reload()
+	return m
+}
+
+// reload fetches projects and open tasks, keeping the selection on the same
+// task where possible.
+func (m *tuiModel) reload() {
+	selected := 0
+	if t, ok := m.selected(); ok {
+		selected = t.ID
+	}
+
+	projects, err := m.svc.Project.GetAllProjects(m.ctx)
+	if err != nil {
+		m.fail(err)
+		return
+	}
+	m.projects = m.projects[:0]
+	for _, p := range projects {
+		if p.ID > 0 && !p.IsArchived {
+			m.projects = append(m.projects, p)
+		}
+	}
+	if m.project > len(m.projects) {
+		m.project = 0
+	}
+
+	tasks, err := fetchTasks(m.ctx, m.svc, api.GetAllTasksParams{Filter: "done = false"}, 0)
+	if err != nil {
+		m.fail(err)
+		return
+	}
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if tasks[i].Urgency == tasks[j].Urgency {
+			return tasks[i].ID > tasks[j].ID
+		}
+		return tasks[i].Urgency > tasks[j].Urgency
+	})
+	m.tasks = tasks
+
+	m.cursor = 0
+	for i, t := range m.visible() {
+		if t.ID == selected {
+			m.cursor = i
+		}
+	}
+}
+
+func (m *tuiModel) fail(err error) {
+	m.status = "Error: " + err.Error()
+}
+
+// currentProject returns the project selected in the sidebar, or ok=false
+// for "All".
+func (m *tuiModel) currentProject() (api.Project, bool) {
+	if m.project == 0 || m.project > len(m.projects) {
+		return api.Project{}, false
+	}
+	return m.projects[m.project-1], true
+}
+
+// visible returns the tasks of the selected project.
+func (m *tuiModel) visible() []api.Task {
+	p, ok := m.currentProject()
+	if !ok {
+		return m.tasks
+	}
+	var tasks []api.Task
+	for _, t := range m.tasks {
+		if t.ProjectID == p.ID {
+			tasks = append(tasks, t)
+		}
+	}
+	return tasks
+}
+
+func (m *tuiModel) selected() (api.Task, bool) {
+	tasks := m.visible()
+	if m.cursor < 0 || m.cursor >= len(tasks) {
+		return api.Task{}, false
+	}
+	return tasks[m.cursor], true
+}
+
+func (m *tuiModel) projectTitle(id int) string {
+	for _, p := range m.projects {
+		if p.ID == id {
+			return p.Title
+		}
+	}
+	return "#" + strconv.Itoa(id)
+}
+
+// Done implements tui.Model.
+func (m *tuiModel) Done() bool { return m.quit }
+
+// Update implements tui.Model.
+func (m *tuiModel) Update(k tui.Key) {
+	switch m.mode {
+	case tuiInput:
+		m.updateInput(k)
+	case tuiConfirm:
+		m.updateConfirm(k)
+	case tuiMove:
+		m.updateMove(k)
+	default:
+		m.updateNormal(k)
+	}
+}
+
+func (m *tuiModel) updateNormal(k tui.Key) {
+	switch k {
+	case "q", tui.KeyCtrlC:
+		m.quit = true
+	case tui.KeyTab, "h", "l", tui.KeyLeft, tui.KeyRight:
+		m.sidebar = !m.sidebar
+	case "j", tui.KeyDown:
+		m.moveCursor(1)
+	case "k", tui.KeyUp:
+		m.moveCursor(-1)
+	case "g", tui.KeyHome:
+		m.moveCursor(-1 << 30)
+	case "G", tui.KeyEnd:
+		m.moveCursor(1 << 30)
+	case tui.KeyPgDown:
+		m.moveCursor(m.pageSize())
+	case tui.KeyPgUp:
+		m.moveCursor(-m.pageSize())
+	case tui.KeyEnter:
+		m.sidebar = false
+	case "r":
+		m.reload()
+		m.status = "Reloaded."
+	case "?":
+		m.status = tuiHelp
+	case "n":
+		m.startInput("New task: ", "", m.createTask)
+	case "e":
+		if t, ok := m.selected(); ok {
+			m.startInput(fmt.Sprintf("Title of #%d: ", t.ID), t.Title, func(title string) {
+				m.run(editTaskSimple(m.ctx, m.svc, t.ID, title, "", "", 0))
+			})
+		}
+	case "d":
+		if t, ok := m.selected(); ok {
+			m.run(toggleTaskDone(m.ctx, m.svc, t.ID))
+		}
+	case "x", tui.KeyDelete:
+		if t, ok := m.selected(); ok {
+			m.mode = tuiConfirm
+			m.prompt = fmt.Sprintf("Delete #%d %s? (y/n) ", t.ID, t.Title)
+			m.submit = func(string) {
+				if _, err := m.svc.Task.DeleteTask(m.ctx, t.ID); err != nil {
+					m.fail(err)
+					return
+				}
+				m.reload()
+				m.status = fmt.Sprintf("Task #%d deleted.", t.ID)
+			}
+		}
+	case "m":
+		if t, ok := m.selected(); ok && len(m.projects) > 0 {
+			m.mode = tuiMove
+			m.pick = 0
+			for i, p := range m.projects {
+				if p.ID == t.ProjectID {
+					m.pick = i
+				}
+			}
+		}
+	}
+}
+
+// moveCursor moves the selection in the focused pane by delta, clamped to
+// the available entries.
+func (m *tuiModel) moveCursor(delta int) {
+	if m.sidebar {
+		m.project = clamp(m.project+delta, 0, len(m.projects))
+		m.cursor, m.offset = 0, 0
+		return
+	}
+	m.cursor = clamp(m.cursor+delta, 0, len(m.visible())-1)
+}
+
+// pageSize returns how far pgup and pgdown move: the rows of the list as
+// last drawn.
+func (m *tuiModel) pageSize() int {
+	if m.rows < 1 {
+		return 1
+	}
+	return m.rows
+}
+
+func clamp(v, lo, hi int) int {
+	if v > hi {
+		v = hi
+	}
+	if v < lo {
+		v = lo
+	}
+	return v
+}
+
+func (m *tuiModel) startInput(prompt, initial string, submit func(string)) {
+	m.mode = tuiInput
+	m.prompt = prompt
+	m.input = []rune(initial)
+	m.submit = submit
+}
+
+func (m *tuiModel) updateInput(k tui.Key) {
+	switch {
+	case k == tui.KeyEnter:
+		m.mode = tuiNormal
+		if text := strings.TrimSpace(string(m.input)); text != "" {
+			m.submit(text)
+		}
+	case k == tui.KeyEsc || k == tui.KeyCtrlC:
+		m.mode = tuiNormal
+		m.status = "Cancelled."
+	case k == tui.KeyBackspace:
+		if len(m.input) > 0 {
+			m.input = m.input[:len(m.input)-1]
+		}
+	case k == tui.KeyCtrlU:
+		m.input = m.input[:0]
+	case k.Printable():
+		m.input = append(m.input, []rune(string(k))...)
+	}
+}
+
+func (m *tuiModel) updateConfirm(k tui.Key) {
+	m.mode = tuiNormal
+	if k == "y" || k == "Y" {
+		m.submit("")
+		return
+	}
+	m.status = "Cancelled."
+}
+
+func (m *tuiModel) updateMove(k tui.Key) {
+	switch k {
+	case "j", tui.KeyDown:
+		m.pick = clamp(m.pick+1, 0, len(m.projects)-1)
+	case "k", tui.KeyUp:
+		m.pick = clamp(m.pick-1, 0, len(m.projects)-1)
+	case tui.KeyEsc, "q", tui.KeyCtrlC:
+		m.mode = tuiNormal
+		m.status = "Cancelled."
+	case tui.KeyEnter:
+		m.mode = tuiNormal
+		t, ok := m.selected()
+		if !ok {
+			return
+		}
+		p := m.projects[m.pick]
+		if _, _, err := editTaskSimple(m.ctx, m.svc, t.ID, "", "", "", p.ID); err != nil {
+			m.fail(err)
+			return
+		}
+		m.reload()
+		m.status = fmt.Sprintf("Task #%d moved to %s.", t.ID, p.Title)
+	}
+}
+
+// createTask adds a task, parsed with the quick-add syntax, to the selected
+// project or, with "All" selected, to the first one.
+func (m *tuiModel) createTask(text string) {
+	projectID := 0
+	if p, ok := m.currentProject(); ok {
+		projectID = p.ID
+	} else if len(m.projects) > 0 {
+		projectID = m.projects[0].ID
+	}
+	m.run(createTaskSimple(m.ctx, m.svc, text, "", projectID, true))
+}
+
+// run reports the outcome of an action on a task and reloads on success.
+func (m *tuiModel) run(_ api.Task, msg string, err error) {
+	if err != nil {
+		m.fail(err)
+		return
+	}
+	m.reload()
+	m.status = strings.TrimSpace(strings.SplitN(msg, "\n", 2)[0])
+}
+
+// htmlTag matches the markup of Vikunja's rich-text descriptions.
+var htmlTag = regexp.MustCompile(`<[^>]*>`)
+
+// View implements tui.Model.
+func (m *tuiModel) View(width, height int) string {
+	s := &tui.Screen{Width: width, Height: height}
+	tasks := m.visible()
+	title := "All projects"
+	if p, ok := m.currentProject(); ok {
+		title = p.Title
+	}
+	s.Line("kunja · %s · %d open tasks", title, len(tasks))
+
+	const detailHeight = 7
+	listHeight := height - detailHeight - 3
+	if listHeight < 3 {
+		listHeight = 3
+	}
+	sideWidth := width / 4
+	if sideWidth > 24 {
+		sideWidth = 24
+	}
+	mainWidth := width - sideWidth - 3
+
+	// Keep the selected task within the visible rows.
+	rows := listHeight - 1
+	m.rows = rows
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+rows {
+		m.offset = m.cursor - rows + 1
+	}
+	sideOffset := 0
+	if m.project >= rows {
+		sideOffset = m.project - rows + 1
+	}
+
+	// The selection of the focused pane is marked with ">", the other one
+	// with "*".
+	marker := func(selected, focused bool) string {
+		switch {
+		case !selected:
+			return "  "
+		case focused:
+			return "> "
+		}
+		return "* "
+	}
+	for row := 0; row < listHeight; row++ {
+		var left, right string
+		switch {
+		case row == 0:
+			left = "  Projects"
+			right = fmt.Sprintf("  %-5s %7s  %-10s  %s", "ID", "Urgency", "Due", "Title")
+		case sideOffset+row-1 <= len(m.projects):
+			entry := sideOffset + row - 1
+			name := "All"
+			if entry > 0 {
+				name = m.projects[entry-1].Title
+			}
+			left = marker(entry == m.project, m.sidebar) + name
+		}
+		if row > 0 {
+			if i := m.offset + row - 1; i < len(tasks) {
+				t := tasks[i]
+				marker := marker(i == m.cursor, !m.sidebar)
+				right = fmt.Sprintf("%s%-5d %7.2f  %-10s  %s", marker, t.ID, t.Urgency, formatDate(t.DueDate, "2006-01-02"), t.Title)
+			}
+		}
+		s.Line("%s │ %s", tui.Fit(left, sideWidth), tui.Fit(right, mainWidth))
+	}
+	s.Line("%s", strings.Repeat("─", width))
+
+	for _, line := range m.detail(width, detailHeight) {
+		s.Line("%s", line)
+	}
+	for s.Len() < height-1 {
+		s.Line("")
+	}
+
+	switch m.mode {
+	case tuiInput:
+		s.Line("%s%s_", m.prompt, string(m.input))
+	case tuiConfirm:
+		s.Line("%s", m.prompt)
+	case tuiMove:
+		s.Line("Move to: j/k select  enter confirm  esc cancel")
+	default:
+		s.Line("%s", m.status)
+	}
+	return s.String()
+}
+
+// detail returns the lines of the lower pane: the selected task, or the
+// project picker while moving a task.
+func (m *tuiModel) detail(width, height int) []string {
+	var lines []string
+	if m.mode == tuiMove {
+		first := clamp(m.pick-height/2, 0, len(m.projects)-height)
+		if first < 0 {
+			first = 0
+		}
+		for i := first; i < len(m.projects) && len(lines) < height; i++ {
+			marker := "  "
+			if i == m.pick {
+				marker = "> "
+			}
+			lines = append(lines, marker+m.projects[i].Title)
+		}
+		return lines
+	}
+
+	t, ok := m.selected()
+	if !ok {
+		return []string{"No tasks."}
+	}
+	lines = append(lines, fmt.Sprintf("#%d %s", t.ID, t.Title))
+	meta := fmt.Sprintf("Project: %s   Priority: %d   Urgency: %.2f", m.projectTitle(t.ProjectID), t.Priority, t.Urgency)
+	if !t.DueDate.IsZero() {
+		meta += "   Due: " + formatDue(t.DueDate.Local())
+	}
+	lines = append(lines, meta)
+	if len(t.Labels) > 0 {
+		titles := make([]string, len(t.Labels))
+		for i, l := range t.Labels {
+			titles[i] = l.Title
+		}
+		lines = append(lines, "Labels: "+strings.Join(titles, ", "))
+	}
+	desc := html.UnescapeString(htmlTag.ReplaceAllString(t.Description, " "))
+	for _, line := range strings.Split(desc, "\n") {
+		if line = strings.TrimSpace(line); line != "" && len(lines) < height {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+var tuiCmd = &cobra.Command{
+	Use:         "tui",
+	Short:       "Browse and edit tasks in a full-screen terminal UI",
+	Annotations: map[string]string{"skip_mcp": "true"},
+	Long: `Open a full-screen task browser: projects on the left, open tasks of the
+selected project sorted by urgency, and the selected task's details below.
+
+Keys: j/k or arrows move, pgup/pgdown move by a page, tab switches between projects and tasks, n creates a
+task (quick-add syntax), e edits the title, d marks done, x deletes, m moves
+to another project, r reloads and q quits.
+
+--keys runs the UI without a terminal: the keys are played in order (named
+keys in angle brackets, e.g. 'jj<enter>nBuy milk<enter>q') and the final
+screen is printed.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		script, _ := cmd.Flags().GetString("keys")
+		width, _ := cmd.Flags().GetInt("width")
+		height, _ := cmd.Flags().GetInt("height")
+
+		m := newTUIModel(cmd.Context(), getServices(cmd))
+		if cmd.Flags().Changed("keys") {
+			keys, err := tui.ParseScript(script)
+			if err != nil {
+				return err
+			}
+			fmt.Print(tui.Play(m, keys, width, height))
+			return nil
+		}
+		if err := tui.Run(m, os.Stdin, os.Stdout); err != nil {
+			return fmt.Errorf("tui: %w (use --keys to run without a terminal)", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	tuiCmd.Flags().String("keys", "", "play these keys without a terminal and print the final screen")
+	tuiCmd.Flags().Int("width", 100, "screen width for --keys")
+	tuiCmd.Flags().Int("height", 24, "screen height for --keys")
+	rootCmd.AddCommand(tuiCmd)
+}
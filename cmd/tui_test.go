@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"kunja/adapter/memory"
+	"kunja/api"
+	"kunja/internal/tui"
+)
+
+// The tests in this file drive the TUI model with scripted keys through
+// tui.Play, against the demo data of the memory backend.
+
+// play types script into m on a 100x24 screen and returns the screen.
+func play(t *testing.T, m *tuiModel, script string) string {
+	t.Helper()
+	keys, err := tui.ParseScript(script)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tui.Play(m, keys, 100, 24)
+}
+
+// findTask returns the visible task with the given title and its row.
+func findTask(t *testing.T, m *tuiModel, title string) (api.Task, int) {
+	t.Helper()
+	for i, task := range m.visible() {
+		if task.Title == title {
+			return task, i
+		}
+	}
+	t.Fatalf("no open task %q", title)
+	return api.Task{}, 0
+}
+
+// selectTask returns the keys that select the visible task with the given
+// title, from the top of the list.
+func selectTask(t *testing.T, m *tuiModel, title string) string {
+	t.Helper()
+	_, row := findTask(t, m, title)
+	return "g" + strings.Repeat("j", row)
+}
+
+// projectIndex returns the position of the project in the TUI's project
+// list.
+func projectIndex(t *testing.T, m *tuiModel, title string) int {
+	t.Helper()
+	for i, p := range m.projects {
+		if p.Title == title {
+			return i
+		}
+	}
+	t.Fatalf("no project %q", title)
+	return 0
+}
+
+func lastLine(screen string) string {
+	lines := strings.Split(strings.TrimRight(screen, "\n"), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+func TestTUITaskActions(t *testing.T) {
+	ctx := context.Background()
+	a := memory.Demo()
+	m := newTUIModel(ctx, adapterServices(a))
+
+	// n creates a task in the first project while "All" is selected.
+	screen := play(t, m, "nWrite the tests<enter>")
+	created, _, err := a.QueryTasks(ctx, api.GetAllTasksParams{S: "Write the tests"})
+	if err != nil || len(created) != 1 {
+		t.Fatalf("the task was not created (%v):\n%s", err, screen)
+	}
+	task := created[0]
+	if task.ProjectID != m.projects[0].ID {
+		t.Errorf("the task was created in project %d, want %d", task.ProjectID, m.projects[0].ID)
+	}
+	if !strings.Contains(screen, "Write the tests") {
+		t.Errorf("the new task is not listed:\n%s", screen)
+	}
+
+	// d marks the selected task done, which hides it.
+	screen = play(t, m, selectTask(t, m, "Write the tests")+"d")
+	if done, err := a.GetTask(ctx, task.ID); err != nil || !done.Done {
+		t.Errorf("the task is not done (%v):\n%s", err, screen)
+	}
+	if strings.Contains(screen, "Write the tests") {
+		t.Errorf("the done task is still listed:\n%s", screen)
+	}
+
+	// m moves the selected task to the project picked with j/k.
+	from, to := projectIndex(t, m, "Work"), projectIndex(t, m, "Home")
+	pick := strings.Repeat("j", max(to-from, 0)) + strings.Repeat("k", max(from-to, 0))
+	review, _ := findTask(t, m, "Review pull requests")
+	screen = play(t, m, selectTask(t, m, "Review pull requests")+"m"+pick+"<enter>")
+	if moved, err := a.GetTask(ctx, review.ID); err != nil || moved.ProjectID != m.projects[to].ID {
+		t.Errorf("the task is in project %d (%v), want %d:\n%s", moved.ProjectID, err, m.projects[to].ID, screen)
+	}
+	if want := fmt.Sprintf("Task #%d moved to Home.", review.ID); lastLine(screen) != want {
+		t.Errorf("status %q, want %q", lastLine(screen), want)
+	}
+
+	// x asks before deleting; anything but y cancels.
+	screen = play(t, m, selectTask(t, m, "Review pull requests")+"xn")
+	if _, err := a.GetTask(ctx, review.ID); err != nil {
+		t.Errorf("the task was deleted although the deletion was cancelled: %v", err)
+	}
+	if lastLine(screen) != "Cancelled." {
+		t.Errorf("status %q, want Cancelled.", lastLine(screen))
+	}
+	screen = play(t, m, selectTask(t, m, "Review pull requests")+"xy")
+	if _, err := a.GetTask(ctx, review.ID); err == nil {
+		t.Errorf("the task still exists:\n%s", screen)
+	}
+	if want := fmt.Sprintf("Task #%d deleted.", review.ID); lastLine(screen) != want {
+		t.Errorf("status %q, want %q", lastLine(screen), want)
+	}
+
+	play(t, m, "q")
+	if !m.Done() {
+		t.Error("q did not quit")
+	}
+}
+
+func TestTUIPageKeys(t *testing.T) {
+	ctx := context.Background()
+	a := memory.Demo()
+	m := newTUIModel(ctx, adapterServices(a))
+	for i := 1; i <= 40; i++ {
+		if _, err := a.CreateTask(ctx, m.projects[0].ID, api.Task{Title: fmt.Sprintf("Bulk task %d", i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A 24-line screen shows 13 task rows.
+	play(t, m, "r<pgdown>")
+	if m.cursor != 13 {
+		t.Errorf("pgdown moved to row %d, want 13", m.cursor)
+	}
+	screen := play(t, m, "<pgdown><pgdown><pgdown><pgdown>")
+	last := len(m.visible()) - 1
+	if m.cursor != last {
+		t.Errorf("pgdown past the end moved to row %d, want %d", m.cursor, last)
+	}
+	if want := fmt.Sprintf("> %-5d", m.visible()[last].ID); !strings.Contains(screen, want) {
+		t.Errorf("the last task is not selected on screen:\n%s", screen)
+	}
+	play(t, m, "<pgup>")
+	if m.cursor != last-13 {
+		t.Errorf("pgup moved to row %d, want %d", m.cursor, last-13)
+	}
+	play(t, m, "<pgup><pgup><pgup><pgup>")
+	if m.cursor != 0 {
+		t.Errorf("pgup past the start moved to row %d, want 0", m.cursor)
+	}
+}
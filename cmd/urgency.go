@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"kunja/api"
+	"kunja/internal/core"
+	"kunja/internal/render"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var urgencyCmd = &cobra.Command{
+	Use:   "urgency [TASK_ID]",
+	Short: "Explain the urgency score of a task term by term",
+	Long: `Show how the urgency of a task is computed. Each term is a factor taken
+from the task multiplied by a coefficient from the "urgency" section of
+config.yaml, for example:
+
+  urgency:
+    due: 2          # due date proximity (-1 … 6)
+    priority: 1     # priority (0 … 5)
+    favorite: 1
+    age: 1.5        # task age, reaches 1 after age_max days
+    age_max: 365
+    started: 2      # start date has passed
+    blocked: -5     # blocked by an open task
+    blocking: 4     # blocks an open task
+    percent_done: -1
+    labels:
+      urgent: 4
+    projects:
+      "12": -2
+
+Unset coefficients keep Vikunja's default formula: 1 + due + priority + favorite.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		taskID, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid task ID: %q", args[0])
+		}
+		model, err := urgencyModel()
+		if err != nil {
+			return err
+		}
+		opts, err := outputOptions()
+		if err != nil {
+			return err
+		}
+		out, err := buildUrgencyBreakdown(cmd.Context(), getServices(cmd), model, taskID, opts)
+		if err != nil {
+			fmt.Println("Error retrieving task:", err)
+			return err
+		}
+		fmt.Print(out)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(urgencyCmd)
+}
+
+// urgencyModel returns the default urgency model overridden by the
+// "urgency" section of the config file.
+func urgencyModel() (api.UrgencyModel, error) {
+	model := core.DefaultUrgencyModel()
+	if err := viper.UnmarshalKey("urgency", &model); err != nil {
+		return model, fmt.Errorf("invalid urgency config: %w", err)
+	}
+	return model, nil
+}
+
+// buildUrgencyBreakdown returns the terms making up the urgency of a task
+// under model, rendered in the format selected by out.
+func buildUrgencyBreakdown(ctx context.Context, svc Services, model api.UrgencyModel, taskID int, out render.Options) (string, error) {
+	task, err := svc.Task.GetTask(ctx, taskID)
+	if err != nil {
+		return "", err
+	}
+	terms := model.Explain(task, time.Now())
+	total := 0.0
+	for _, t := range terms {
+		total += t.Score
+	}
+
+	if out.Tabular() && task.Done {
+		return fmt.Sprintf("%d:  %s\nTask is done; urgency is 0.\n", task.ID, task.Title), nil
+	}
+
+	table := render.Table{Header: []string{"Term", "Factor", "Coefficient", "Score"}}
+	for _, t := range terms {
+		table.AddRow(t.Name, fmt.Sprintf("%.3f", t.Factor), fmt.Sprintf("%.3f", t.Coefficient), fmt.Sprintf("%.3f", t.Score))
+	}
+	result := struct {
+		ID      int               `json:"id"`
+		Title   string            `json:"title"`
+		Urgency float64           `json:"urgency"`
+		Terms   []api.UrgencyTerm `json:"terms"`
+	}{task.ID, task.Title, total, terms}
+	if !out.Tabular() {
+		return out.String(result, table)
+	}
+
+	table.AddRow("Urgency", "", "", fmt.Sprintf("%.3f", total))
+	text, err := out.String(result, table)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d:  %s\n", task.ID, task.Title) + text, nil
+}
@@ -0,0 +1,731 @@
+// Package dateparse turns human date expressions such as "tomorrow 9am",
+// "next friday", "in 2 weeks", "end of month" or "2026-03-01 17:00
+// Europe/Berlin" into time.Time values. It is shared by every date flag of
+// the CLI, the quick-add parser and the MCP time tools.
+//
+// All relative expressions are resolved against Parser.Now, so callers (and
+// tests) can inject a fixed clock.
+package dateparse
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Parser resolves date expressions. The zero value is ready to use: it reads
+// the wall clock, interprets naive dates in the clock's location and uses
+// midnight when an expression names a day but no time.
+type Parser struct {
+	// Now returns the reference time; nil means time.Now.
+	Now func() time.Time
+	// Location is used for expressions without a zone; nil means the
+	// location of Now().
+	Location *time.Location
+	// DefaultHour is the hour of day used for day-level expressions such as
+	// "tomorrow" when no time is given.
+	DefaultHour int
+}
+
+// Parse parses s with the zero Parser.
+func Parse(s string) (time.Time, error) { return Parser{}.Parse(s) }
+
+func (p Parser) now() time.Time {
+	now := time.Now()
+	if p.Now != nil {
+		now = p.Now()
+	}
+	if p.Location != nil {
+		now = now.In(p.Location)
+	}
+	return now
+}
+
+// absoluteLayouts are tried on the raw input before the phrase grammar and
+// are interpreted in the parser's location.
+var absoluteLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+var reDigits = regexp.MustCompile(`^\d+$`)
+
+// Parse interprets the whole of s as a date expression. Besides the phrase
+// grammar it accepts RFC 3339, ISO dates with optional time, dd.mm.yyyy,
+// unix seconds or milliseconds and "now" with Vikunja-style offsets
+// ("now+7d", "now-2h").
+func (p Parser) Parse(s string) (time.Time, error) {
+	raw := strings.TrimSpace(s)
+	if raw == "" {
+		return time.Time{}, fmt.Errorf("empty date")
+	}
+
+	if reDigits.MatchString(raw) && len(raw) >= 9 {
+		n, _ := strconv.ParseInt(raw, 10, 64)
+		if len(raw) >= 13 {
+			return time.UnixMilli(n), nil
+		}
+		return time.Unix(n, 0), nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	for _, layout := range absoluteLayouts {
+		if t, err := time.ParseInLocation(layout, raw, p.now().Location()); err == nil {
+			return t, nil
+		}
+	}
+
+	toks := tokenize(raw)
+	st := p.parseSeq(toks, 0, parseDate)
+	if st.consumed != len(toks) || st.date == nil && st.clock == nil {
+		return time.Time{}, fmt.Errorf("cannot understand date %q (try e.g. 2006-01-02, tomorrow 9am, next friday, in 2 weeks)", s)
+	}
+	return p.resolve(st)
+}
+
+// Extract finds the first date expression inside free text, e.g. a task
+// title, and returns its value together with the text without it. Only
+// phrases that name a coming day count (see extractDate); a bare "5pm", or
+// "now" and "last week", are left alone.
+func (p Parser) Extract(text string) (time.Time, string, bool) {
+	toks := tokenize(text)
+	for i := range toks {
+		st := p.parseSeq(toks, i, extractDate)
+		if st.date == nil || st.consumed == i {
+			continue
+		}
+		t, err := p.resolve(st)
+		if err != nil {
+			continue
+		}
+		start, end := toks[i].start, toks[st.consumed-1].end
+		return t, text[:start] + text[end:], true
+	}
+	return time.Time{}, text, false
+}
+
+type token struct {
+	text       string // original spelling, used for zones and now±nM
+	lower      string // lower-case, trailing punctuation stripped
+	start, end int
+}
+
+var reToken = regexp.MustCompile(`\S+`)
+
+func tokenize(s string) []token {
+	var toks []token
+	for _, loc := range reToken.FindAllStringIndex(s, -1) {
+		text := s[loc[0]:loc[1]]
+		toks = append(toks, token{
+			text:  text,
+			lower: strings.TrimRight(strings.ToLower(text), ",;"),
+			start: loc[0],
+			end:   loc[1],
+		})
+	}
+	return toks
+}
+
+// dateFunc computes a date relative to now (already in the target zone).
+// dayLevel reports whether only the day is meaningful, so a clock time may
+// be applied; otherwise the result is an exact instant.
+type dateFunc func(now time.Time) (t time.Time, dayLevel bool)
+
+type clock struct{ hour, min int }
+
+type state struct {
+	date     dateFunc
+	clock    *clock
+	loc      *time.Location
+	consumed int // index of the first token not consumed
+}
+
+// fillers may precede a component ("due friday", "at 5pm") and are only
+// consumed together with it.
+var fillers = map[string]bool{"at": true, "on": true, "by": true, "due": true}
+
+// parseSeq greedily reads date, time and zone components starting at
+// token i, in any order, each at most once. dates reads the date component.
+func (p Parser) parseSeq(toks []token, i int, dates func([]token) (dateFunc, int)) state {
+	st := state{consumed: i}
+	for st.consumed < len(toks) {
+		j := st.consumed
+		if fillers[toks[j].lower] && j+1 < len(toks) {
+			j++
+		}
+		if st.date == nil {
+			if fn, n := dates(toks[j:]); n > 0 {
+				st.date, st.consumed = fn, j+n
+				continue
+			}
+		}
+		if st.clock == nil {
+			if c, n := parseClock(toks[j:]); n > 0 {
+				st.clock, st.consumed = &c, j+n
+				continue
+			}
+		}
+		if st.loc == nil && (st.date != nil || st.clock != nil) && j == st.consumed {
+			if loc, ok := parseZone(toks[j].text); ok {
+				st.loc, st.consumed = loc, j+1
+				continue
+			}
+		}
+		break
+	}
+	return st
+}
+
+func (p Parser) resolve(st state) (time.Time, error) {
+	now := p.now()
+	if st.loc != nil {
+		now = now.In(st.loc)
+	}
+	loc := now.Location()
+
+	var t time.Time
+	dayLevel := true
+	if st.date != nil {
+		t, dayLevel = st.date(now)
+	} else {
+		t = startOfDay(now)
+	}
+	if !dayLevel {
+		if st.clock != nil {
+			return time.Time{}, fmt.Errorf("a time of day cannot be combined with a relative time")
+		}
+		return t, nil
+	}
+	h, m := p.DefaultHour, 0
+	if st.clock != nil {
+		h, m = st.clock.hour, st.clock.min
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), h, m, 0, 0, loc), nil
+}
+
+/* ---- components -------------------------------------------------- */
+
+var weekdayNames = map[string]time.Weekday{
+	"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday,
+	"wednesday": time.Wednesday, "thursday": time.Thursday,
+	"friday": time.Friday, "saturday": time.Saturday,
+}
+
+// weekdayAbbrevs are only accepted by Parse, where the whole input is a
+// date; inside titles "sun" or "wed" are too likely to be ordinary words.
+var weekdayAbbrevs = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wed": time.Wednesday, "thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday,
+	"fri": time.Friday, "sat": time.Saturday,
+}
+
+var monthNames = map[string]time.Month{
+	"jan": time.January, "january": time.January, "feb": time.February, "february": time.February,
+	"mar": time.March, "march": time.March, "apr": time.April, "april": time.April,
+	"may": time.May, "jun": time.June, "june": time.June, "jul": time.July, "july": time.July,
+	"aug": time.August, "august": time.August, "sep": time.September, "sept": time.September,
+	"september": time.September, "oct": time.October, "october": time.October,
+	"nov": time.November, "november": time.November, "dec": time.December, "december": time.December,
+}
+
+var numberWords = map[string]int{
+	"a": 1, "an": 1, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6,
+	"seven": 7, "eight": 8, "nine": 9, "ten": 10, "eleven": 11, "twelve": 12,
+}
+
+var (
+	reNowOffset  = regexp.MustCompile(`^(?i:now)((?:[+-]\d+[smhdwMy])*)$`)
+	reOffsetPart = regexp.MustCompile(`([+-])(\d+)([smhdwMy])`)
+	reOrdinal    = regexp.MustCompile(`^(\d{1,2})(?:st|nd|rd|th)?$`)
+	reYear       = regexp.MustCompile(`^\d{4}$`)
+)
+
+func weekday(tok string) (time.Weekday, bool) {
+	if wd, ok := weekdayNames[tok]; ok {
+		return wd, true
+	}
+	wd, ok := weekdayAbbrevs[tok]
+	return wd, ok
+}
+
+func at(toks []token, i int) string {
+	if i < len(toks) {
+		return toks[i].lower
+	}
+	return ""
+}
+
+// parseDate tries to read a date component at the start of toks and
+// returns it together with the number of tokens consumed (0 = no match).
+func parseDate(toks []token) (dateFunc, int) {
+	if len(toks) == 0 {
+		return nil, 0
+	}
+	w0, w1, w2 := at(toks, 0), at(toks, 1), at(toks, 2)
+
+	// now, now+7d, now-2h
+	if m := reNowOffset.FindStringSubmatch(toks[0].text); m != nil {
+		offsets := m[1]
+		return func(now time.Time) (time.Time, bool) {
+			for _, part := range reOffsetPart.FindAllStringSubmatch(offsets, -1) {
+				n, _ := strconv.Atoi(part[2])
+				if part[1] == "-" {
+					n = -n
+				}
+				now = addUnit(now, part[3], n)
+			}
+			return now, false
+		}, 1
+	}
+
+	// ISO and dd.mm.yyyy dates
+	for _, layout := range []string{"2006-01-02", "02.01.2006"} {
+		if d, err := time.Parse(layout, w0); err == nil {
+			return func(now time.Time) (time.Time, bool) {
+				return time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, now.Location()), true
+			}, 1
+		}
+	}
+
+	switch w0 {
+	case "today":
+		return dayOffset(0), 1
+	case "tomorrow", "tmrw":
+		return dayOffset(1), 1
+	case "yesterday":
+		return dayOffset(-1), 1
+	case "day":
+		if w1 == "after" && w2 == "tomorrow" {
+			return dayOffset(2), 3
+		}
+	case "weekend":
+		return weekendFunc(), 1
+	case "end", "start", "beginning":
+		// end of [the] [next] week|month|year
+		if w1 != "of" {
+			break
+		}
+		n := 2
+		if at(toks, n) == "the" {
+			n++
+		}
+		next := false
+		if at(toks, n) == "next" {
+			next = true
+			n++
+		}
+		unit := at(toks, n)
+		if unit != "week" && unit != "month" && unit != "year" {
+			break
+		}
+		return boundary(w0 == "end", unit, next), n + 1
+	case "in":
+		if n, ok := count(w1); ok {
+			if unit, ok := unitName(w2); ok {
+				return relative(unit, n), 3
+			}
+		}
+	case "next", "this", "last":
+		if wd, ok := weekday(w1); ok {
+			return weekdayFunc(wd, w0), 2
+		}
+		if w0 == "this" && w1 == "weekend" {
+			return weekendFunc(), 2
+		}
+		switch w1 {
+		case "week", "month", "year":
+			n := 1
+			if w0 == "last" {
+				n = -1
+			} else if w0 == "this" {
+				n = 0
+			}
+			return relative(w1, n), 2
+		}
+	}
+
+	// N units ago
+	if n, ok := count(w0); ok && w2 == "ago" {
+		if unit, ok := unitName(w1); ok {
+			return relative(unit, -n), 3
+		}
+	}
+
+	if wd, ok := weekday(w0); ok {
+		return weekdayFunc(wd, ""), 1
+	}
+
+	// month day [year] | day month [year]
+	if mon, ok := monthNames[w0]; ok {
+		if m := reOrdinal.FindStringSubmatch(w1); m != nil {
+			day, _ := strconv.Atoi(m[1])
+			return monthDay(mon, day, w2)
+		}
+	}
+	if m := reOrdinal.FindStringSubmatch(w0); m != nil {
+		if mon, ok := monthNames[w1]; ok {
+			day, _ := strconv.Atoi(m[1])
+			return monthDay(mon, day, w2)
+		}
+	}
+
+	return nil, 0
+}
+
+// extractDate is parseDate for Extract. Words in a title are too easily
+// taken for dates ("Fix it now", "Report for last week", "2 days ago"), so
+// it only reads phrases that name a coming day: today, tomorrow, the day
+// after tomorrow, next <weekday>, in N units and <month> <day> [year] with
+// the month spelt out. A bare weekday counts only at the end of the title,
+// optionally followed by a time, as in Vikunja's quick add ("Call mom
+// friday", but not "Friday standup notes").
+func extractDate(toks []token) (dateFunc, int) {
+	w0, w1, w2 := at(toks, 0), at(toks, 1), at(toks, 2)
+	switch w0 {
+	case "today":
+		return dayOffset(0), 1
+	case "tomorrow", "tmrw":
+		return dayOffset(1), 1
+	case "day":
+		if w1 == "after" && w2 == "tomorrow" {
+			return dayOffset(2), 3
+		}
+	case "next":
+		if wd, ok := weekdayNames[w1]; ok {
+			return weekdayFunc(wd, w0), 2
+		}
+	case "in":
+		if n, ok := count(w1); ok {
+			if unit, ok := unitName(w2); ok {
+				return relative(unit, n), 3
+			}
+		}
+	}
+
+	if wd, ok := weekdayNames[w0]; ok && onlyClock(toks[1:]) {
+		return weekdayFunc(wd, ""), 1
+	}
+
+	if mon, ok := monthNames[w0]; ok && w0 == strings.ToLower(mon.String()) {
+		if m := reOrdinal.FindStringSubmatch(w1); m != nil {
+			// "may 3" is as likely a verb and a number; "may 3rd" and
+			// "may 3 2027" are dates.
+			if mon == time.May && w1 == m[1] && !reYear.MatchString(w2) {
+				return nil, 0
+			}
+			day, _ := strconv.Atoi(m[1])
+			return monthDay(mon, day, w2)
+		}
+	}
+	return nil, 0
+}
+
+// onlyClock reports whether toks is empty or holds nothing but a time of
+// day, optionally introduced by a filler and followed by a zone.
+func onlyClock(toks []token) bool {
+	if len(toks) > 1 && fillers[toks[0].lower] {
+		toks = toks[1:]
+	}
+	if len(toks) == 0 {
+		return true
+	}
+	_, n := parseClock(toks)
+	if n == 0 {
+		return false
+	}
+	if n < len(toks) {
+		if _, ok := parseZone(toks[n].text); ok {
+			n++
+		}
+	}
+	return n == len(toks)
+}
+
+// monthDay returns the date of a month and day, in the year given by the
+// following word if it is one, or else the next occurrence. Days that do
+// not exist, such as feb 31, are no date.
+func monthDay(mon time.Month, day int, next string) (dateFunc, int) {
+	if reYear.MatchString(next) {
+		year, _ := strconv.Atoi(next)
+		if !validDay(year, mon, day) {
+			return nil, 0
+		}
+		return fixedDate(year, mon, day), 3
+	}
+	if !validDay(2000, mon, day) { // a leap year, so feb 29 is accepted
+		return nil, 0
+	}
+	return nextDate(mon, day), 2
+}
+
+var reClock = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm)?$`)
+
+// parseClock reads "9am", "9:30 pm", "17:00", "noon" or "midnight".
+func parseClock(toks []token) (clock, int) {
+	w0, w1 := at(toks, 0), at(toks, 1)
+	switch w0 {
+	case "noon", "midday":
+		return clock{12, 0}, 1
+	case "midnight":
+		return clock{0, 0}, 1
+	}
+	m := reClock.FindStringSubmatch(w0)
+	if m == nil {
+		return clock{}, 0
+	}
+	n := 1
+	suffix := m[3]
+	if suffix == "" && (w1 == "am" || w1 == "pm") {
+		suffix, n = w1, 2
+	}
+	// A bare number is not a time ("in 3 days" is handled elsewhere, and
+	// "call 5 people" should not become 05:00).
+	if m[2] == "" && suffix == "" {
+		return clock{}, 0
+	}
+	h, _ := strconv.Atoi(m[1])
+	min := 0
+	if m[2] != "" {
+		min, _ = strconv.Atoi(m[2])
+	}
+	switch suffix {
+	case "pm":
+		if h < 1 || h > 12 {
+			return clock{}, 0
+		}
+		if h < 12 {
+			h += 12
+		}
+	case "am":
+		if h < 1 || h > 12 {
+			return clock{}, 0
+		}
+		if h == 12 {
+			h = 0
+		}
+	}
+	if h > 23 || min > 59 {
+		return clock{}, 0
+	}
+	return clock{h, min}, n
+}
+
+var reOffsetZone = regexp.MustCompile(`^([+-])(\d{2}):?(\d{2})$`)
+
+// parseZone accepts UTC/GMT/Z, "local", ±hh:mm offsets and IANA names.
+func parseZone(tok string) (*time.Location, bool) {
+	switch strings.ToLower(tok) {
+	case "utc", "gmt", "z":
+		return time.UTC, true
+	case "local":
+		return time.Local, true
+	}
+	if m := reOffsetZone.FindStringSubmatch(tok); m != nil {
+		h, _ := strconv.Atoi(m[2])
+		mi, _ := strconv.Atoi(m[3])
+		off := h*3600 + mi*60
+		if m[1] == "-" {
+			off = -off
+		}
+		return time.FixedZone(tok, off), true
+	}
+	if strings.Contains(tok, "/") {
+		if loc, err := time.LoadLocation(tok); err == nil {
+			return loc, true
+		}
+	}
+	return nil, false
+}
+
+/* ---- date helpers ------------------------------------------------ */
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func dayOffset(days int) dateFunc {
+	return func(now time.Time) (time.Time, bool) {
+		return startOfDay(now).AddDate(0, 0, days), true
+	}
+}
+
+func count(w string) (int, bool) {
+	if n, ok := numberWords[w]; ok {
+		return n, true
+	}
+	n, err := strconv.Atoi(w)
+	return n, err == nil && n >= 0
+}
+
+// unitName normalises "mins", "hrs", "weeks", … to minute|hour|day|week|month|year.
+// The single letters s, m, h, d and w are seconds, minutes, hours, days and
+// weeks, as in Go durations.
+func unitName(w string) (string, bool) {
+	switch w {
+	case "s":
+		return "second", true
+	case "m":
+		return "minute", true
+	}
+	switch strings.TrimSuffix(w, "s") {
+	case "second", "sec":
+		return "second", true
+	case "minute", "min":
+		return "minute", true
+	case "hour", "hr", "h":
+		return "hour", true
+	case "day", "d":
+		return "day", true
+	case "week", "wk", "w":
+		return "week", true
+	case "month", "mo":
+		return "month", true
+	case "year", "yr", "y":
+		return "year", true
+	}
+	return "", false
+}
+
+// relative shifts now by n units. Sub-day units yield an instant, day and
+// longer units a day-level date.
+func relative(unit string, n int) dateFunc {
+	return func(now time.Time) (time.Time, bool) {
+		switch unit {
+		case "second":
+			return now.Add(time.Duration(n) * time.Second), false
+		case "minute":
+			return now.Add(time.Duration(n) * time.Minute), false
+		case "hour":
+			return now.Add(time.Duration(n) * time.Hour), false
+		case "week":
+			return startOfDay(now).AddDate(0, 0, 7*n), true
+		case "month":
+			return startOfDay(now).AddDate(0, n, 0), true
+		case "year":
+			return startOfDay(now).AddDate(n, 0, 0), true
+		default:
+			return startOfDay(now).AddDate(0, 0, n), true
+		}
+	}
+}
+
+// addUnit applies one Vikunja-style offset unit (s m h d w M y).
+func addUnit(t time.Time, unit string, n int) time.Time {
+	switch unit {
+	case "s":
+		return t.Add(time.Duration(n) * time.Second)
+	case "m":
+		return t.Add(time.Duration(n) * time.Minute)
+	case "h":
+		return t.Add(time.Duration(n) * time.Hour)
+	case "d":
+		return t.AddDate(0, 0, n)
+	case "w":
+		return t.AddDate(0, 0, 7*n)
+	case "M":
+		return t.AddDate(0, n, 0)
+	case "y":
+		return t.AddDate(n, 0, 0)
+	}
+	return t
+}
+
+// weekdayFunc resolves a weekday name. Plain names mean the next such day
+// after today, "this" includes today, "next" skips to the following week
+// when the day is still ahead this week, and "last" is the most recent past
+// occurrence.
+func weekdayFunc(wd time.Weekday, modifier string) dateFunc {
+	return func(now time.Time) (time.Time, bool) {
+		today := startOfDay(now)
+		diff := (int(wd) - int(today.Weekday()) + 7) % 7
+		switch modifier {
+		case "this":
+		case "last":
+			if diff == 0 {
+				diff = 7
+			}
+			diff -= 7
+		default:
+			if diff == 0 {
+				diff = 7
+			}
+			if modifier == "next" && diff < 7 {
+				diff += 7
+			}
+		}
+		return today.AddDate(0, 0, diff), true
+	}
+}
+
+// weekendFunc is the coming Saturday (today when it is Saturday).
+func weekendFunc() dateFunc {
+	return weekdayFunc(time.Saturday, "this")
+}
+
+// boundary returns the first (start) or last (end) day of the current or
+// next week, month or year. Weeks start on Monday.
+func boundary(end bool, unit string, next bool) dateFunc {
+	return func(now time.Time) (time.Time, bool) {
+		today := startOfDay(now)
+		switch unit {
+		case "week":
+			monday := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+			if next {
+				monday = monday.AddDate(0, 0, 7)
+			}
+			if end {
+				return monday.AddDate(0, 0, 6), true
+			}
+			return monday, true
+		case "month":
+			first := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, today.Location())
+			if next {
+				first = first.AddDate(0, 1, 0)
+			}
+			if end {
+				return first.AddDate(0, 1, -1), true
+			}
+			return first, true
+		default:
+			year := today.Year()
+			if next {
+				year++
+			}
+			if end {
+				return time.Date(year, time.December, 31, 0, 0, 0, 0, today.Location()), true
+			}
+			return time.Date(year, time.January, 1, 0, 0, 0, 0, today.Location()), true
+		}
+	}
+}
+
+// validDay reports whether day exists in mon of year.
+func validDay(year int, mon time.Month, day int) bool {
+	return day >= 1 && time.Date(year, mon, day, 0, 0, 0, 0, time.UTC).Day() == day
+}
+
+func fixedDate(year int, mon time.Month, day int) dateFunc {
+	return func(now time.Time) (time.Time, bool) {
+		return time.Date(year, mon, day, 0, 0, 0, 0, now.Location()), true
+	}
+}
+
+// nextDate is the next occurrence of month/day, today included; feb 29
+// is the next one in a leap year.
+func nextDate(mon time.Month, day int) dateFunc {
+	return func(now time.Time) (time.Time, bool) {
+		today := startOfDay(now)
+		year := today.Year()
+		d := time.Date(year, mon, day, 0, 0, 0, 0, today.Location())
+		for d.Before(today) || d.Day() != day {
+			year++
+			d = time.Date(year, mon, day, 0, 0, 0, 0, today.Location())
+		}
+		return d, true
+	}
+}
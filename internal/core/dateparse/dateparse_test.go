@@ -0,0 +1,212 @@
+package dateparse
+
+import (
+	"testing"
+	"time"
+)
+
+// testNow is Wednesday, 14 October 2026, 10:30 UTC.
+var testNow = time.Date(2026, time.October, 14, 10, 30, 0, 0, time.UTC)
+
+func testParser() Parser {
+	return Parser{Now: func() time.Time { return testNow }}
+}
+
+func day(year int, mon time.Month, d, hour, min int) time.Time {
+	return time.Date(year, mon, d, hour, min, 0, 0, time.UTC)
+}
+
+func TestParse(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"today", day(2026, time.October, 14, 0, 0)},
+		{"tomorrow", day(2026, time.October, 15, 0, 0)},
+		{"tomorrow 9am", day(2026, time.October, 15, 9, 0)},
+		{"yesterday", day(2026, time.October, 13, 0, 0)},
+		{"day after tomorrow", day(2026, time.October, 16, 0, 0)},
+		{"friday", day(2026, time.October, 16, 0, 0)},
+		{"friday 17:00", day(2026, time.October, 16, 17, 0)},
+		{"due friday at 5:30 pm", day(2026, time.October, 16, 17, 30)},
+		{"this friday", day(2026, time.October, 16, 0, 0)},
+		{"next friday", day(2026, time.October, 23, 0, 0)},
+		{"last friday", day(2026, time.October, 9, 0, 0)},
+		{"wednesday", day(2026, time.October, 21, 0, 0)},
+		{"this wednesday", day(2026, time.October, 14, 0, 0)},
+		{"weekend", day(2026, time.October, 17, 0, 0)},
+		{"in 3 days", day(2026, time.October, 17, 0, 0)},
+		{"in two weeks", day(2026, time.October, 28, 0, 0)},
+		{"in 1 month", day(2026, time.November, 14, 0, 0)},
+		{"in 2 hours", day(2026, time.October, 14, 12, 30)},
+		{"in 45 mins", day(2026, time.October, 14, 11, 15)},
+		{"2 days ago", day(2026, time.October, 12, 0, 0)},
+		{"next week", day(2026, time.October, 21, 0, 0)},
+		{"end of week", day(2026, time.October, 18, 0, 0)},
+		{"end of month", day(2026, time.October, 31, 0, 0)},
+		{"start of next month", day(2026, time.November, 1, 0, 0)},
+		{"end of the year", day(2026, time.December, 31, 0, 0)},
+		{"noon", day(2026, time.October, 14, 12, 0)},
+		{"now", testNow},
+		{"now+7d", day(2026, time.October, 21, 10, 30)},
+		{"now-2h", day(2026, time.October, 14, 8, 30)},
+		{"dec 24", day(2026, time.December, 24, 0, 0)},
+		{"march 3", day(2027, time.March, 3, 0, 0)},
+		{"3rd march 2028", day(2028, time.March, 3, 0, 0)},
+		{"oct 14", day(2026, time.October, 14, 0, 0)},
+		{"feb 29", day(2028, time.February, 29, 0, 0)},
+		{"2026-12-24", day(2026, time.December, 24, 0, 0)},
+		{"24.12.2026", day(2026, time.December, 24, 0, 0)},
+		{"2026-03-01 17:00", day(2026, time.March, 1, 17, 0)},
+		{"2026-03-01 17:00 Europe/Berlin", time.Date(2026, time.March, 1, 17, 0, 0, 0, berlin)},
+		{"tomorrow 9am UTC", day(2026, time.October, 15, 9, 0)},
+		{"2026-01-02T03:04:05Z", day(2026, time.January, 2, 3, 4).Add(5 * time.Second)},
+		{"1767225600", time.Unix(1767225600, 0)},
+	}
+	p := testParser()
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := p.Parse(tt.in)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.in, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Parse(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDefaultHour(t *testing.T) {
+	p := testParser()
+	p.DefaultHour = 9
+	got, err := p.Parse("tomorrow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := day(2026, time.October, 15, 9, 0); !got.Equal(want) {
+		t.Errorf("Parse(tomorrow) = %v, want %v", got, want)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"someday",
+		"feb 31",
+		"31 feb",
+		"february 30 2026",
+		"feb 29 2027",
+		"april 31",
+		"oct 0",
+		"31.02.2026",
+		"2026-02-30",
+		"25pm",
+		"in 2 hours 9am",
+	} {
+		if got, err := testParser().Parse(in); err == nil {
+			t.Errorf("Parse(%q) = %v, want an error", in, got)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"2h30m", 2*time.Hour + 30*time.Minute},
+		{"90s", 90 * time.Second},
+		{"P1DT30M", 24*time.Hour + 30*time.Minute},
+		{"PT2H", 2 * time.Hour},
+		{"P1W", 7 * 24 * time.Hour},
+		{"2 hours", 2 * time.Hour},
+		{"1.5 hours", 90 * time.Minute},
+		{"90min", 90 * time.Minute},
+		{"1 day 30 min", 24*time.Hour + 30*time.Minute},
+		{"1 day 30 m", 24*time.Hour + 30*time.Minute},
+		{"2 hours 5 m", 2*time.Hour + 5*time.Minute},
+		{"10 s", 10 * time.Second},
+		{"10 secs", 10 * time.Second},
+		{"3 h", 3 * time.Hour},
+		{"2 d", 48 * time.Hour},
+		{"1 w", 7 * 24 * time.Hour},
+		{"2 weeks 1 day", 15 * 24 * time.Hour},
+		{"1 hr 15 mins", 75 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseDuration(tt.in)
+			if err != nil {
+				t.Fatalf("ParseDuration(%q): %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseDuration(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDurationInvalid(t *testing.T) {
+	for _, in := range []string{"", "soon", "P", "PT", "0 min"} {
+		if got, err := ParseDuration(in); err == nil {
+			t.Errorf("ParseDuration(%q) = %v, want an error", in, got)
+		}
+	}
+}
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		in, rest string
+		want     time.Time
+	}{
+		{"Pay rent today", "Pay rent ", day(2026, time.October, 14, 0, 0)},
+		{"Call Bob tomorrow 5pm", "Call Bob ", day(2026, time.October, 15, 17, 0)},
+		{"Ship it day after tomorrow", "Ship it ", day(2026, time.October, 16, 0, 0)},
+		{"Review due next friday", "Review ", day(2026, time.October, 23, 0, 0)},
+		{"Renew passport in 3 weeks", "Renew passport ", day(2026, time.November, 4, 0, 0)},
+		{"Party on december 24 at 8pm", "Party ", day(2026, time.December, 24, 20, 0)},
+		{"Tax return may 3rd", "Tax return ", day(2027, time.May, 3, 0, 0)},
+		{"Tax return may 3 2027", "Tax return ", day(2027, time.May, 3, 0, 0)},
+		{"Call mom friday", "Call mom ", day(2026, time.October, 16, 0, 0)},
+		{"Call mom friday 5pm", "Call mom ", day(2026, time.October, 16, 17, 0)},
+		{"Submit report due monday at 9:30 am", "Submit report ", day(2026, time.October, 19, 9, 30)},
+	}
+	p := testParser()
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, rest, ok := p.Extract(tt.in)
+			if !ok {
+				t.Fatalf("Extract(%q) found no date", tt.in)
+			}
+			if !got.Equal(tt.want) || rest != tt.rest {
+				t.Errorf("Extract(%q) = %v, %q, want %v, %q", tt.in, got, rest, tt.want, tt.rest)
+			}
+		})
+	}
+}
+
+func TestExtractNoDate(t *testing.T) {
+	for _, in := range []string{
+		"Fix it now",
+		"Fix bug that may 3 people hit",
+		"Report for last week",
+		"Write 2 days ago notes",
+		"Plan next week",
+		"Friday standup notes",
+		"Call mom friday about the party",
+		"Book sat",
+		"Meet at 5pm",
+		"Fix sat solver for mar 3 release",
+		"Release 2026-12-24",
+		"Handle feb 31 edge case",
+	} {
+		if got, _, ok := testParser().Extract(in); ok {
+			t.Errorf("Extract(%q) = %v, want no date", in, got)
+		}
+	}
+}
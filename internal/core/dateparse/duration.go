@@ -0,0 +1,73 @@
+package dateparse
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseDuration accepts a Go duration ("2h30m"), a small ISO-8601 subset
+// ("P1DT30M") or a human phrase ("2 hours", "1 day 30 min", "90min").
+func ParseDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("no duration provided")
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return d, nil
+	}
+	if d, err := parseISODur(s); err == nil {
+		return d, nil
+	}
+	if d, err := parseHumanDur(s); err == nil {
+		return d, nil
+	}
+	return 0, fmt.Errorf("invalid duration: %q", s)
+}
+
+var reISODur = regexp.MustCompile(`(?i)^P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// parseISODur supports PnWnDTnHnMnS (any part optional).
+func parseISODur(s string) (time.Duration, error) {
+	m := reISODur.FindStringSubmatch(s)
+	if m == nil || strings.EqualFold(s, "P") || strings.EqualFold(s, "PT") {
+		return 0, fmt.Errorf("not ISO-8601 duration")
+	}
+	val := func(i int) time.Duration {
+		v, _ := strconv.ParseInt(m[i], 10, 64)
+		return time.Duration(v)
+	}
+	return val(1)*7*24*time.Hour + val(2)*24*time.Hour + val(3)*time.Hour +
+		val(4)*time.Minute + val(5)*time.Second, nil
+}
+
+var reHumanDur = regexp.MustCompile(`(?i)(\d+(?:\.\d+)?)\s*(weeks?|w|days?|d|hours?|hrs?|h|minutes?|mins?|m|seconds?|secs?|s)\b`)
+
+// parseHumanDur parses phrases like "2 hours", "1 day 30 min", "90min".
+func parseHumanDur(s string) (time.Duration, error) {
+	var sec float64
+	for _, m := range reHumanDur.FindAllStringSubmatch(s, -1) {
+		v, err := strconv.ParseFloat(m[1], 64)
+		if err != nil {
+			continue
+		}
+		switch unit, _ := unitName(strings.ToLower(m[2])); unit {
+		case "week":
+			sec += v * 7 * 86400
+		case "day":
+			sec += v * 86400
+		case "hour":
+			sec += v * 3600
+		case "minute":
+			sec += v * 60
+		case "second":
+			sec += v
+		}
+	}
+	if sec == 0 {
+		return 0, fmt.Errorf("not human duration")
+	}
+	return time.Duration(sec * float64(time.Second)), nil
+}
@@ -0,0 +1,171 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Compile translates e into Vikunja's filter query. Parts the server cannot
+// evaluate – local-only fields such as title or urgency, negations it has no
+// operator for, labels that cannot be resolved to IDs – are returned as rest,
+// to be applied with Filter to the tasks the query returns. The top-level
+// conjunction is split so that as much as possible is sent to the server;
+// query is empty and rest is e when nothing can be sent.
+func Compile(e Expr, env Env) (query string, rest Expr, err error) {
+	ev := newEvaluator(env)
+	var parts []string
+	for _, c := range conjuncts(e) {
+		q, ok, err := ev.compile(c)
+		if err != nil {
+			return "", nil, err
+		}
+		if ok {
+			parts = append(parts, q)
+		} else if rest == nil {
+			rest = c
+		} else {
+			rest = &Logical{Op: "&&", Left: rest, Right: c}
+		}
+	}
+	return strings.Join(parts, " && "), rest, nil
+}
+
+// conjuncts flattens the top-level && chain of e.
+func conjuncts(e Expr) []Expr {
+	if l, ok := e.(*Logical); ok && l.Op == "&&" {
+		return append(conjuncts(l.Left), conjuncts(l.Right)...)
+	}
+	return []Expr{e}
+}
+
+// compile returns the query for e, or ok=false when e has to be evaluated
+// locally.
+func (ev *evaluator) compile(e Expr) (string, bool, error) {
+	switch e := e.(type) {
+	case *Logical:
+		left, ok, err := ev.compile(e.Left)
+		if err != nil || !ok {
+			return "", false, err
+		}
+		right, ok, err := ev.compile(e.Right)
+		if err != nil || !ok {
+			return "", false, err
+		}
+		return fmt.Sprintf("(%s %s %s)", left, e.Op, right), true, nil
+	case *Not:
+		c, ok := e.X.(*Comparison)
+		if !ok {
+			return "", false, nil
+		}
+		inverse, ok := negations[c.Op]
+		if f, _ := lookupField(c.Field); !ok || f.kind == kindDate || f.kind == kindLabels || f.kind == kindAssignees {
+			return "", false, nil
+		}
+		negated := *c
+		negated.Op = inverse
+		return ev.compile(&negated)
+	case *Comparison:
+		return ev.compileComparison(e)
+	}
+	return "", false, nil
+}
+
+// negations maps operators to their inverse for compiling "!".
+var negations = map[string]string{
+	"=":  "!=",
+	"!=": "=",
+	">":  "<=",
+	">=": "<",
+	"<":  ">=",
+	"<=": ">",
+}
+
+func (ev *evaluator) compileComparison(c *Comparison) (string, bool, error) {
+	f, _ := lookupField(c.Field)
+	if f.api == "" || c.Op == "not in" {
+		return "", false, nil
+	}
+
+	switch f.kind {
+	case kindBool:
+		b, _ := parseBool(c.Values[0].Text)
+		return fmt.Sprintf("%s %s %t", f.api, c.Op, b), true, nil
+
+	case kindNumber:
+		return fmt.Sprintf("%s %s %s", f.api, c.Op, joinValues(c.Values)), true, nil
+
+	case kindDate:
+		want, day, err := dateValue(c.Values[0].Text, ev.now)
+		if err != nil {
+			return "", false, err
+		}
+		if !day {
+			return fmt.Sprintf("%s %s %s", f.api, c.Op, formatTime(want)), true, nil
+		}
+		next := want.AddDate(0, 0, 1)
+		switch c.Op {
+		case "=":
+			return fmt.Sprintf("(%s >= %s && %s < %s)", f.api, formatTime(want), f.api, formatTime(next)), true, nil
+		case "<", ">=":
+			return fmt.Sprintf("%s %s %s", f.api, c.Op, formatTime(want)), true, nil
+		case "<=":
+			return fmt.Sprintf("%s < %s", f.api, formatTime(next)), true, nil
+		case ">":
+			return fmt.Sprintf("%s >= %s", f.api, formatTime(next)), true, nil
+		}
+		return "", false, nil // "!=" on a day keeps unset dates out only locally
+
+	case kindLabels:
+		if c.Op == "!=" {
+			return "", false, nil
+		}
+		ids := make([]string, len(c.Values))
+		for i, v := range c.Values {
+			if _, err := strconv.Atoi(v.Text); err == nil {
+				ids[i] = v.Text
+				continue
+			}
+			if ev.env.LabelID == nil {
+				return "", false, nil
+			}
+			id, err := ev.env.LabelID(v.Text)
+			if err != nil {
+				return "", false, err
+			}
+			ids[i] = strconv.Itoa(id)
+		}
+		return fmt.Sprintf("%s in %s", f.api, strings.Join(ids, ", ")), true, nil
+
+	case kindProject:
+		ids := make([]string, len(c.Values))
+		for i, v := range c.Values {
+			id, err := ev.projectID(v)
+			if err != nil {
+				return "", false, err
+			}
+			ids[i] = strconv.Itoa(id)
+		}
+		return fmt.Sprintf("%s %s %s", f.api, c.Op, strings.Join(ids, ", ")), true, nil
+
+	case kindAssignees:
+		if c.Op == "!=" {
+			return "", false, nil
+		}
+		return fmt.Sprintf("%s in %s", f.api, joinValues(c.Values)), true, nil
+	}
+	return "", false, nil
+}
+
+func joinValues(values []Value) string {
+	texts := make([]string, len(values))
+	for i, v := range values {
+		texts[i] = v.Text
+	}
+	return strings.Join(texts, ", ")
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
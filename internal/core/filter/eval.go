@@ -0,0 +1,289 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"kunja/internal/core"
+)
+
+// Env supplies what compiling and evaluating need beyond the expression
+// itself. The zero value uses the wall clock and only accepts numeric
+// project references.
+type Env struct {
+	// Now returns the reference time for relative dates; nil means time.Now.
+	Now func() time.Time
+	// ProjectID resolves a project title or identifier to its ID.
+	ProjectID func(ref string) (int, error)
+	// LabelID resolves a label title to its ID. Compile needs it to send
+	// labels given by name to the server.
+	LabelID func(ref string) (int, error)
+}
+
+// evaluator holds the reference time and the resolved project references
+// shared by all tasks of one Filter or Compile call.
+type evaluator struct {
+	env      Env
+	now      time.Time
+	projects map[string]int
+}
+
+func newEvaluator(env Env) *evaluator {
+	now := time.Now()
+	if env.Now != nil {
+		now = env.Now()
+	}
+	return &evaluator{env: env, now: now, projects: map[string]int{}}
+}
+
+// Match reports whether task satisfies e.
+func Match(e Expr, task core.Task, env Env) (bool, error) {
+	return newEvaluator(env).match(e, task)
+}
+
+// Filter returns the tasks satisfying e, in their original order.
+func Filter(e Expr, tasks []core.Task, env Env) ([]core.Task, error) {
+	ev := newEvaluator(env)
+	var out []core.Task
+	for _, t := range tasks {
+		ok, err := ev.match(e, t)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+func (ev *evaluator) match(e Expr, task core.Task) (bool, error) {
+	switch e := e.(type) {
+	case *Logical:
+		left, err := ev.match(e.Left, task)
+		if err != nil {
+			return false, err
+		}
+		if e.Op == "&&" && !left || e.Op == "||" && left {
+			return left, nil
+		}
+		return ev.match(e.Right, task)
+	case *Not:
+		ok, err := ev.match(e.X, task)
+		return !ok, err
+	case *Comparison:
+		return ev.compare(e, task)
+	}
+	return false, fmt.Errorf("filter: unknown expression %T", e)
+}
+
+func (ev *evaluator) compare(c *Comparison, task core.Task) (bool, error) {
+	f, _ := lookupField(c.Field)
+	switch f.kind {
+	case kindBool:
+		want, _ := parseBool(c.Values[0].Text)
+		var got bool
+		switch c.Field {
+		case "done":
+			got = task.Done
+		case "favorite":
+			got = task.IsFavorite
+		case "blocked":
+			got = task.IsBlocked()
+		}
+		return (got == want) == (c.Op == "="), nil
+
+	case kindNumber:
+		var got float64
+		switch c.Field {
+		case "priority":
+			got = float64(task.Priority)
+		case "percent_done":
+			got = task.PercentDone
+		case "id":
+			got = float64(task.ID)
+		case "urgency":
+			got = task.Urgency
+		}
+		return anyValue(c, func(v Value) bool {
+			want, _ := strconv.ParseFloat(v.Text, 64)
+			return compareNumbers(got, c.Op, want)
+		}), nil
+
+	case kindDate:
+		var got time.Time
+		switch c.Field {
+		case "due":
+			got = task.DueDate
+		case "start":
+			got = task.StartDate
+		case "end":
+			got = task.EndDate
+		case "done_at":
+			got = task.DoneAt
+		case "created":
+			got = task.Created
+		case "updated":
+			got = task.Updated
+		}
+		if got.IsZero() {
+			return false, nil // like Vikunja, unset dates never match
+		}
+		want, day, err := dateValue(c.Values[0].Text, ev.now)
+		if err != nil {
+			return false, err
+		}
+		return compareDates(got, c.Op, want, day), nil
+
+	case kindText:
+		got := task.Title
+		if c.Field == "description" {
+			got = task.Description
+		}
+		if c.Op == "like" {
+			return likePattern(c.Values[0].Text).MatchString(got), nil
+		}
+		return anyValue(c, func(v Value) bool { return strings.EqualFold(got, v.Text) }), nil
+
+	case kindLabels:
+		return anyValue(c, func(v Value) bool {
+			for _, l := range task.Labels {
+				if strings.EqualFold(l.Title, v.Text) || strconv.Itoa(l.ID) == v.Text {
+					return true
+				}
+			}
+			return false
+		}), nil
+
+	case kindAssignees:
+		return anyValue(c, func(v Value) bool {
+			for _, u := range task.Assignees {
+				if strings.EqualFold(u.Username, v.Text) || strconv.Itoa(u.ID) == v.Text {
+					return true
+				}
+			}
+			return false
+		}), nil
+
+	case kindProject:
+		ids := make([]int, len(c.Values))
+		for i, v := range c.Values {
+			id, err := ev.projectID(v)
+			if err != nil {
+				return false, err
+			}
+			ids[i] = id
+		}
+		return anyValue(c, func(v Value) bool {
+			for _, id := range ids {
+				if task.ProjectID == id {
+					return true
+				}
+			}
+			return false
+		}), nil
+	}
+	return false, fmt.Errorf("filter: cannot evaluate %s", c.Field)
+}
+
+// anyValue applies the membership semantics of c.Op: "=" and "in" need one
+// matching value, "!=" and "not in" need none. Other operators have a
+// single value and use test directly.
+func anyValue(c *Comparison, test func(Value) bool) bool {
+	switch c.Op {
+	case "=", "in", "!=", "not in":
+		hit := false
+		for _, v := range c.Values {
+			if test(v) {
+				hit = true
+				break
+			}
+		}
+		return hit == (c.Op == "=" || c.Op == "in")
+	}
+	return test(c.Values[0])
+}
+
+func compareNumbers(got float64, op string, want float64) bool {
+	switch op {
+	case "=", "in", "!=", "not in":
+		return got == want // negation is applied by anyValue
+	case ">":
+		return got > want
+	case ">=":
+		return got >= want
+	case "<":
+		return got < want
+	case "<=":
+		return got <= want
+	}
+	return false
+}
+
+// compareDates compares got with want. When day is set, want stands for the
+// whole day starting at want.
+func compareDates(got time.Time, op string, want time.Time, day bool) bool {
+	end := want.Add(time.Nanosecond)
+	if day {
+		end = want.AddDate(0, 0, 1)
+	}
+	switch op {
+	case "=":
+		return !got.Before(want) && got.Before(end)
+	case "!=":
+		return got.Before(want) || !got.Before(end)
+	case ">":
+		return !got.Before(end)
+	case ">=":
+		return !got.Before(want)
+	case "<":
+		return got.Before(want)
+	case "<=":
+		return got.Before(end)
+	}
+	return false
+}
+
+// likePattern turns a like operand into a case-insensitive regexp. Without
+// SQL wildcards (% and _) it matches anywhere in the text.
+func likePattern(s string) *regexp.Regexp {
+	if !strings.ContainsAny(s, "%_") {
+		return regexp.MustCompile("(?i)" + regexp.QuoteMeta(s))
+	}
+	var b strings.Builder
+	b.WriteString("(?is)^")
+	for _, r := range s {
+		switch r {
+		case '%':
+			b.WriteString(".*")
+		case '_':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+// projectID resolves a project operand to its ID.
+func (ev *evaluator) projectID(v Value) (int, error) {
+	if id, err := strconv.Atoi(v.Text); err == nil {
+		return id, nil
+	}
+	if id, ok := ev.projects[v.Text]; ok {
+		return id, nil
+	}
+	if ev.env.ProjectID == nil {
+		return 0, fmt.Errorf("filter: cannot resolve project %q by name here; use its ID", v.Text)
+	}
+	id, err := ev.env.ProjectID(v.Text)
+	if err != nil {
+		return 0, err
+	}
+	ev.projects[v.Text] = id
+	return id, nil
+}
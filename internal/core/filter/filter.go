@@ -0,0 +1,221 @@
+// Package filter implements kunja's task filter language, e.g.
+//
+//	priority >= 3 && (label in backend, api) && due < now+7d
+//
+// An expression is parsed once into an AST which can then be compiled into
+// the filter query understood by Vikunja's /tasks/all endpoint, evaluated
+// against core.Task values client-side, or both: Compile sends everything the
+// server understands and hands back the remainder to be matched locally.
+//
+// Grammar:
+//
+//	expr       = and { "||" and }
+//	and        = unary { "&&" unary }
+//	unary      = "!" unary | "(" expr ")" | comparison
+//	comparison = field op value | field ["not"] "in" value { "," value }
+//	op         = "=" | "==" | "!=" | ">" | ">=" | "<" | "<=" | "like"
+//	value      = word { word } | quoted string
+//
+// Values may span several words ("due < next friday"); dates accept
+// everything the dateparse package does.
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Expr is a node of the filter AST.
+type Expr interface {
+	String() string
+	node()
+}
+
+// Logical combines two expressions with "&&" or "||".
+type Logical struct {
+	Op          string // "&&" or "||"
+	Left, Right Expr
+}
+
+// Not negates an expression.
+type Not struct {
+	X Expr
+}
+
+// Comparison tests one task field against one or more values.
+type Comparison struct {
+	Field  string // canonical field name, see Fields
+	Op     string // "=", "!=", ">", ">=", "<", "<=", "like", "in" or "not in"
+	Values []Value
+	Pos    int // byte offset of the field in the source
+}
+
+// Value is a literal operand as written in the source.
+type Value struct {
+	Text string
+	Pos  int
+}
+
+func (*Logical) node()    {}
+func (*Not) node()        {}
+func (*Comparison) node() {}
+
+func (e *Logical) String() string {
+	return fmt.Sprintf("(%s %s %s)", e.Left, e.Op, e.Right)
+}
+
+func (e *Not) String() string {
+	if _, ok := e.X.(*Logical); ok {
+		return "!" + e.X.String()
+	}
+	return "!(" + e.X.String() + ")"
+}
+
+func (e *Comparison) String() string {
+	vals := make([]string, len(e.Values))
+	for i, v := range e.Values {
+		vals[i] = v.Text
+		if strings.ContainsAny(v.Text, " ,()&|!=<>\"'") {
+			vals[i] = fmt.Sprintf("%q", v.Text)
+		}
+	}
+	return fmt.Sprintf("%s %s %s", e.Field, e.Op, strings.Join(vals, ", "))
+}
+
+// kind is the type of a field, which decides the operators it accepts and
+// how its values are interpreted.
+type kind int
+
+const (
+	kindBool kind = iota
+	kindNumber
+	kindDate
+	kindText
+	kindLabels
+	kindProject
+	kindAssignees
+)
+
+// field describes a filterable task field. api is the name of the field in
+// Vikunja's filter query; fields without one are only evaluated locally.
+type field struct {
+	name string
+	kind kind
+	api  string
+}
+
+var fieldList = []field{
+	{"done", kindBool, "done"},
+	{"priority", kindNumber, "priority"},
+	{"percent_done", kindNumber, "percent_done"},
+	{"due", kindDate, "due_date"},
+	{"start", kindDate, "start_date"},
+	{"end", kindDate, "end_date"},
+	{"done_at", kindDate, "done_at"},
+	{"created", kindDate, "created"},
+	{"updated", kindDate, "updated"},
+	{"label", kindLabels, "labels"},
+	{"project", kindProject, "project"},
+	{"assignee", kindAssignees, "assignees"},
+	{"id", kindNumber, ""},
+	{"urgency", kindNumber, ""},
+	{"title", kindText, ""},
+	{"description", kindText, ""},
+	{"favorite", kindBool, ""},
+	{"blocked", kindBool, ""},
+}
+
+// fieldAliases maps alternative spellings, including Vikunja's own field
+// names, to the canonical names.
+var fieldAliases = map[string]string{
+	"due_date":    "due",
+	"duedate":     "due",
+	"start_date":  "start",
+	"startdate":   "start",
+	"end_date":    "end",
+	"enddate":     "end",
+	"doneat":      "done_at",
+	"percent":     "percent_done",
+	"percentdone": "percent_done",
+	"labels":      "label",
+	"assignees":   "assignee",
+	"is_favorite": "favorite",
+}
+
+func lookupField(name string) (field, bool) {
+	name = strings.ToLower(name)
+	if canonical, ok := fieldAliases[name]; ok {
+		name = canonical
+	}
+	for _, f := range fieldList {
+		if f.name == name {
+			return f, true
+		}
+	}
+	return field{}, false
+}
+
+// Fields returns the canonical names of all filterable fields.
+func Fields() []string {
+	names := make([]string, len(fieldList))
+	for i, f := range fieldList {
+		names[i] = f.name
+	}
+	return names
+}
+
+// operators lists the operators each kind of field accepts.
+var operators = map[kind][]string{
+	kindBool:      {"=", "!="},
+	kindNumber:    {"=", "!=", ">", ">=", "<", "<=", "in", "not in"},
+	kindDate:      {"=", "!=", ">", ">=", "<", "<="},
+	kindText:      {"=", "!=", "like", "in", "not in"},
+	kindLabels:    {"=", "!=", "in", "not in"},
+	kindProject:   {"=", "!=", "in", "not in"},
+	kindAssignees: {"=", "!=", "in", "not in"},
+}
+
+// Mentions reports whether e tests the given field (canonical or alias name).
+func Mentions(e Expr, name string) bool {
+	f, ok := lookupField(name)
+	if !ok {
+		return false
+	}
+	switch e := e.(type) {
+	case *Logical:
+		return Mentions(e.Left, name) || Mentions(e.Right, name)
+	case *Not:
+		return Mentions(e.X, name)
+	case *Comparison:
+		return e.Field == f.name
+	}
+	return false
+}
+
+// Error is a syntax or type error at a byte offset of the source. Its
+// message and Pointer count columns in characters, not bytes.
+type Error struct {
+	Source string
+	Pos    int
+	Msg    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("filter: column %d: %s", e.column()+1, e.Msg)
+}
+
+// Pointer returns the source with a caret under the offending position,
+// for display below the error message.
+func (e *Error) Pointer() string {
+	return e.Source + "\n" + strings.Repeat(" ", e.column()) + "^"
+}
+
+// column returns the 0-based character column of Pos.
+func (e *Error) column() int {
+	pos := e.Pos
+	if pos > len(e.Source) {
+		pos = len(e.Source)
+	}
+	return utf8.RuneCountInString(e.Source[:pos])
+}
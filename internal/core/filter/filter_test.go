@@ -0,0 +1,196 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"kunja/internal/core"
+)
+
+// testNow is Wednesday, 14 October 2026, 10:30 UTC.
+var testNow = time.Date(2026, time.October, 14, 10, 30, 0, 0, time.UTC)
+
+func testEnv() Env {
+	ids := map[string]int{"work": 2, "backend": 11, "api": 12}
+	lookup := func(ref string) (int, error) {
+		if id, ok := ids[strings.ToLower(ref)]; ok {
+			return id, nil
+		}
+		return 0, fmt.Errorf("no %q", ref)
+	}
+	return Env{Now: func() time.Time { return testNow }, ProjectID: lookup, LabelID: lookup}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		src    string
+		column int
+		msg    string
+	}{
+		{"colour = red", 1, `unknown field "colour"`},
+		{"priority >", 11, "expected a value but found end of input"},
+		{"priority = high", 12, `priority expects a number, not "high"`},
+		{"priority = 1, 2", 13, "a list of values needs the in operator"},
+		{"priority >= 3 & done = true", 15, `unexpected '&' (did you mean "&&"?)`},
+		{"due like friday", 5, "operator like cannot be used with due"},
+		{"label not backend", 11, `expected in after not but found "backend"`},
+		{"(done = true", 1, "unclosed ("},
+		{"done = true)", 12, "unmatched )"},
+		{`title = "abc`, 9, "unterminated string"},
+		{"done = maybe", 8, `expected true or false, not "maybe"`},
+		{"", 1, "empty filter"},
+		// Columns count characters: "größe" is 5 of them in 7 bytes.
+		{`title = "größe" && prio = 1`, 20, `unknown field "prio"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			_, err := Parse(tt.src)
+			var fe *Error
+			if !errors.As(err, &fe) {
+				t.Fatalf("Parse(%q) = %v, want an *Error", tt.src, err)
+			}
+			want := fmt.Sprintf("filter: column %d: %s", tt.column, tt.msg)
+			if !strings.HasPrefix(err.Error(), want) {
+				t.Errorf("Parse(%q) = %q, want %q", tt.src, err, want)
+			}
+			caret := strings.Split(fe.Pointer(), "\n")[1]
+			if want := strings.Repeat(" ", tt.column-1) + "^"; caret != want {
+				t.Errorf("Pointer() caret %q, want %q", caret, want)
+			}
+		})
+	}
+}
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		src, query, rest string
+	}{
+		{"done = false && project = Work", "done = false && project = 2", ""},
+		{"priority >= 3 && title like bug", "priority >= 3", "title like bug"},
+		{"label in backend, api", "labels in 11, 12", ""},
+		{"!(priority = 5)", "priority != 5", ""},
+		{"!(due < today) && done = false", "done = false", "!(due < today)"},
+		{"due = today", "(due_date >= 2026-10-14T00:00:00Z && due_date < 2026-10-15T00:00:00Z)", ""},
+		{"due <= tomorrow", "due_date < 2026-10-16T00:00:00Z", ""},
+		{"due < now+7d", "due_date < 2026-10-21T10:30:00Z", ""},
+		{"priority > 2 || urgency > 5", "", "(priority > 2 || urgency > 5)"},
+		{"assignee not in alice && favorite = true", "", "(assignee not in alice && favorite = true)"},
+		{"assignee in alice, bob", "assignees in alice, bob", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			e, err := Parse(tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			query, rest, err := Compile(e, testEnv())
+			if err != nil {
+				t.Fatal(err)
+			}
+			restText := ""
+			if rest != nil {
+				restText = rest.String()
+			}
+			if query != tt.query || restText != tt.rest {
+				t.Errorf("Compile(%q) = %q, %q, want %q, %q", tt.src, query, restText, tt.query, tt.rest)
+			}
+		})
+	}
+
+	// A label LabelID cannot resolve is an error; without LabelID, labels
+	// given by name are matched locally.
+	e, _ := Parse("label in backend, frontend")
+	if query, _, err := Compile(e, testEnv()); err == nil {
+		t.Errorf("Compile resolved an unknown label to %q", query)
+	}
+	e, _ = Parse("label = backend && priority = 1")
+	query, rest, err := Compile(e, Env{})
+	if err != nil || query != "priority = 1" || rest == nil || rest.String() != "label = backend" {
+		t.Errorf("Compile without LabelID = %q, %v, %v", query, rest, err)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	task := core.Task{
+		ID:        7,
+		Title:     "Fix login bug",
+		Priority:  3,
+		ProjectID: 2,
+		Labels:    []core.Label{{ID: 11, Title: "backend"}, {ID: 13, Title: "urgent"}},
+		Assignees: []core.User{{ID: 4, Username: "alice"}},
+	}
+	tests := []struct {
+		src  string
+		want bool
+	}{
+		{"label = urgent", true},
+		{"label != urgent", false},
+		{"!(label = urgent)", false},
+		{"label != docs", true},
+		{"!(label = docs)", true},
+		{"label in docs, backend", true},
+		{"label in docs, frontend", false},
+		{"label not in docs, backend", false},
+		{"label not in docs, frontend", true},
+		{"label = 11", true},
+		{"priority != 3", false},
+		{"priority in 1, 3", true},
+		{"priority not in 1, 3", false},
+		{"priority not in 1, 2", true},
+		{"!(priority >= 4)", true},
+		{"assignee = ALICE", true},
+		{"assignee not in bob", true},
+		{"project = work && title like login", true},
+		{"project != 2 || title like 'fix%bug'", true},
+		{"title like fix_bug", false},
+		// Unset dates match no comparison, but their negation.
+		{"due != today", false},
+		{"!(due = today)", true},
+		{"!(label = urgent) || !(priority = 3)", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			e, err := Parse(tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := Match(e, task, testEnv())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Match(%q) = %v, want %v", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPage(t *testing.T) {
+	tasks := make([]core.Task, 7)
+	for i := range tasks {
+		tasks[i].ID = i + 1
+	}
+	tests := []struct {
+		page, perPage int
+		want          []int
+	}{
+		{1, 3, []int{1, 2, 3}},
+		{3, 3, []int{7}},
+		{4, 3, nil},
+		{0, 3, []int{1, 2, 3}},
+		{2, 0, []int{1, 2, 3, 4, 5, 6, 7}},
+		{1, 10, []int{1, 2, 3, 4, 5, 6, 7}},
+	}
+	for _, tt := range tests {
+		var got []int
+		for _, task := range Page(tasks, tt.page, tt.perPage) {
+			got = append(got, task.ID)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("Page(%d, %d) = %v, want %v", tt.page, tt.perPage, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,325 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"kunja/internal/core/dateparse"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokString
+	tokOp // comparison operator
+	tokAnd
+	tokOr
+	tokNot
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) describe() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of input"
+	case tokString:
+		return fmt.Sprintf("string %q", t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// wordBreak lists the characters that end an unquoted word.
+const wordBreak = " \t\r\n()!,=<>&|\"'"
+
+func lex(src string) ([]token, error) {
+	var toks []token
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+		case c == '(':
+			toks = append(toks, token{tokLParen, "(", i})
+			i++
+		case c == ')':
+			toks = append(toks, token{tokRParen, ")", i})
+			i++
+		case c == ',':
+			toks = append(toks, token{tokComma, ",", i})
+			i++
+		case strings.HasPrefix(src[i:], "&&"):
+			toks = append(toks, token{tokAnd, "&&", i})
+			i += 2
+		case strings.HasPrefix(src[i:], "||"):
+			toks = append(toks, token{tokOr, "||", i})
+			i += 2
+		case strings.HasPrefix(src[i:], "!="), strings.HasPrefix(src[i:], ">="),
+			strings.HasPrefix(src[i:], "<="), strings.HasPrefix(src[i:], "=="):
+			op := src[i : i+2]
+			if op == "==" {
+				op = "="
+			}
+			toks = append(toks, token{tokOp, op, i})
+			i += 2
+		case c == '=' || c == '<' || c == '>':
+			toks = append(toks, token{tokOp, string(c), i})
+			i++
+		case c == '!':
+			toks = append(toks, token{tokNot, "!", i})
+			i++
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(src[i+1:], c)
+			if end < 0 {
+				return nil, &Error{src, i, "unterminated string"}
+			}
+			toks = append(toks, token{tokString, src[i+1 : i+1+end], i})
+			i += end + 2
+		case c == '&' || c == '|':
+			return nil, &Error{src, i, fmt.Sprintf("unexpected %q (did you mean %q?)", c, string([]byte{c, c}))}
+		default:
+			start := i
+			for i < len(src) && !strings.ContainsRune(wordBreak, rune(src[i])) {
+				i++
+			}
+			toks = append(toks, token{tokWord, src[start:i], start})
+		}
+	}
+	return append(toks, token{tokEOF, "", len(src)}), nil
+}
+
+type parser struct {
+	src  string
+	toks []token
+	i    int
+}
+
+// Parse parses and type-checks a filter expression. Errors are *Error
+// values pointing at the offending column.
+func Parse(src string) (Expr, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{src: src, toks: toks}
+	if p.peek().kind == tokEOF {
+		return nil, p.errorf(p.peek(), "empty filter")
+	}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		if t.kind == tokRParen {
+			return nil, p.errorf(t, "unmatched )")
+		}
+		return nil, p.errorf(t, "expected && or || but found %s", t.describe())
+	}
+	return e, nil
+}
+
+func (p *parser) peek() token { return p.toks[p.i] }
+
+func (p *parser) next() token {
+	t := p.toks[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) errorf(t token, format string, args ...interface{}) error {
+	return &Error{p.src, t.pos, fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Logical{Op: "||", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokAnd {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &Logical{Op: "&&", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	switch t := p.peek(); t.kind {
+	case tokNot:
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{X: x}, nil
+	case tokLParen:
+		p.next()
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.peek(); closing.kind != tokRParen {
+			if closing.kind == tokEOF {
+				return nil, p.errorf(t, "unclosed (")
+			}
+			return nil, p.errorf(closing, "expected ) but found %s", closing.describe())
+		}
+		p.next()
+		return e, nil
+	case tokWord:
+		return p.parseComparison()
+	default:
+		return nil, p.errorf(t, "expected a field name but found %s", t.describe())
+	}
+}
+
+func (p *parser) parseComparison() (Expr, error) {
+	name := p.next()
+	f, ok := lookupField(name.text)
+	if !ok {
+		return nil, p.errorf(name, "unknown field %q (known fields: %s)", name.text, strings.Join(Fields(), ", "))
+	}
+
+	opTok := p.next()
+	var op string
+	switch {
+	case opTok.kind == tokOp:
+		op = opTok.text
+	case opTok.kind == tokWord && strings.EqualFold(opTok.text, "like"):
+		op = "like"
+	case opTok.kind == tokWord && strings.EqualFold(opTok.text, "in"):
+		op = "in"
+	case opTok.kind == tokWord && strings.EqualFold(opTok.text, "not"):
+		if in := p.next(); in.kind != tokWord || !strings.EqualFold(in.text, "in") {
+			return nil, p.errorf(in, "expected in after not but found %s", in.describe())
+		}
+		op = "not in"
+	default:
+		return nil, p.errorf(opTok, "expected an operator after %s but found %s", name.text, opTok.describe())
+	}
+	if !allowed(f.kind, op) {
+		return nil, p.errorf(opTok, "operator %s cannot be used with %s (use %s)", op, f.name, strings.Join(operators[f.kind], ", "))
+	}
+
+	c := &Comparison{Field: f.name, Op: op, Pos: name.pos}
+	for {
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		if err := checkValue(f, v); err != nil {
+			return nil, &Error{p.src, v.Pos, err.Error()}
+		}
+		c.Values = append(c.Values, v)
+		if p.peek().kind != tokComma {
+			break
+		}
+		comma := p.next()
+		if op != "in" && op != "not in" {
+			return nil, p.errorf(comma, "a list of values needs the in operator")
+		}
+	}
+	return c, nil
+}
+
+// parseValue reads a quoted string or a run of words, so that multi-word
+// values such as "next friday" need no quotes.
+func (p *parser) parseValue() (Value, error) {
+	t := p.peek()
+	switch t.kind {
+	case tokString:
+		p.next()
+		return Value{Text: t.text, Pos: t.pos}, nil
+	case tokWord:
+		words := []string{p.next().text}
+		for p.peek().kind == tokWord {
+			words = append(words, p.next().text)
+		}
+		return Value{Text: strings.Join(words, " "), Pos: t.pos}, nil
+	}
+	return Value{}, p.errorf(t, "expected a value but found %s", t.describe())
+}
+
+func allowed(k kind, op string) bool {
+	for _, o := range operators[k] {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+// checkValue rejects values that can never be interpreted for the field.
+func checkValue(f field, v Value) error {
+	switch f.kind {
+	case kindBool:
+		if _, err := parseBool(v.Text); err != nil {
+			return err
+		}
+	case kindNumber:
+		if _, err := strconv.ParseFloat(v.Text, 64); err != nil {
+			return fmt.Errorf("%s expects a number, not %q", f.name, v.Text)
+		}
+	case kindDate:
+		if _, err := dateparse.Parse(v.Text); err != nil {
+			return fmt.Errorf("%s expects a date: %v", f.name, err)
+		}
+	}
+	return nil
+}
+
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "true", "yes", "1":
+		return true, nil
+	case "false", "no", "0":
+		return false, nil
+	}
+	return false, fmt.Errorf("expected true or false, not %q", s)
+}
+
+// dateValue resolves a date operand relative to now. day reports whether
+// the value names a whole day (midnight), which makes = and the range
+// operators compare against the full day.
+func dateValue(text string, now time.Time) (t time.Time, day bool, err error) {
+	t, err = dateparse.Parser{Now: func() time.Time { return now }}.Parse(text)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	y, m, d := t.Date()
+	day = t.Equal(time.Date(y, m, d, 0, 0, 0, 0, t.Location())) && !strings.HasPrefix(strings.ToLower(text), "now")
+	return t, day, nil
+}
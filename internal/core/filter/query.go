@@ -0,0 +1,94 @@
+package filter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"kunja/internal/core"
+)
+
+// Query answers a task collection request without a server, for backends
+// that hold the tasks themselves: it applies the filter query (in the syntax
+// Compile produces), the search term and the sort order of params and
+// returns all matching tasks. The legacy filter_by parameters are ignored;
+// paging is left to Page so the caller can report the total first.
+func Query(tasks []core.Task, params core.GetAllTasksParams, env Env) ([]core.Task, error) {
+	tasks = append([]core.Task(nil), tasks...)
+	if strings.TrimSpace(params.Filter) != "" {
+		expr, err := Parse(params.Filter)
+		if err != nil {
+			return nil, fmt.Errorf("filter: %w", err)
+		}
+		if tasks, err = Filter(expr, tasks, env); err != nil {
+			return nil, err
+		}
+	}
+	if s := strings.ToLower(params.S); s != "" {
+		matched := tasks[:0]
+		for _, t := range tasks {
+			if strings.Contains(strings.ToLower(t.Title), s) || strings.Contains(strings.ToLower(t.Description), s) {
+				matched = append(matched, t)
+			}
+		}
+		tasks = matched
+	}
+	sortTasks(tasks, params.SortBy, params.OrderBy == "desc")
+	return tasks, nil
+}
+
+// Page returns the given page (counted from 1) of tasks; perPage <= 0
+// returns all of them.
+func Page(tasks []core.Task, page, perPage int) []core.Task {
+	if perPage <= 0 {
+		return tasks
+	}
+	if page < 1 {
+		page = 1
+	}
+	start := (page - 1) * perPage
+	if start > len(tasks) {
+		start = len(tasks)
+	}
+	end := start + perPage
+	if end > len(tasks) {
+		end = len(tasks)
+	}
+	return tasks[start:end]
+}
+
+// sortTasks orders tasks by one of Vikunja's sort_by fields, by ID when the
+// field is empty or unknown.
+func sortTasks(tasks []core.Task, field string, desc bool) {
+	less := func(a, b core.Task) bool {
+		switch field {
+		case "title":
+			return strings.ToLower(a.Title) < strings.ToLower(b.Title)
+		case "priority":
+			return a.Priority < b.Priority
+		case "due_date":
+			return a.DueDate.Before(b.DueDate)
+		case "start_date":
+			return a.StartDate.Before(b.StartDate)
+		case "end_date":
+			return a.EndDate.Before(b.EndDate)
+		case "done_at":
+			return a.DoneAt.Before(b.DoneAt)
+		case "created":
+			return a.Created.Before(b.Created)
+		case "updated":
+			return a.Updated.Before(b.Updated)
+		case "percent_done":
+			return a.PercentDone < b.PercentDone
+		case "position":
+			return a.Position < b.Position
+		}
+		return a.ID < b.ID
+	}
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if desc {
+			return less(tasks[j], tasks[i])
+		}
+		return less(tasks[i], tasks[j])
+	})
+}
@@ -3,17 +3,33 @@ package core
 import "time"
 
 type Bucket struct {
-	ID           int    `json:"id"`
-	Title        string `json:"title"`
-	IsDoneBucket bool   `json:"is_done_bucket"`
-	Limit        int    `json:"limit"`
-	Position     int    `json:"position"`
-	CountTasks   int    `json:"count_tasks"`
+	ID            int     `json:"id"`
+	Title         string  `json:"title"`
+	ProjectViewID int     `json:"project_view_id,omitempty"`
+	IsDoneBucket  bool    `json:"is_done_bucket"`
+	Limit         int     `json:"limit"`
+	Position      float64 `json:"position"`
+	CountTasks    int     `json:"count_tasks"`
+	Tasks         []Task  `json:"tasks,omitempty"`
+}
+
+// ProjectView is one of the views (list, gantt, table, kanban) configured
+// for a project. Buckets always belong to a kanban view.
+type ProjectView struct {
+	ID              int     `json:"id"`
+	Title           string  `json:"title"`
+	ProjectID       int     `json:"project_id"`
+	ViewKind        string  `json:"view_kind"`
+	Position        float64 `json:"position"`
+	DoneBucketID    int     `json:"done_bucket_id"`
+	DefaultBucketID int     `json:"default_bucket_id"`
 }
 
 type Label struct {
-	ID    int    `json:"id"`
-	Title string `json:"title"`
+	ID          int    `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	HexColor    string `json:"hex_color,omitempty"`
 }
 
 type TaskReminder struct {
@@ -38,6 +54,8 @@ type Task struct {
 	Done           bool           `json:"done"`
 	DoneAt         time.Time      `json:"done_at"`
 	Labels         []Label        `json:"labels"`
+	Assignees      []User         `json:"assignees,omitempty"`
+	RelatedTasks   RelatedTaskMap `json:"related_tasks,omitempty"`
 	ProjectID      int            `json:"project_id,omitempty"`
 	Position       float64        `json:"position"`
 	BucketID       int            `json:"bucket_id"`
@@ -58,6 +76,9 @@ type GetAllTasksParams struct {
 	FilterComparator   string `json:"filter_comparator,omitempty"   url:"filter_comparator,omitempty"`
 	FilterConcat       string `json:"filter_concat,omitempty"       url:"filter_concat,omitempty"`
 	FilterIncludeNulls string `json:"filter_include_nulls,omitempty" url:"filter_include_nulls,omitempty"`
+	// Filter is a filter query such as "done = false && priority >= 3",
+	// supported since Vikunja 0.24 in place of the FilterBy fields.
+	Filter string `json:"filter,omitempty" url:"filter,omitempty"`
 }
 
 type Project struct {
@@ -78,6 +99,33 @@ type Project struct {
 	HexColor              string    `json:"hex_color,omitempty"`
 }
 
+// TaskComment is a single comment left on a task.
+type TaskComment struct {
+	ID      int       `json:"id"`
+	Comment string    `json:"comment"`
+	Author  User      `json:"author"`
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+}
+
+// File describes the stored file behind a task attachment.
+type File struct {
+	ID      int       `json:"id"`
+	Name    string    `json:"name"`
+	Mime    string    `json:"mime"`
+	Size    int64     `json:"size"`
+	Created time.Time `json:"created"`
+}
+
+// TaskAttachment links an uploaded file to a task.
+type TaskAttachment struct {
+	ID        int       `json:"id"`
+	TaskID    int       `json:"task_id"`
+	CreatedBy User      `json:"created_by"`
+	File      File      `json:"file"`
+	Created   time.Time `json:"created"`
+}
+
 type UserWithRight struct {
 	ID       int    `json:"id"`
 	Username string `json:"username"`
@@ -90,3 +138,33 @@ type User struct {
 	Name             string `json:"name"`
 	DefaultProjectID int    `json:"default_project_id"`
 }
+
+// SavedFilter is a filter query stored on the server under a title. Vikunja
+// lists saved filters among the projects, as pseudo projects with negative
+// IDs; see SavedFilterID.
+type SavedFilter struct {
+	ID          int              `json:"id"`
+	Title       string           `json:"title"`
+	Description string           `json:"description"`
+	Filters     SavedFilterQuery `json:"filters"`
+	IsFavorite  bool             `json:"is_favorite"`
+	Owner       User             `json:"owner"`
+	Created     time.Time        `json:"created"`
+	Updated     time.Time        `json:"updated"`
+}
+
+// SavedFilterQuery holds the task collection parameters of a saved filter.
+type SavedFilterQuery struct {
+	Filter             string   `json:"filter"`
+	FilterIncludeNulls bool     `json:"filter_include_nulls"`
+	S                  string   `json:"s,omitempty"`
+	SortBy             []string `json:"sort_by,omitempty"`
+	OrderBy            []string `json:"order_by,omitempty"`
+}
+
+// SavedFilterID returns the ID of the saved filter behind a pseudo project
+// ID, or ok=false for regular projects and the favorites pseudo project (-1).
+func SavedFilterID(projectID int) (id int, ok bool) {
+	id = -projectID - 1
+	return id, id > 0
+}
@@ -0,0 +1,80 @@
+package core
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"kunja/internal/core/dateparse"
+)
+
+// QuickAdd is the result of parsing a task title written in Vikunja's
+// quick-add magic syntax. Names are returned unresolved; mapping them to
+// label, project and user IDs is left to the caller.
+type QuickAdd struct {
+	Title     string
+	Labels    []string
+	Project   string
+	Priority  int
+	Assignees []string
+	DueDate   time.Time
+}
+
+// MaxPriority is the highest priority Vikunja knows ("DO NOW").
+const MaxPriority = 5
+
+// quickAddToken matches a prefixed token (*label, +project, !priority,
+// @user) at the start of the input or after whitespace. Values containing
+// spaces can be quoted: *"needs review".
+var quickAddToken = regexp.MustCompile(`(^|\s)([*+!@])("[^"]+"|'[^']+'|\S+)`)
+
+// ParseQuickAdd extracts labels, project, priority, assignees and a due date
+// phrase from input. now anchors relative dates such as "tomorrow"; the
+// remaining text becomes the title.
+func ParseQuickAdd(input string, now time.Time) QuickAdd {
+	var q QuickAdd
+
+	rest := quickAddToken.ReplaceAllStringFunc(input, func(m string) string {
+		sub := quickAddToken.FindStringSubmatch(m)
+		lead, prefix, value := sub[1], sub[2], unquote(sub[3])
+		switch prefix {
+		case "*":
+			q.Labels = append(q.Labels, value)
+		case "+":
+			if q.Project != "" {
+				return m // only the first project counts
+			}
+			q.Project = value
+		case "!":
+			p, err := strconv.Atoi(value)
+			if err != nil || p < 1 {
+				return m // not a priority, e.g. "!important"
+			}
+			if p > MaxPriority {
+				p = MaxPriority
+			}
+			q.Priority = p
+		case "@":
+			q.Assignees = append(q.Assignees, value)
+		}
+		return lead
+	})
+
+	// Dates without an explicit time default to 12:00, as in Vikunja's quick add.
+	dates := dateparse.Parser{Now: func() time.Time { return now }, DefaultHour: 12}
+	if due, remaining, ok := dates.Extract(rest); ok {
+		q.DueDate = due
+		rest = remaining
+	}
+
+	q.Title = strings.Join(strings.Fields(rest), " ")
+	return q
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,47 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseQuickAdd(t *testing.T) {
+	now := time.Date(2026, time.October, 14, 10, 30, 0, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want QuickAdd
+	}{
+		{
+			in: `Buy milk *shopping *"needs review" +Home !3 @alice tomorrow`,
+			want: QuickAdd{
+				Title:     "Buy milk",
+				Labels:    []string{"shopping", "needs review"},
+				Project:   "Home",
+				Priority:  3,
+				Assignees: []string{"alice"},
+				DueDate:   time.Date(2026, time.October, 15, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			in:   "Call Bob next friday 9am !9",
+			want: QuickAdd{Title: "Call Bob", Priority: MaxPriority, DueDate: time.Date(2026, time.October, 23, 9, 0, 0, 0, time.UTC)},
+		},
+		{
+			in:   "Fix login bug *backend +Website !4 friday",
+			want: QuickAdd{Title: "Fix login bug", Labels: []string{"backend"}, Project: "Website", Priority: 4, DueDate: time.Date(2026, time.October, 16, 12, 0, 0, 0, time.UTC)},
+		},
+		{in: "Fix it now", want: QuickAdd{Title: "Fix it now"}},
+		{in: "Fix bug that may 3 people hit", want: QuickAdd{Title: "Fix bug that may 3 people hit"}},
+		{in: "Report for last week", want: QuickAdd{Title: "Report for last week"}},
+		{in: "Write 2 days ago notes", want: QuickAdd{Title: "Write 2 days ago notes"}},
+		{in: "Read !important +a +b", want: QuickAdd{Title: "Read !important +b", Project: "a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := ParseQuickAdd(tt.in, now); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseQuickAdd(%q) = %+v, want %+v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RelationKind is the kind of a relation between two tasks as used by
+// Vikunja's related_tasks map and relation endpoints.
+type RelationKind string
+
+const (
+	RelationSubtask     RelationKind = "subtask"
+	RelationParentTask  RelationKind = "parenttask"
+	RelationRelated     RelationKind = "related"
+	RelationDuplicateOf RelationKind = "duplicateof"
+	RelationDuplicates  RelationKind = "duplicates"
+	RelationBlocking    RelationKind = "blocking"
+	RelationBlocked     RelationKind = "blocked"
+	RelationPrecedes    RelationKind = "precedes"
+	RelationFollows     RelationKind = "follows"
+	RelationCopiedFrom  RelationKind = "copiedfrom"
+	RelationCopiedTo    RelationKind = "copiedto"
+)
+
+// RelationKinds lists all kinds in the order they are rendered.
+var RelationKinds = []RelationKind{
+	RelationParentTask,
+	RelationSubtask,
+	RelationBlocked,
+	RelationBlocking,
+	RelationPrecedes,
+	RelationFollows,
+	RelationDuplicateOf,
+	RelationDuplicates,
+	RelationRelated,
+	RelationCopiedFrom,
+	RelationCopiedTo,
+}
+
+// relationAliases maps normalised user input to a relation kind.
+var relationAliases = map[string]RelationKind{
+	"subtask":     RelationSubtask,
+	"sub":         RelationSubtask,
+	"child":       RelationSubtask,
+	"parenttask":  RelationParentTask,
+	"parent":      RelationParentTask,
+	"related":     RelationRelated,
+	"relates":     RelationRelated,
+	"relatesto":   RelationRelated,
+	"duplicateof": RelationDuplicateOf,
+	"duplicate":   RelationDuplicateOf,
+	"dup":         RelationDuplicateOf,
+	"duplicates":  RelationDuplicates,
+	"blocking":    RelationBlocking,
+	"blocks":      RelationBlocking,
+	"blocked":     RelationBlocked,
+	"blockedby":   RelationBlocked,
+	"precedes":    RelationPrecedes,
+	"before":      RelationPrecedes,
+	"follows":     RelationFollows,
+	"after":       RelationFollows,
+	"copiedfrom":  RelationCopiedFrom,
+	"copiedto":    RelationCopiedTo,
+}
+
+// ParseRelationKind converts user input such as "blocked-by" or "sub" into
+// a RelationKind.
+func ParseRelationKind(s string) (RelationKind, error) {
+	norm := strings.NewReplacer("-", "", "_", "", " ", "").Replace(strings.ToLower(strings.TrimSpace(s)))
+	if k, ok := relationAliases[norm]; ok {
+		return k, nil
+	}
+	var valid []string
+	for _, k := range RelationKinds {
+		valid = append(valid, string(k))
+	}
+	return "", fmt.Errorf("unknown relation kind %q (use one of %s)", s, strings.Join(valid, ", "))
+}
+
+// Label returns a human readable description of the kind as seen from the
+// task that holds the relation, e.g. "blocked by".
+func (k RelationKind) Label() string {
+	switch k {
+	case RelationSubtask:
+		return "subtasks"
+	case RelationParentTask:
+		return "parent"
+	case RelationDuplicateOf:
+		return "duplicate of"
+	case RelationBlocking:
+		return "blocks"
+	case RelationBlocked:
+		return "blocked by"
+	case RelationCopiedFrom:
+		return "copied from"
+	case RelationCopiedTo:
+		return "copied to"
+	default:
+		return string(k)
+	}
+}
+
+// Describe states a relation of the kind from task to other as a sentence,
+// e.g. "task 10 is a subtask of task 12" for a subtask relation from task
+// 12 to task 10.
+func (k RelationKind) Describe(task, other int) string {
+	switch k {
+	case RelationSubtask:
+		return fmt.Sprintf("task %d is a subtask of task %d", other, task)
+	case RelationParentTask:
+		return fmt.Sprintf("task %d is the parent task of task %d", other, task)
+	case RelationDuplicateOf:
+		return fmt.Sprintf("task %d is a duplicate of task %d", task, other)
+	case RelationDuplicates:
+		return fmt.Sprintf("task %d is a duplicate of task %d", other, task)
+	case RelationBlocking:
+		return fmt.Sprintf("task %d blocks task %d", task, other)
+	case RelationBlocked:
+		return fmt.Sprintf("task %d is blocked by task %d", task, other)
+	case RelationPrecedes:
+		return fmt.Sprintf("task %d precedes task %d", task, other)
+	case RelationFollows:
+		return fmt.Sprintf("task %d follows task %d", task, other)
+	case RelationCopiedFrom:
+		return fmt.Sprintf("task %d was copied from task %d", task, other)
+	case RelationCopiedTo:
+		return fmt.Sprintf("task %d was copied to task %d", task, other)
+	case RelationRelated:
+		return fmt.Sprintf("task %d is related to task %d", task, other)
+	default:
+		return fmt.Sprintf("task %d and task %d are related (%s)", task, other, k)
+	}
+}
+
+// RelatedTaskMap holds the related tasks of a task grouped by kind.
+type RelatedTaskMap map[RelationKind][]Task
+
+// TaskRelation is a single directed relation between two tasks.
+type TaskRelation struct {
+	TaskID       int          `json:"task_id"`
+	OtherTaskID  int          `json:"other_task_id"`
+	RelationKind RelationKind `json:"relation_kind"`
+	Created      time.Time    `json:"created"`
+}
+
+// IsBlocked reports whether the task is blocked by at least one open task.
+func (task *Task) IsBlocked() bool {
+	for _, other := range task.RelatedTasks[RelationBlocked] {
+		if !other.Done {
+			return true
+		}
+	}
+	return false
+}
+
+// IsBlocking reports whether the task blocks at least one open task.
+func (task *Task) IsBlocking() bool {
+	for _, other := range task.RelatedTasks[RelationBlocking] {
+		if !other.Done {
+			return true
+		}
+	}
+	return false
+}
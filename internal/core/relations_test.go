@@ -0,0 +1,22 @@
+package core
+
+import "testing"
+
+func TestRelationKindDescribe(t *testing.T) {
+	tests := []struct {
+		kind RelationKind
+		want string
+	}{
+		{RelationSubtask, "task 10 is a subtask of task 12"},
+		{RelationParentTask, "task 10 is the parent task of task 12"},
+		{RelationBlocked, "task 12 is blocked by task 10"},
+		{RelationBlocking, "task 12 blocks task 10"},
+		{RelationDuplicateOf, "task 12 is a duplicate of task 10"},
+		{RelationDuplicates, "task 10 is a duplicate of task 12"},
+	}
+	for _, tt := range tests {
+		if got := tt.kind.Describe(12, 10); got != tt.want {
+			t.Errorf("%s.Describe(12, 10) = %q, want %q", tt.kind, got, tt.want)
+		}
+	}
+}
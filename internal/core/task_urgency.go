@@ -1,41 +1,160 @@
 package core
 
-import "time"
+import (
+	"strconv"
+	"strings"
+	"time"
+)
 
-// CalculateUrgency computes the urgency score of the task according to the
-// Vikunja formula.  It is attached to *Task here (the type owner) so that the
-// method set is available through the api.Task alias as well.
-func (task *Task) CalculateUrgency() {
+// UrgencyModel holds the coefficients of the urgency score, in the spirit of
+// Taskwarrior: every term is a factor derived from the task multiplied by a
+// coefficient, and the urgency is the sum of all terms. The zero value of a
+// coefficient disables its term.
+//
+// The model is loaded from the "urgency" section of config.yaml, e.g.
+//
+//	urgency:
+//	  due: 2
+//	  age: 1.5
+//	  blocked: -5
+//	  labels:
+//	    urgent: 4
+//	  projects:
+//	    "12": -2
+type UrgencyModel struct {
+	// Base is added to every open task so that unfinished, no-priority tasks
+	// are still sorted ahead of completed ones (which score 0).
+	Base float64 `mapstructure:"base" json:"base"`
+	// Due weighs the due date proximity score: 6 when overdue, 5 due today,
+	// down to -1 for more than two weeks away.
+	Due float64 `mapstructure:"due" json:"due"`
+	// Priority weighs the task priority (0–5).
+	Priority float64 `mapstructure:"priority" json:"priority"`
+	// Favorite applies to tasks marked as favorite.
+	Favorite float64 `mapstructure:"favorite" json:"favorite"`
+	// Age weighs the task age relative to AgeMax, capped at 1.
+	Age float64 `mapstructure:"age" json:"age"`
+	// AgeMax is the age in days at which the age factor reaches 1.
+	AgeMax float64 `mapstructure:"age_max" json:"age_max"`
+	// Labels maps label titles (case-insensitive) to their weight.
+	Labels map[string]float64 `mapstructure:"labels" json:"labels,omitempty"`
+	// Projects maps project IDs to their weight.
+	Projects map[string]float64 `mapstructure:"projects" json:"projects,omitempty"`
+	// Started applies to tasks whose start date has passed.
+	Started float64 `mapstructure:"started" json:"started"`
+	// Blocked applies to tasks blocked by an open task.
+	Blocked float64 `mapstructure:"blocked" json:"blocked"`
+	// Blocking applies to tasks that block an open task.
+	Blocking float64 `mapstructure:"blocking" json:"blocking"`
+	// PercentDone weighs the completed fraction of the task (0–1).
+	PercentDone float64 `mapstructure:"percent_done" json:"percent_done"`
+}
+
+// DefaultUrgencyModel returns the model matching Vikunja's own formula,
+// 1 + due score + priority + favorite. The remaining terms are disabled.
+func DefaultUrgencyModel() UrgencyModel {
+	return UrgencyModel{
+		Base:     1,
+		Due:      1,
+		Priority: 1,
+		Favorite: 1,
+		AgeMax:   365,
+	}
+}
+
+// UrgencyTerm is one line of an urgency breakdown: Factor is derived from
+// the task, Coefficient from the model and Score is their product.
+type UrgencyTerm struct {
+	Name        string  `json:"name"`
+	Factor      float64 `json:"factor"`
+	Coefficient float64 `json:"coefficient"`
+	Score       float64 `json:"score"`
+}
+
+// Explain returns the non-zero terms making up the urgency of task at now.
+// Done tasks have no terms.
+func (m UrgencyModel) Explain(task Task, now time.Time) []UrgencyTerm {
 	if task.Done {
-		task.Urgency = 0
-		return
+		return nil
 	}
 
-	dueDateScore := float64(task.getDueDateScore())
-	priorityScore := float64(task.Priority)
+	var terms []UrgencyTerm
+	add := func(name string, factor, coefficient float64) {
+		if factor == 0 || coefficient == 0 {
+			return
+		}
+		terms = append(terms, UrgencyTerm{Name: name, Factor: factor, Coefficient: coefficient, Score: factor * coefficient})
+	}
 
-	favoriteScore := 0.0
+	add("base", 1, m.Base)
+	add("due", float64(task.dueDateScore(now)), m.Due)
+	add("priority", float64(task.Priority), m.Priority)
 	if task.IsFavorite {
-		favoriteScore = 1
+		add("favorite", 1, m.Favorite)
+	}
+	if m.AgeMax > 0 && !task.Created.IsZero() {
+		age := now.Sub(task.Created).Hours() / 24 / m.AgeMax
+		if age > 1 {
+			age = 1
+		}
+		if age > 0 {
+			add("age", age, m.Age)
+		}
+	}
+	for _, l := range task.Labels {
+		if w, ok := m.Labels[strings.ToLower(l.Title)]; ok {
+			add("label:"+l.Title, 1, w)
+		}
 	}
+	if w, ok := m.Projects[strconv.Itoa(task.ProjectID)]; ok && task.ProjectID != 0 {
+		add("project:"+strconv.Itoa(task.ProjectID), 1, w)
+	}
+	if !task.StartDate.IsZero() && !task.StartDate.After(now) {
+		add("started", 1, m.Started)
+	}
+	if task.IsBlocked() {
+		add("blocked", 1, m.Blocked)
+	}
+	if task.IsBlocking() {
+		add("blocking", 1, m.Blocking)
+	}
+	add("percent_done", task.PercentDone, m.PercentDone)
+	return terms
+}
+
+// Score returns the urgency of task at now, the sum of its terms.
+func (m UrgencyModel) Score(task Task, now time.Time) float64 {
+	total := 0.0
+	for _, t := range m.Explain(task, now) {
+		total += t.Score
+	}
+	return total
+}
+
+// CalculateUrgency computes the urgency score of the task according to the
+// default (Vikunja) model. It is attached to *Task here (the type owner) so
+// that the method set is available through the api.Task alias as well.
+func (task *Task) CalculateUrgency() {
+	task.CalculateUrgencyWith(DefaultUrgencyModel())
+}
 
-	// Base 1.0 is added so that unfinished, no-priority tasks are still sorted
-	// ahead of completed ones (which are set to 0).
-	task.Urgency = 1 + dueDateScore + priorityScore + favoriteScore
+// CalculateUrgencyWith computes the urgency score of the task using m.
+func (task *Task) CalculateUrgencyWith(m UrgencyModel) {
+	task.Urgency = m.Score(*task, time.Now())
 }
 
-// getDueDateScore converts the remaining days until due-date into the score
+// dueDateScore converts the remaining days until due-date into the score
 // defined by Vikunja’s web client.
-func (task *Task) getDueDateScore() int {
+func (task *Task) dueDateScore(now time.Time) int {
 	if task.DueDate.IsZero() {
 		return 0
 	}
 
-	dueDays := int(task.DueDate.Sub(time.Now()).Hours() / 24)
+	dueDays := int(task.DueDate.Sub(now).Hours() / 24)
 
 	switch {
 	case dueDays < 0:
-		return 6    // overdue
+		return 6 // overdue
 	case dueDays == 0:
 		return 5
 	case dueDays == 1:
@@ -47,7 +166,7 @@ func (task *Task) getDueDateScore() int {
 	case dueDays <= 10:
 		return 1
 	case dueDays > 14:
-		return -1   // “Someday”
+		return -1 // “Someday”
 	default: // 11-14 days
 		return 0
 	}
@@ -0,0 +1,258 @@
+// Package render writes command results in the output formats selected with
+// the global --output flag. Every listing command describes its result once,
+// as the raw value (for json, yaml and template) plus a Table (for table, csv
+// and tsv), and leaves the formatting to Options.Render.
+package render
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format is an output format name as accepted by --output.
+type Format string
+
+const (
+	FormatTable    Format = "table"
+	FormatJSON     Format = "json"
+	FormatYAML     Format = "yaml"
+	FormatCSV      Format = "csv"
+	FormatTSV      Format = "tsv"
+	FormatTemplate Format = "template"
+)
+
+// Formats lists all supported formats in the order they are documented.
+var Formats = []Format{FormatTable, FormatJSON, FormatYAML, FormatCSV, FormatTSV, FormatTemplate}
+
+// ParseFormat validates a format name; the empty string selects FormatTable.
+func ParseFormat(s string) (Format, error) {
+	if s == "" {
+		return FormatTable, nil
+	}
+	for _, f := range Formats {
+		if strings.EqualFold(s, string(f)) {
+			return f, nil
+		}
+	}
+	names := make([]string, len(Formats))
+	for i, f := range Formats {
+		names[i] = string(f)
+	}
+	return "", fmt.Errorf("unknown output format %q (use one of %s)", s, strings.Join(names, ", "))
+}
+
+// Options selects how a result is rendered. Template is the text/template
+// source used with FormatTemplate.
+type Options struct {
+	Format   Format
+	Template string
+}
+
+// NewOptions builds Options from the --output and --template flag values.
+// tmpl is either template source or the path of a file containing it; a
+// template without an explicit format implies FormatTemplate, and one
+// given with any other format is an error.
+func NewOptions(format, tmpl string) (Options, error) {
+	if format == "" && tmpl != "" {
+		format = string(FormatTemplate)
+	}
+	f, err := ParseFormat(format)
+	if err != nil {
+		return Options{}, err
+	}
+	opts := Options{Format: f, Template: tmpl}
+	if f != FormatTemplate {
+		if tmpl != "" {
+			return Options{}, fmt.Errorf("--template requires --output template, not %s", f)
+		}
+		return opts, nil
+	}
+	if tmpl == "" {
+		return Options{}, fmt.Errorf("--output template requires --template")
+	}
+	if info, err := os.Stat(tmpl); err == nil && !info.IsDir() {
+		data, err := os.ReadFile(tmpl)
+		if err != nil {
+			return Options{}, err
+		}
+		opts.Template = string(data)
+	}
+	return opts, nil
+}
+
+// Tabular reports whether the format is rendered from the Table rather than
+// from the raw value. Commands use it to add human-only decorations such as
+// a result count.
+func (o Options) Tabular() bool {
+	return o.Format == "" || o.Format == FormatTable
+}
+
+// Table is the row/column view of a result.
+type Table struct {
+	Header []string
+	Rows   [][]string
+}
+
+// AddRow appends a row, formatting each cell with fmt.Sprint.
+func (t *Table) AddRow(cells ...interface{}) {
+	row := make([]string, len(cells))
+	for i, c := range cells {
+		row[i] = fmt.Sprint(c)
+	}
+	t.Rows = append(t.Rows, row)
+}
+
+// Render writes v (or its Table view) to w in the selected format.
+func (o Options) Render(w io.Writer, v interface{}, t Table) error {
+	switch o.Format {
+	case "", FormatTable:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		if len(t.Header) > 0 {
+			fmt.Fprintln(tw, strings.Join(t.Header, "\t"))
+		}
+		for _, row := range t.Rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+	case FormatJSON:
+		pretty, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(pretty))
+		return err
+	case FormatYAML:
+		// Go through JSON so that YAML keys match the JSON field names.
+		generic, err := toGeneric(v)
+		if err != nil {
+			return err
+		}
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(generic); err != nil {
+			return err
+		}
+		return enc.Close()
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		if len(t.Header) > 0 {
+			cw.Write(t.Header)
+		}
+		cw.WriteAll(t.Rows)
+		return cw.Error()
+	case FormatTSV:
+		clean := strings.NewReplacer("\t", " ", "\r", " ", "\n", " ")
+		for _, row := range append([][]string{t.Header}, t.Rows...) {
+			if len(row) == 0 {
+				continue
+			}
+			cells := make([]string, len(row))
+			for i, c := range row {
+				cells[i] = clean.Replace(c)
+			}
+			if _, err := fmt.Fprintln(w, strings.Join(cells, "\t")); err != nil {
+				return err
+			}
+		}
+		return nil
+	case FormatTemplate:
+		tmpl, err := template.New("output").Funcs(funcs).Parse(o.Template)
+		if err != nil {
+			return fmt.Errorf("invalid template: %w", err)
+		}
+		generic, err := toGeneric(v)
+		if err != nil {
+			return err
+		}
+		return tmpl.Execute(w, generic)
+	}
+	return fmt.Errorf("unknown output format %q", o.Format)
+}
+
+// String is Render into a string, for callers such as the MCP tools that
+// return the output rather than print it.
+func (o Options) String(v interface{}, t Table) (string, error) {
+	var buf bytes.Buffer
+	if err := o.Render(&buf, v, t); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// toGeneric converts v into maps and slices keyed by its JSON field names,
+// so templates and YAML use the same names as the JSON output.
+func toGeneric(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&generic); err != nil {
+		return nil, err
+	}
+	return convertNumbers(generic), nil
+}
+
+// convertNumbers replaces json.Number values with int64 or float64, so IDs
+// print as 1234567 rather than 1.234567e+06 and YAML does not quote them.
+func convertNumbers(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = convertNumbers(e)
+		}
+	case []interface{}:
+		for i, e := range v {
+			v[i] = convertNumbers(e)
+		}
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	}
+	return v
+}
+
+// funcs are the helpers available to --template in addition to the
+// text/template builtins.
+var funcs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"join":  func(sep string, items []interface{}) string { return joinAny(items, sep) },
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	// date reformats an RFC 3339 timestamp, e.g. {{date "2006-01-02" .due_date}};
+	// Vikunja's zero date renders as an empty string.
+	"date": func(layout string, v interface{}) string {
+		s, _ := v.(string)
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil || t.Year() <= 1 {
+			return ""
+		}
+		return t.Format(layout)
+	},
+}
+
+func joinAny(items []interface{}, sep string) string {
+	parts := make([]string, len(items))
+	for i, it := range items {
+		parts[i] = fmt.Sprint(it)
+	}
+	return strings.Join(parts, sep)
+}
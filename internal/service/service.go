@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"io"
 
 	"kunja/api"
 )
@@ -36,3 +37,61 @@ type ProjectService interface {
 type UserService interface {
 	GetAllUsers(ctx context.Context) ([]api.User, error)
 }
+
+// LabelService defines label related operations.
+type LabelService interface {
+	GetAllLabels(ctx context.Context) ([]api.Label, error)
+	GetLabel(ctx context.Context, id int) (api.Label, error)
+	CreateLabel(ctx context.Context, l api.Label) (api.Label, error)
+	UpdateLabel(ctx context.Context, id int, l api.Label) (api.Label, error)
+	DeleteLabel(ctx context.Context, id int) (string, error)
+
+	GetTaskLabels(ctx context.Context, taskID int) ([]api.Label, error)
+	AddLabelToTask(ctx context.Context, taskID, labelID int) (string, error)
+	RemoveLabelFromTask(ctx context.Context, taskID, labelID int) (string, error)
+}
+
+// BucketService defines kanban bucket related operations. Buckets belong to a
+// kanban view of a project, so every call is scoped by project and view ID.
+type BucketService interface {
+	GetProjectViews(ctx context.Context, projectID int) ([]api.ProjectView, error)
+	GetBuckets(ctx context.Context, projectID, viewID int) ([]api.Bucket, error)
+	GetBucketTasks(ctx context.Context, projectID, viewID int) ([]api.Bucket, error)
+	CreateBucket(ctx context.Context, projectID, viewID int, b api.Bucket) (api.Bucket, error)
+	UpdateBucket(ctx context.Context, projectID, viewID, bucketID int, b api.Bucket) (api.Bucket, error)
+	DeleteBucket(ctx context.Context, projectID, viewID, bucketID int) (string, error)
+	MoveTaskToBucket(ctx context.Context, projectID, viewID, bucketID, taskID int) (string, error)
+}
+
+// CommentService defines task comment related operations.
+type CommentService interface {
+	GetTaskComments(ctx context.Context, taskID int) ([]api.TaskComment, error)
+	GetTaskComment(ctx context.Context, taskID, commentID int) (api.TaskComment, error)
+	CreateTaskComment(ctx context.Context, taskID int, comment string) (api.TaskComment, error)
+	UpdateTaskComment(ctx context.Context, taskID, commentID int, comment string) (api.TaskComment, error)
+	DeleteTaskComment(ctx context.Context, taskID, commentID int) (string, error)
+}
+
+// AttachmentService defines task attachment related operations. Content is
+// passed as streams so implementations never need to buffer whole files.
+type AttachmentService interface {
+	GetTaskAttachments(ctx context.Context, taskID int) ([]api.TaskAttachment, error)
+	UploadTaskAttachment(ctx context.Context, taskID int, filename string, r io.Reader) (api.TaskAttachment, error)
+	DownloadTaskAttachment(ctx context.Context, taskID, attachmentID int, w io.Writer) (int64, error)
+	DeleteTaskAttachment(ctx context.Context, taskID, attachmentID int) (string, error)
+}
+
+// RelationService defines task relation related operations.
+type RelationService interface {
+	CreateTaskRelation(ctx context.Context, taskID, otherTaskID int, kind api.RelationKind) (api.TaskRelation, error)
+	DeleteTaskRelation(ctx context.Context, taskID, otherTaskID int, kind api.RelationKind) (string, error)
+}
+
+// FilterService defines saved filter related operations.
+type FilterService interface {
+	GetSavedFilters(ctx context.Context) ([]api.SavedFilter, error)
+	GetSavedFilter(ctx context.Context, id int) (api.SavedFilter, error)
+	CreateSavedFilter(ctx context.Context, f api.SavedFilter) (api.SavedFilter, error)
+	UpdateSavedFilter(ctx context.Context, id int, f api.SavedFilter) (api.SavedFilter, error)
+	DeleteSavedFilter(ctx context.Context, id int) (string, error)
+}
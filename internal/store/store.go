@@ -0,0 +1,211 @@
+// Package store keeps a local copy of the Vikunja data kunja works with,
+// and the task changes made while the server could not be reached, as JSON
+// files in a directory:
+//
+//	snapshot.json  projects, tasks, labels and users as last seen
+//	queue.json     task mutations waiting to be replayed by `kunja sync`
+//
+// Files are replaced atomically, so a crash never leaves half a snapshot.
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"kunja/api"
+)
+
+// Snapshot is the cached state of the server.
+type Snapshot struct {
+	Projects []api.Project `json:"projects"`
+	Tasks    []api.Task    `json:"tasks"`
+	Labels   []api.Label   `json:"labels"`
+	Users    []api.User    `json:"users"`
+	// Synced is when the snapshot was last replaced by a full sync; zero if
+	// it was only filled by individual reads.
+	Synced time.Time `json:"synced"`
+}
+
+// Task returns the cached task with the given ID.
+func (s *Snapshot) Task(id int) (api.Task, bool) {
+	for _, t := range s.Tasks {
+		if t.ID == id {
+			return t, true
+		}
+	}
+	return api.Task{}, false
+}
+
+// PutTask adds t or replaces the cached task with the same ID.
+func (s *Snapshot) PutTask(t api.Task) {
+	for i := range s.Tasks {
+		if s.Tasks[i].ID == t.ID {
+			s.Tasks[i] = t
+			return
+		}
+	}
+	s.Tasks = append(s.Tasks, t)
+}
+
+// RemoveTask drops the task with the given ID.
+func (s *Snapshot) RemoveTask(id int) {
+	for i := range s.Tasks {
+		if s.Tasks[i].ID == id {
+			s.Tasks = append(s.Tasks[:i], s.Tasks[i+1:]...)
+			return
+		}
+	}
+}
+
+// PutProject adds p or replaces the cached project with the same ID.
+func (s *Snapshot) PutProject(p api.Project) {
+	for i := range s.Projects {
+		if s.Projects[i].ID == p.ID {
+			s.Projects[i] = p
+			return
+		}
+	}
+	s.Projects = append(s.Projects, p)
+}
+
+// LocalID returns an ID for a task created offline. Local IDs are negative
+// so they never collide with the server's; sync replaces them.
+func (s *Snapshot) LocalID() int {
+	id := 0
+	for _, t := range s.Tasks {
+		if t.ID < id {
+			id = t.ID
+		}
+	}
+	return id - 1
+}
+
+// Op is the kind of a queued mutation.
+type Op string
+
+const (
+	OpCreate Op = "create"
+	OpUpdate Op = "update"
+	OpDelete Op = "delete"
+)
+
+// Mutation is a task change made offline.
+type Mutation struct {
+	Op        Op       `json:"op"`
+	TaskID    int      `json:"task_id"`              // local (negative) ID for creates
+	ProjectID int      `json:"project_id,omitempty"` // for creates
+	Task      api.Task `json:"task"`                 // the new state, for creates and updates
+	// Base is the task's Updated time on the server when the change was
+	// made. A task updated on the server after Base has changed since, and
+	// replaying the mutation would overwrite that change.
+	Base   time.Time `json:"base"`
+	Queued time.Time `json:"queued"`
+}
+
+// Store reads and writes the files of one directory. It is safe for use by
+// several goroutines of one process.
+type Store struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// Open returns the store in dir, creating the directory if needed.
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &Store{dir: dir}, nil
+}
+
+// Dir returns the directory of the store.
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Snapshot returns the cached state; an empty snapshot if there is none.
+func (s *Store) Snapshot() (Snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var snap Snapshot
+	err := s.read("snapshot.json", &snap)
+	return snap, err
+}
+
+// Update loads the snapshot, applies fn and saves the result unless fn
+// fails.
+func (s *Store) Update(fn func(*Snapshot) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var snap Snapshot
+	if err := s.read("snapshot.json", &snap); err != nil {
+		return err
+	}
+	if err := fn(&snap); err != nil {
+		return err
+	}
+	return s.write("snapshot.json", snap)
+}
+
+// Queue returns the queued mutations in the order they were made.
+func (s *Store) Queue() ([]Mutation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var q []Mutation
+	err := s.read("queue.json", &q)
+	return q, err
+}
+
+// UpdateQueue loads the queue, applies fn and saves the result unless fn
+// fails.
+func (s *Store) UpdateQueue(fn func([]Mutation) ([]Mutation, error)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var q []Mutation
+	if err := s.read("queue.json", &q); err != nil {
+		return err
+	}
+	q, err := fn(q)
+	if err != nil {
+		return err
+	}
+	return s.write("queue.json", q)
+}
+
+// read decodes the named file into v, leaving v untouched if the file does
+// not exist.
+func (s *Store) read(name string, v interface{}) error {
+	data, err := os.ReadFile(filepath.Join(s.dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// write encodes v into the named file via a temporary file and rename.
+func (s *Store) write(name string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(s.dir, name+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(s.dir, name))
+}
@@ -0,0 +1,139 @@
+package tui
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Key is a key press: a printable character such as "j" or "N", or the name
+// of a special key such as "enter" or "ctrl+c".
+type Key string
+
+// Special keys.
+const (
+	KeyEnter     Key = "enter"
+	KeyEsc       Key = "esc"
+	KeyTab       Key = "tab"
+	KeyBackspace Key = "backspace"
+	KeyDelete    Key = "delete"
+	KeyUp        Key = "up"
+	KeyDown      Key = "down"
+	KeyLeft      Key = "left"
+	KeyRight     Key = "right"
+	KeyHome      Key = "home"
+	KeyEnd       Key = "end"
+	KeyPgUp      Key = "pgup"
+	KeyPgDown    Key = "pgdown"
+	KeySpace     Key = " "
+	KeyCtrlC     Key = "ctrl+c"
+	KeyCtrlU     Key = "ctrl+u"
+)
+
+// Printable reports whether k inserts text, i.e. is a single character.
+func (k Key) Printable() bool {
+	return utf8.RuneCountInString(string(k)) == 1
+}
+
+// escapeKeys maps the escape sequences of xterm-compatible terminals.
+var escapeKeys = map[string]Key{
+	"[A": KeyUp, "[B": KeyDown, "[C": KeyRight, "[D": KeyLeft,
+	"OA": KeyUp, "OB": KeyDown, "OC": KeyRight, "OD": KeyLeft,
+	"[H": KeyHome, "[F": KeyEnd, "OH": KeyHome, "OF": KeyEnd,
+	"[1~": KeyHome, "[4~": KeyEnd, "[3~": KeyDelete,
+	"[5~": KeyPgUp, "[6~": KeyPgDown,
+}
+
+// ReadKey reads one key press from r, which delivers the bytes of a
+// terminal in raw mode. A lone ESC is only recognised as such when no
+// sequence follows in the same read.
+func ReadKey(r *bufio.Reader) (Key, error) {
+	c, size, err := r.ReadRune()
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case c == '\r' || c == '\n':
+		return KeyEnter, nil
+	case c == '\t':
+		return KeyTab, nil
+	case c == 127 || c == 8:
+		return KeyBackspace, nil
+	case c == 3:
+		return KeyCtrlC, nil
+	case c == 21:
+		return KeyCtrlU, nil
+	case c == 27:
+		if r.Buffered() == 0 {
+			return KeyEsc, nil
+		}
+		var seq strings.Builder
+		for r.Buffered() > 0 && seq.Len() < 8 {
+			b, _ := r.ReadByte()
+			seq.WriteByte(b)
+			if k, ok := escapeKeys[seq.String()]; ok {
+				return k, nil
+			}
+			if seq.Len() > 1 && (b >= 'A' && b <= 'Z' || b >= 'a' && b <= 'z' || b == '~') {
+				break
+			}
+		}
+		return "", nil // unknown sequence, ignored by callers
+	case c < 32 || c == utf8.RuneError && size == 1:
+		return "", nil
+	}
+	return Key(string(c)), nil
+}
+
+// ParseScript turns a key script into key presses, for driving a Model
+// without a terminal. Every character is a key of its own, except that
+// names in angle brackets stand for special keys:
+//
+//	jj<enter>nBuy milk<enter><esc>q
+//
+// "<lt>" types a literal "<".
+func ParseScript(script string) ([]Key, error) {
+	var keys []Key
+	for len(script) > 0 {
+		if script[0] == '<' {
+			end := strings.IndexByte(script, '>')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated key name in %q", script)
+			}
+			name := strings.ToLower(script[1:end])
+			switch name {
+			case "lt":
+				keys = append(keys, "<")
+			case "space":
+				keys = append(keys, KeySpace)
+			case "return", "cr":
+				keys = append(keys, KeyEnter)
+			case "escape":
+				keys = append(keys, KeyEsc)
+			case "bs":
+				keys = append(keys, KeyBackspace)
+			default:
+				if !knownKey(Key(name)) {
+					return nil, fmt.Errorf("unknown key <%s>", name)
+				}
+				keys = append(keys, Key(name))
+			}
+			script = script[end+1:]
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(script)
+		keys = append(keys, Key(string(r)))
+		script = script[size:]
+	}
+	return keys, nil
+}
+
+func knownKey(k Key) bool {
+	switch k {
+	case KeyEnter, KeyEsc, KeyTab, KeyBackspace, KeyDelete, KeyUp, KeyDown, KeyLeft, KeyRight,
+		KeyHome, KeyEnd, KeyPgUp, KeyPgDown, KeyCtrlC, KeyCtrlU:
+		return true
+	}
+	return false
+}
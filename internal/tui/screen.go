@@ -0,0 +1,132 @@
+// Package tui is a minimal full-screen terminal toolkit: key decoding, a
+// redraw loop and text layout helpers. Applications implement Model; Run
+// drives a model from a real terminal and Play from a key script, so the
+// same model can be exercised without one.
+package tui
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// Model is a full-screen application.
+type Model interface {
+	// Update handles a key press.
+	Update(k Key)
+	// View draws the screen as lines of at most width characters.
+	View(width, height int) string
+	// Done reports whether the application has quit.
+	Done() bool
+}
+
+// Run drives m from the terminal in until it is done, drawing on out. The
+// terminal is switched to raw mode and the alternate screen for the
+// duration.
+func Run(m Model, in *os.File, out io.Writer) error {
+	fd := int(in.Fd())
+	if !term.IsTerminal(fd) {
+		return errors.New("not a terminal")
+	}
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return err
+	}
+	defer term.Restore(fd, state)
+	fmt.Fprint(out, "\x1b[?1049h\x1b[?25l")
+	defer fmt.Fprint(out, "\x1b[?25h\x1b[?1049l")
+
+	r := bufio.NewReader(in)
+	for !m.Done() {
+		width, height, err := term.GetSize(fd)
+		if err != nil {
+			width, height = 80, 24
+		}
+		draw(out, m.View(width, height))
+		k, err := ReadKey(r)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if k != "" {
+			m.Update(k)
+		}
+	}
+	return nil
+}
+
+// draw repaints the screen from the top left corner.
+func draw(out io.Writer, view string) {
+	var b strings.Builder
+	b.WriteString("\x1b[H")
+	for i, line := range strings.Split(strings.TrimSuffix(view, "\n"), "\n") {
+		if i > 0 {
+			b.WriteString("\r\n")
+		}
+		b.WriteString(line)
+		b.WriteString("\x1b[K")
+	}
+	b.WriteString("\x1b[J")
+	io.WriteString(out, b.String())
+}
+
+// Play feeds keys to m as if typed on a terminal of the given size and
+// returns the screen after the last key, or after the key that made m quit.
+// Like Run, it draws the view before every key, so models that track
+// scrolling or page sizes in View behave the same.
+func Play(m Model, keys []Key, width, height int) string {
+	for _, k := range keys {
+		if m.Done() {
+			break
+		}
+		m.View(width, height)
+		m.Update(k)
+	}
+	return m.View(width, height)
+}
+
+// Fit truncates s to width characters, marking the cut with "…", and pads
+// it with spaces to exactly width.
+func Fit(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	n := utf8.RuneCountInString(s)
+	if n > width {
+		runes := []rune(s)
+		return string(runes[:width-1]) + "…"
+	}
+	return s + strings.Repeat(" ", width-n)
+}
+
+// Screen collects the lines of a view, fitting each to the screen width.
+type Screen struct {
+	Width, Height int
+	lines         []string
+}
+
+// Line appends a line; lines beyond the screen height are dropped.
+func (s *Screen) Line(format string, args ...interface{}) {
+	if len(s.lines) < s.Height {
+		s.lines = append(s.lines, Fit(fmt.Sprintf(format, args...), s.Width))
+	}
+}
+
+// Len returns the number of lines so far.
+func (s *Screen) Len() int { return len(s.lines) }
+
+// String pads the view with empty lines to the screen height and returns it.
+func (s *Screen) String() string {
+	for len(s.lines) < s.Height {
+		s.lines = append(s.lines, Fit("", s.Width))
+	}
+	return strings.Join(s.lines, "\n") + "\n"
+}
@@ -0,0 +1,91 @@
+package vikunjatest
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Fault makes matching requests slow or fail. The zero values of Method
+// and Path match every request.
+type Fault struct {
+	// Method is the HTTP method to match.
+	Method string
+	// Path is a prefix of the path below /api/v1 to match, e.g. "/tasks".
+	Path string
+	// Times is how many requests the fault applies to; 0 means every
+	// request until ClearFaults.
+	Times int
+
+	// Delay is waited before the request is answered. A client that gives
+	// up earlier sees its timeout.
+	Delay time.Duration
+	// Status answers the request with this status instead of handling it;
+	// 0 handles it normally after Delay.
+	Status int
+	// Message is the message of the JSON error payload; empty means the
+	// status text.
+	Message string
+	// Body is sent verbatim instead of the JSON payload, like the HTML
+	// error page of a proxy in front of Vikunja.
+	Body string
+}
+
+// Inject adds a fault. Faults are matched in the order they were added and
+// a request is affected by the first one that matches.
+func (s *Server) Inject(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes all injected faults.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// fault returns the fault applying to r, if any, and counts it as used.
+func (s *Server) fault(r *http.Request) *Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	path := strings.TrimPrefix(r.URL.Path, prefix)
+	for i, f := range s.faults {
+		if (f.Method != "" && f.Method != r.Method) || !strings.HasPrefix(path, f.Path) {
+			continue
+		}
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+		return f
+	}
+	return nil
+}
+
+// apply waits for the delay of f and writes its error, if it has one. It
+// reports whether the request should still be handled.
+func (f *Fault) apply(ctx context.Context, w http.ResponseWriter) bool {
+	if f.Delay > 0 && !sleep(ctx, f.Delay) {
+		return false
+	}
+	if f.Status == 0 {
+		return true
+	}
+	if f.Body != "" {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(f.Status)
+		w.Write([]byte(f.Body))
+		return false
+	}
+	msg := f.Message
+	if msg == "" {
+		msg = http.StatusText(f.Status)
+	}
+	writeError(w, httpError(f.Status, msg))
+	return false
+}
@@ -0,0 +1,481 @@
+// Package vikunjatest provides a stand-in for a Vikunja server, to exercise
+// api.ApiClient and the commands built on it end to end without a real
+// instance.
+//
+// A Server answers the part of the REST API the client uses for tasks,
+// projects, users, assignees and attachments:
+//
+//	POST   /login
+//	GET    /tasks/all
+//	GET    /tasks/{id}
+//	POST   /tasks/{id}
+//	DELETE /tasks/{id}
+//	GET    /tasks/{id}/assignees
+//	PUT    /tasks/{id}/assignees
+//	GET    /tasks/{id}/attachments
+//	PUT    /tasks/{id}/attachments
+//	GET    /projects
+//	PUT    /projects
+//	GET    /projects/{id}
+//	PUT    /projects/{id}          (creates a task, as the client sends it)
+//	PUT    /projects/{id}/tasks
+//	DELETE /projects/{id}
+//	GET    /projects/{id}/users
+//	GET    /users
+//
+// all below /api/v1, on the data of a memory.Adapter, so paging, filters
+// and error messages behave like the in-memory backend. Requests need a
+// token issued by /login or Token; errors are answered with Vikunja's
+// {"message": …} payload and task listings carry the X-Total header.
+//
+// Faults can be injected to test the unhappy paths: ExpireTokens makes
+// every request fail with 401 until the client logs in again, and Inject
+// answers matching requests with an error status, a raw body or a delay.
+package vikunjatest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"kunja/adapter/memory"
+	"kunja/api"
+)
+
+// prefix is the path of the API below the server's URL.
+const prefix = "/api/v1"
+
+// Server is a fake Vikunja server. It is safe for concurrent use.
+type Server struct {
+	*httptest.Server
+	// Backend holds the data the server answers with; tests may seed and
+	// inspect it directly.
+	Backend *memory.Adapter
+
+	mu       sync.Mutex
+	issued   int
+	tokens   map[string]bool
+	faults   []*Fault
+	requests []Request
+}
+
+// Request is a request the server received, with its path below /api/v1
+// and the status code and headers it was answered with.
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Status int
+	Header http.Header
+}
+
+// NewServer starts a server on a loopback address answering with the data
+// of backend. The caller should Close it when finished.
+func NewServer(backend *memory.Adapter) *Server {
+	s := NewUnstartedServer(backend)
+	s.Start()
+	return s
+}
+
+// NewUnstartedServer returns a server that is not started yet, so its
+// Listener can be replaced, e.g. to serve on a fixed address.
+func NewUnstartedServer(backend *memory.Adapter) *Server {
+	s := &Server{Backend: backend, tokens: map[string]bool{}}
+	s.Server = httptest.NewUnstartedServer(s.routes())
+	return s
+}
+
+// BaseURL returns the URL to configure as the client's API base URL.
+func (s *Server) BaseURL() string {
+	return s.URL + prefix
+}
+
+// Token issues a valid token without logging in.
+func (s *Server) Token() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.issued++
+	token := fmt.Sprintf("token-%d", s.issued)
+	s.tokens[token] = true
+	return token
+}
+
+// ExpireTokens invalidates every token issued so far; requests carrying
+// one are answered with 401 as for an expired JWT.
+func (s *Server) ExpireTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens = map[string]bool{}
+}
+
+// Requests returns the requests received so far, oldest first.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request{}, s.requests...)
+}
+
+// ResetRequests forgets the requests received so far.
+func (s *Server) ResetRequests() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+}
+
+/* ---- routing ---- */
+
+// handlerFunc handles an authenticated request and returns the response
+// body to encode as JSON, or an error.
+type handlerFunc func(w http.ResponseWriter, r *http.Request) (interface{}, error)
+
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+	handle := func(pattern string, h handlerFunc) {
+		method, path, _ := strings.Cut(pattern, " ")
+		mux.Handle(method+" "+prefix+path, s.serve(path != "/login", h))
+	}
+	handle("POST /login", s.login)
+	handle("GET /tasks/all", s.getAllTasks)
+	handle("GET /tasks/{id}", s.getTask)
+	handle("POST /tasks/{id}", s.updateTask)
+	handle("DELETE /tasks/{id}", s.deleteTask)
+	handle("GET /tasks/{id}/assignees", s.getTaskAssignees)
+	handle("PUT /tasks/{id}/assignees", s.assignUser)
+	handle("GET /tasks/{id}/attachments", s.getTaskAttachments)
+	handle("PUT /tasks/{id}/attachments", s.uploadAttachments)
+	handle("GET /projects", s.getAllProjects)
+	handle("PUT /projects", s.createProject)
+	handle("GET /projects/{id}", s.getProject)
+	handle("PUT /projects/{id}", s.createTask)
+	handle("PUT /projects/{id}/tasks", s.createTask)
+	handle("DELETE /projects/{id}", s.deleteProject)
+	handle("GET /projects/{id}/users", s.getProjectUsers)
+	handle("GET /users", s.getAllUsers)
+	mux.Handle("/", s.serve(false, func(w http.ResponseWriter, r *http.Request) (interface{}, error) {
+		return nil, httpError(http.StatusNotFound, "Not Found")
+	}))
+	return mux
+}
+
+// serve wraps h with request logging, fault injection and, if auth is
+// set, the token check, and writes its result.
+func (s *Server) serve(auth bool, h handlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		defer func() {
+			s.mu.Lock()
+			s.requests = append(s.requests, Request{
+				Method: r.Method,
+				Path:   strings.TrimPrefix(r.URL.Path, prefix),
+				Query:  r.URL.Query(),
+				Status: rec.status,
+				Header: rec.Header().Clone(),
+			})
+			s.mu.Unlock()
+		}()
+
+		if f := s.fault(r); f != nil {
+			if !f.apply(r.Context(), rec) {
+				return
+			}
+		}
+		if auth && !s.authorized(r) {
+			writeError(rec, httpError(http.StatusUnauthorized, "missing, malformed, expired or otherwise invalid token provided"))
+			return
+		}
+		v, err := h(rec, r)
+		if err != nil {
+			writeError(rec, err)
+			return
+		}
+		if msg, ok := v.(string); ok {
+			// Deletions answer with the JSON message the backend returns.
+			rec.Header().Set("Content-Type", "application/json")
+			rec.Write([]byte(msg))
+			return
+		}
+		writeJSON(rec, http.StatusOK, v)
+	})
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return ok && s.tokens[token]
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// httpError returns an error answered with status and Vikunja's JSON
+// error payload.
+func httpError(status int, msg string) error {
+	return &memory.Error{Status: status, Message: msg}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	var e *memory.Error
+	if !errors.As(err, &e) {
+		e = &memory.Error{Status: http.StatusInternalServerError, Message: err.Error()}
+	}
+	writeJSON(w, e.Status, map[string]string{"message": e.Message})
+}
+
+// pathID returns the {id} path value of r.
+func pathID(r *http.Request) (int, error) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		return 0, httpError(http.StatusBadRequest, "Invalid ID.")
+	}
+	return id, nil
+}
+
+// decode reads the JSON request body into v.
+func decode(r *http.Request, v interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return httpError(http.StatusBadRequest, "Invalid model provided.")
+	}
+	return nil
+}
+
+/* ---- handlers ---- */
+
+func (s *Server) login(w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	var body struct {
+		Username     string `json:"username"`
+		Password     string `json:"password"`
+		TOTPPasscode string `json:"totp_passcode"`
+	}
+	if err := decode(r, &body); err != nil {
+		return nil, err
+	}
+	if _, err := s.Backend.Login(r.Context(), body.Username, body.Password, body.TOTPPasscode); err != nil {
+		return nil, err
+	}
+	return map[string]string{"token": s.Token()}, nil
+}
+
+// getAllTasks reports the number of matching tasks on all pages in the
+// X-Total header, and the number of pages like Vikunja does.
+func (s *Server) getAllTasks(w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	q := r.URL.Query()
+	params := api.GetAllTasksParams{
+		S:                  q.Get("s"),
+		SortBy:             q.Get("sort_by"),
+		OrderBy:            q.Get("order_by"),
+		FilterBy:           q.Get("filter_by"),
+		FilterValue:        q.Get("filter_value"),
+		FilterComparator:   q.Get("filter_comparator"),
+		FilterConcat:       q.Get("filter_concat"),
+		FilterIncludeNulls: q.Get("filter_include_nulls"),
+		Filter:             q.Get("filter"),
+	}
+	params.Page, _ = strconv.Atoi(q.Get("page"))
+	params.PerPage, _ = strconv.Atoi(q.Get("per_page"))
+	tasks, total, err := s.Backend.QueryTasks(r.Context(), params)
+	if err != nil {
+		return nil, err
+	}
+	perPage := params.PerPage
+	if perPage <= 0 || perPage > 50 {
+		perPage = 50
+	}
+	w.Header().Set("X-Total", strconv.Itoa(total))
+	w.Header().Set("X-Pagination-Total-Pages", strconv.Itoa((total+perPage-1)/perPage))
+	w.Header().Set("X-Pagination-Result-Count", strconv.Itoa(len(tasks)))
+	return tasks, nil
+}
+
+func (s *Server) getTask(w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, err
+	}
+	return s.Backend.GetTask(r.Context(), id)
+}
+
+func (s *Server) updateTask(w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, err
+	}
+	var task api.Task
+	if err := decode(r, &task); err != nil {
+		return nil, err
+	}
+	return s.Backend.UpdateTask(r.Context(), id, task)
+}
+
+func (s *Server) deleteTask(w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, err
+	}
+	return s.Backend.DeleteTask(r.Context(), id)
+}
+
+func (s *Server) getTaskAssignees(w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, err
+	}
+	return s.Backend.GetTaskAssignees(r.Context(), id)
+}
+
+func (s *Server) assignUser(w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, err
+	}
+	var body struct {
+		UserID int `json:"user_id"`
+	}
+	if err := decode(r, &body); err != nil {
+		return nil, err
+	}
+	return s.Backend.AssignUserToTask(r.Context(), id, body.UserID)
+}
+
+func (s *Server) getTaskAttachments(w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, err
+	}
+	return s.Backend.GetTaskAttachments(r.Context(), id)
+}
+
+// uploadAttachments stores the "files" parts of a multipart body and
+// answers with Vikunja's per-file result.
+func (s *Server) uploadAttachments(w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, err
+	}
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return nil, httpError(http.StatusBadRequest, "No file provided.")
+	}
+	result := struct {
+		Success []api.TaskAttachment `json:"success"`
+		Errors  []struct{}           `json:"errors"`
+	}{Success: []api.TaskAttachment{}, Errors: []struct{}{}}
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, httpError(http.StatusBadRequest, err.Error())
+		}
+		if part.FormName() != "files" {
+			continue
+		}
+		a, err := s.Backend.UploadTaskAttachment(r.Context(), id, part.FileName(), part)
+		if err != nil {
+			return nil, err
+		}
+		result.Success = append(result.Success, a)
+	}
+	return result, nil
+}
+
+func (s *Server) getAllProjects(w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	return s.Backend.GetAllProjects(r.Context())
+}
+
+func (s *Server) createProject(w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	var p api.Project
+	if err := decode(r, &p); err != nil {
+		return nil, err
+	}
+	return s.Backend.CreateProject(r.Context(), p)
+}
+
+func (s *Server) getProject(w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, err
+	}
+	return s.Backend.GetProject(r.Context(), id)
+}
+
+func (s *Server) createTask(w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, err
+	}
+	var task api.Task
+	if err := decode(r, &task); err != nil {
+		return nil, err
+	}
+	return s.Backend.CreateTask(r.Context(), id, task)
+}
+
+func (s *Server) deleteProject(w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, err
+	}
+	return s.Backend.DeleteProject(r.Context(), id)
+}
+
+func (s *Server) getProjectUsers(w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, err
+	}
+	return s.Backend.GetProjectUsers(r.Context(), id)
+}
+
+// getAllUsers lists the users whose username or name contains the s
+// parameter.
+func (s *Server) getAllUsers(w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	users, err := s.Backend.GetAllUsers(r.Context())
+	if err != nil {
+		return nil, err
+	}
+	search := strings.ToLower(r.URL.Query().Get("s"))
+	matched := []api.User{}
+	for _, u := range users {
+		if strings.Contains(strings.ToLower(u.Username), search) || strings.Contains(strings.ToLower(u.Name), search) {
+			matched = append(matched, u)
+		}
+	}
+	return matched, nil
+}
+
+// sleep waits for d or until ctx is done, and reports whether it waited
+// the full duration.
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,50 @@
+// Command fakevikunja serves the demo data of the in-memory backend through
+// the fake Vikunja server of package vikunjatest, to point kunja or the MCP
+// inspector at by hand:
+//
+//	go run ./scripts/fakevikunja -listen 127.0.0.1:8089
+//
+// The automated checks against the fake server are the tests in api and
+// cmd; scripts/test-integration.sh runs them.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+
+	"kunja/adapter/memory"
+	"kunja/internal/vikunjatest"
+)
+
+var listen = flag.String("listen", "127.0.0.1:8089", "serve on this `address`")
+
+func main() {
+	flag.Parse()
+	if err := serveDemo(*listen); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+// serveDemo serves the demo data on addr until interrupted.
+func serveDemo(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	srv := vikunjatest.NewUnstartedServer(memory.Demo())
+	srv.Listener.Close()
+	srv.Listener = l
+	srv.Start()
+	defer srv.Close()
+	fmt.Printf("Serving the demo data on %s (log in as demo/demo or use token %s)\n", srv.BaseURL(), srv.Token())
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	<-ctx.Done()
+	return nil
+}
@@ -0,0 +1,14 @@
+#!/usr/bin/env sh
+# ---------------------------------------------------------------------------
+# Run the tests against the fake Vikunja server (internal/vikunjatest): the
+# API client tests and the command tests, which build and run the kunja
+# binary. Requires: Go. Extra arguments go to `go test`, e.g. -v or
+# -run Refresh.
+# ---------------------------------------------------------------------------
+
+set -eu
+
+# Move to repository root (directory containing this script’s parent).
+cd "$(dirname "$0")"/..
+
+go test -count=1 ./api ./cmd "$@"